@@ -0,0 +1,84 @@
+// Package audit records who performed privileged operations and how they
+// turned out. It's append-only and kept apart from anything game-scoped so
+// entries survive the games they refer to.
+package audit
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeDenied  = "denied"
+	OutcomeFailed  = "failed"
+)
+
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Actor      string    `json:"actor"`  // "admin" or a player name
+	Action     string    `json:"action"` // e.g. "POST /admin/import"
+	Target     string    `json:"target,omitempty"`
+	Outcome    string    `json:"outcome"`
+	Status     int       `json:"status"`
+	RemoteAddr string    `json:"remoteAddr"`
+	Suppressed int       `json:"suppressed,omitempty"` // like denials since the last entry that weren't written
+}
+
+// Writer appends entries. Implementations might ship them to CloudWatch or
+// another log service instead of keeping them.
+type Writer interface {
+	Write(Entry) error
+}
+
+// Reader returns entries from the half-open range [from, to), oldest first.
+// A zero from or to leaves that end unbounded.
+type Reader interface {
+	Entries(from, to time.Time) ([]Entry, error)
+}
+
+// DefaultMemoryLogSize is how many entries NewMemoryLog keeps.
+const DefaultMemoryLogSize = 10000
+
+// MemoryLog is an in-process Writer and Reader that keeps the latest Max
+// entries, dropping the oldest.
+type MemoryLog struct {
+	Max int
+
+	mu      sync.Mutex
+	entries []Entry
+	next    int // where the next entry goes once entries is full
+}
+
+func NewMemoryLog() *MemoryLog {
+	return &MemoryLog{Max: DefaultMemoryLogSize}
+}
+
+func (m *MemoryLog) Write(e Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.entries) < m.Max {
+		m.entries = append(m.entries, e)
+		return nil
+	}
+	m.entries[m.next] = e
+	m.next = (m.next + 1) % len(m.entries)
+	return nil
+}
+
+func (m *MemoryLog) Entries(from, to time.Time) ([]Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entries := []Entry{}
+	for i := range m.entries {
+		e := m.entries[(m.next+i)%len(m.entries)]
+		if !from.IsZero() && e.Time.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !e.Time.Before(to) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
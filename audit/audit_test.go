@@ -0,0 +1,46 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryLog(t *testing.T) {
+	start := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	log := NewMemoryLog()
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, log.Write(Entry{Time: start.Add(time.Hour * time.Duration(i)), Action: "import"}))
+	}
+
+	entries, err := log.Entries(time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 4)
+
+	entries, err = log.Entries(start.Add(time.Hour), start.Add(time.Hour*3))
+	assert.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Time: start.Add(time.Hour), Action: "import"},
+		{Time: start.Add(time.Hour * 2), Action: "import"},
+	}, entries)
+
+	entries, err = log.Entries(start.Add(time.Hour*4), time.Time{})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestMemoryLogDropsOldest(t *testing.T) {
+	start := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	log := &MemoryLog{Max: 3}
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, log.Write(Entry{Time: start.Add(time.Minute * time.Duration(i))}))
+	}
+	entries, err := log.Entries(time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Time: start.Add(time.Minute * 2)},
+		{Time: start.Add(time.Minute * 3)},
+		{Time: start.Add(time.Minute * 4)},
+	}, entries)
+}
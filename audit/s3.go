@@ -0,0 +1,94 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// keyTime is fixed width so keys sort in the order entries were written.
+const keyTime = "20060102T150405.000000000Z"
+
+// S3Log is a Writer and Reader that keeps each entry as its own object
+// under Prefix in Bucket, so entries outlive the process.
+type S3Log struct {
+	Client s3iface.S3API
+	Bucket string
+	Prefix string
+
+	seq uint64 // tells apart entries written in the same instant
+}
+
+func (l *S3Log) Write(e Entry) error {
+	j, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("%s-%06d.json", l.key(e.Time), atomic.AddUint64(&l.seq, 1)%1000000)
+	_, err = l.Client.PutObjectWithContext(context.Background(), &s3.PutObjectInput{
+		Bucket:      aws.String(l.Bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(j),
+		ContentType: aws.String("application/json"),
+	})
+	return err
+}
+
+func (l *S3Log) Entries(from, to time.Time) ([]Entry, error) {
+	ctx := context.Background()
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(l.Bucket),
+		Prefix: aws.String(l.Prefix),
+	}
+	if !from.IsZero() {
+		input.StartAfter = aws.String(l.key(from))
+	}
+	var keys []string
+	err := l.Client.ListObjectsV2PagesWithContext(ctx, input, func(page *s3.ListObjectsV2Output, last bool) bool {
+		for _, object := range page.Contents {
+			key := aws.StringValue(object.Key)
+			if !to.IsZero() && key >= l.key(to) {
+				return false
+			}
+			keys = append(keys, key)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries := []Entry{}
+	for _, key := range keys {
+		e, err := l.get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func (l *S3Log) key(t time.Time) string {
+	return l.Prefix + t.UTC().Format(keyTime)
+}
+
+func (l *S3Log) get(ctx context.Context, key string) (Entry, error) {
+	var e Entry
+	resp, err := l.Client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(l.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return e, err
+	}
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&e)
+	return e, err
+}
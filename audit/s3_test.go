@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"bytes"
+	"io/ioutil"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/stretchr/testify/assert"
+)
+
+// bucket is an in-memory S3 bucket that lists one object per page.
+type bucket struct {
+	s3iface.S3API
+	objects map[string][]byte
+}
+
+func (b *bucket) PutObjectWithContext(ctx aws.Context, input *s3.PutObjectInput, opts ...request.Option) (*s3.PutObjectOutput, error) {
+	body, err := ioutil.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	b.objects[aws.StringValue(input.Key)] = body
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (b *bucket) GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	return &s3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(b.objects[aws.StringValue(input.Key)]))}, nil
+}
+
+func (b *bucket) ListObjectsV2PagesWithContext(ctx aws.Context, input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool, opts ...request.Option) error {
+	var keys []string
+	for key := range b.objects {
+		if key > aws.StringValue(input.StartAfter) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		page := &s3.ListObjectsV2Output{Contents: []*s3.Object{{Key: aws.String(key)}}}
+		if !fn(page, i == len(keys)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+func TestS3Log(t *testing.T) {
+	start := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	log := &S3Log{Client: &bucket{objects: make(map[string][]byte)}, Bucket: "b", Prefix: "audit/"}
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, log.Write(Entry{Time: start.Add(time.Hour * time.Duration(i)), Action: "import"}))
+	}
+	assert.NoError(t, log.Write(Entry{Time: start.Add(time.Hour), Action: "delete"}), "same instant")
+
+	entries, err := log.Entries(time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 5)
+
+	entries, err = log.Entries(start.Add(time.Hour), start.Add(time.Hour*3))
+	assert.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Time: start.Add(time.Hour), Action: "import"},
+		{Time: start.Add(time.Hour), Action: "delete"},
+		{Time: start.Add(time.Hour * 2), Action: "import"},
+	}, entries)
+
+	entries, err = log.Entries(start.Add(time.Hour*4), time.Time{})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner(t *testing.T) {
+	now := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	signer := &Signer{Key: []byte("secret"), TTL: time.Hour, Now: clock}
+
+	token, err := signer.Issue(7, "al")
+	assert.NoError(t, err)
+	claims, err := signer.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, &Claims{GameID: 7, Player: "al", Expires: now.Add(time.Hour).Unix()}, claims)
+
+	// tampered payload, keeping the original signature
+	parts := strings.Split(token, ".")
+	forged, err := (&Signer{Key: []byte("other"), TTL: time.Hour, Now: clock}).Issue(7, "bob")
+	assert.NoError(t, err)
+	_, err = signer.Verify(strings.Split(forged, ".")[0] + "." + parts[1])
+	assert.Equal(t, ErrInvalidToken, err)
+	_, err = signer.Verify(forged)
+	assert.Equal(t, ErrInvalidToken, err)
+	_, err = signer.Verify("garbage")
+	assert.Equal(t, ErrInvalidToken, err)
+
+	now = now.Add(time.Hour)
+	_, err = signer.Verify(token)
+	assert.Equal(t, ErrExpiredToken, err)
+}
+
+func TestSignerRotation(t *testing.T) {
+	now := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	old := &Signer{Key: []byte("old"), TTL: time.Hour * 24, Now: clock}
+	token, err := old.Issue(7, "al")
+	assert.NoError(t, err)
+
+	rotated := &Signer{
+		Key:           []byte("new"),
+		PreviousKey:   []byte("old"),
+		PreviousUntil: now.Add(time.Hour),
+		TTL:           time.Hour * 24,
+		Now:           clock,
+	}
+	claims, err := rotated.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "al", claims.Player)
+
+	now = now.Add(time.Hour)
+	_, err = rotated.Verify(token)
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestSignerIssueClaims(t *testing.T) {
+	now := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	signer := &Signer{Key: []byte("secret"), TTL: time.Hour, Now: func() time.Time { return now }}
+	claims := Claims{GameID: 7, Player: "al", Expires: now.Add(time.Minute).Unix(), Generation: 2}
+	token, err := signer.IssueClaims(claims)
+	assert.NoError(t, err)
+	verified, err := signer.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, &claims, verified)
+	now = now.Add(time.Minute)
+	_, err = signer.Verify(token)
+	assert.Equal(t, ErrExpiredToken, err, "its own expiry, not TTL")
+}
@@ -0,0 +1,76 @@
+// Command cahimport converts a card set in the common CAH JSON format into
+// setup and punchline decks.
+//
+//	cahimport -rating PG-13 -out ./decks cah.json
+//	cahimport -rating R -s3 mypack cah.json
+//
+// Skipped cards are listed on stderr.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/stinkyfingers/differencebetween/api/game"
+)
+
+func main() {
+	rating := flag.String("rating", "R", "rating applied to every imported card")
+	out := flag.String("out", ".", "directory to write setups.csv and punchlines.csv to")
+	s3Name := flag.String("s3", "", "write the decks to the card bucket under decks/{name}/ instead of -out")
+	flag.Parse()
+
+	var in io.Reader = os.Stdin
+	if flag.NArg() > 0 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fail(err)
+		}
+		defer f.Close()
+		in = f
+	}
+	imported, err := game.ImportCAH(in, *rating)
+	if err != nil {
+		fail(err)
+	}
+	for _, skipped := range imported.Skipped {
+		fmt.Fprintf(os.Stderr, "skipped (%s): %s\n", skipped.Reason, skipped.Text)
+	}
+
+	decks := map[string][]game.RatedCard{
+		"setups.csv":     imported.Setups,
+		"punchlines.csv": imported.Punchlines,
+	}
+	for name, cards := range decks {
+		if *s3Name != "" {
+			err = game.PutDeck(context.Background(), "decks/"+*s3Name+"/"+name, cards)
+		} else {
+			err = writeDeck(filepath.Join(*out, name), cards)
+		}
+		if err != nil {
+			fail(err)
+		}
+	}
+	fmt.Printf("imported %d setups, %d punchlines, skipped %d\n", len(imported.Setups), len(imported.Punchlines), len(imported.Skipped))
+}
+
+func writeDeck(path string, cards []game.RatedCard) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = game.WriteCSVDeck(f, cards)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
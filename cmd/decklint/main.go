@@ -0,0 +1,122 @@
+// Command decklint checks deck files with the same parser the server uses and
+// exits non-zero if any problems are found, so decks can be checked in CI.
+//
+//	decklint [-fix] setups.csv https://example.com/punchlines.csv s3://bucket/key
+//
+// With -fix, a normalized and deduplicated copy of each deck is written next
+// to local files (or to the working directory for remote ones) as
+// <name>.fixed.csv.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stinkyfingers/differencebetween/api/game"
+)
+
+const (
+	exitOK       = 0
+	exitProblems = 1
+	exitError    = 2
+)
+
+func main() {
+	fix := flag.Bool("fix", false, "write a normalized, deduplicated copy of each deck")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: decklint [-fix] deck...")
+		os.Exit(exitError)
+	}
+
+	status := exitOK
+	for _, location := range flag.Args() {
+		report, err := lint(location)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", location, err)
+			status = exitError
+			continue
+		}
+		for _, problem := range report.Problems {
+			fmt.Printf("%s:%s\n", location, problem)
+		}
+		fmt.Printf("%s: %s, %d problems\n", location, report.Summary(), len(report.Problems))
+		if len(report.Problems) > 0 && status == exitOK {
+			status = exitProblems
+		}
+		if *fix {
+			err = writeFixed(location, report)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", location, err)
+				status = exitError
+			}
+		}
+	}
+	os.Exit(status)
+}
+
+func lint(location string) (*game.DeckReport, error) {
+	r, err := open(location)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return game.LintDeck(r)
+}
+
+func open(location string) (io.ReadCloser, error) {
+	switch {
+	case strings.HasPrefix(location, "http://"), strings.HasPrefix(location, "https://"):
+		resp, err := http.Get(location)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return resp.Body, nil
+	case strings.HasPrefix(location, "s3://"):
+		parts := strings.SplitN(strings.TrimPrefix(location, "s3://"), "/", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("expected s3://bucket/key")
+		}
+		sess, err := session.NewSession()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := s3.New(sess).GetObject(&s3.GetObjectInput{
+			Bucket: aws.String(parts[0]),
+			Key:    aws.String(parts[1]),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp.Body, nil
+	}
+	return os.Open(location)
+}
+
+func writeFixed(location string, report *game.DeckReport) error {
+	name := strings.TrimSuffix(filepath.Base(location), filepath.Ext(location)) + ".fixed.csv"
+	if !strings.Contains(location, "://") {
+		name = filepath.Join(filepath.Dir(location), name)
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	err = game.WriteCSVDeck(f, report.Fixed())
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,290 @@
+// Package config holds the server's settings, read from the environment and
+// optionally overridden by a JSON file named by CONFIG_FILE. Some settings
+// can be reloaded while running; read them through Current each time rather
+// than keeping a copy.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Config struct {
+	// Require a restart to change.
+	Port          string
+	DiffEnv       string
+	SetupsURL     string
+	PunchlinesURL string
+	GCSBucket     string
+	AuditBucket   string // keeps the audit log in S3 rather than in memory
+	PublicURL     string // where clients reach the API, for links in webhooks
+	GameIDSpace   int    // game ids run from 0 to GameIDSpace-1
+
+	// Reloadable.
+	AdminSecret         string `config:"secret"`
+	TokenSecret         string `config:"secret"`
+	TokenPreviousSecret string `config:"secret"`
+	TokenPreviousUntil  time.Time
+	MaxActions          int           // per player per ActionWindow
+	ActionWindow        time.Duration // for MaxActions
+	Features            map[string]bool
+	TombstoneWindow     time.Duration // how long a deleted game can be restored
+	InactiveTimeout     time.Duration // unseen players are dropped after this, 0 never
+	GameTTL             time.Duration // games are removed this long after creation
+	HistoryTTL          time.Duration // finished games' summaries are kept this long
+	HistorySize         int           // the most finished games' summaries kept, 0 for none
+	MaxRounds           int           // the most rounds a new game may have
+	SetupSkips          int           // setup redraws allowed per game, 0 for none
+	WebhookSecret       string        `config:"secret"`
+	FetchAllowedHosts   []string      // hosts player-given urls may reach even if they aren't public
+}
+
+// restartFields can't take effect without a restart, so Reload leaves them
+// as they were.
+var restartFields = map[string]bool{
+	"Port":          true,
+	"DiffEnv":       true,
+	"SetupsURL":     true,
+	"PunchlinesURL": true,
+	"GCSBucket":     true,
+	"AuditBucket":   true,
+	"PublicURL":     true,
+	"GameIDSpace":   true,
+}
+
+var (
+	current  atomic.Value // *Config
+	reloadMu sync.Mutex
+)
+
+func init() {
+	c, err := Load()
+	if err != nil {
+		log.Fatal("config: ", err)
+	}
+	current.Store(c)
+}
+
+// Current returns the active configuration. It must not be modified.
+func Current() *Config {
+	return current.Load().(*Config)
+}
+
+// Set replaces the active configuration wholesale, e.g. in tests.
+func Set(c *Config) {
+	current.Store(c)
+}
+
+// Load reads and validates the configuration without activating it.
+func Load() (*Config, error) {
+	values := make(map[string]string)
+	for _, key := range []string{
+		"PORT", "DIFF_ENV", "SETUPS_URL", "PUNCHLINES_URL", "GCS_BUCKET", "AUDIT_BUCKET", "PUBLIC_URL", "GAME_ID_SPACE",
+		"ADMIN_SECRET", "TOKEN_SECRET", "TOKEN_PREVIOUS_SECRET", "TOKEN_PREVIOUS_UNTIL",
+		"MAX_ACTIONS", "ACTION_WINDOW", "FEATURES", "TOMBSTONE_WINDOW", "WEBHOOK_SECRET",
+		"INACTIVE_TIMEOUT", "GAME_TTL", "MAX_ROUNDS", "SETUP_SKIPS",
+		"HISTORY_TTL", "HISTORY_SIZE", "FETCH_ALLOWED_HOSTS",
+	} {
+		if v, ok := os.LookupEnv(key); ok {
+			values[key] = v
+		}
+	}
+	if file := os.Getenv("CONFIG_FILE"); file != "" {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var overrides map[string]string
+		err = json.Unmarshal(b, &overrides)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		for k, v := range overrides {
+			values[k] = v
+		}
+	}
+	return parse(values)
+}
+
+func parse(values map[string]string) (*Config, error) {
+	c := &Config{
+		Port:                "7777",
+		DiffEnv:             values["DIFF_ENV"],
+		SetupsURL:           values["SETUPS_URL"],
+		PunchlinesURL:       values["PUNCHLINES_URL"],
+		GCSBucket:           values["GCS_BUCKET"],
+		AuditBucket:         values["AUDIT_BUCKET"],
+		PublicURL:           strings.TrimSuffix(values["PUBLIC_URL"], "/"),
+		GameIDSpace:         1000000,
+		AdminSecret:         values["ADMIN_SECRET"],
+		TokenSecret:         values["TOKEN_SECRET"],
+		TokenPreviousSecret: values["TOKEN_PREVIOUS_SECRET"],
+		WebhookSecret:       values["WEBHOOK_SECRET"],
+		MaxActions:          10,
+		ActionWindow:        time.Second * 10,
+		Features:            make(map[string]bool),
+		TombstoneWindow:     time.Minute * 10,
+		InactiveTimeout:     time.Minute * 5,
+		GameTTL:             time.Hour * 12,
+		HistoryTTL:          time.Hour * 2,
+		HistorySize:         1000,
+		MaxRounds:           50,
+		SetupSkips:          3,
+	}
+	if v := values["PORT"]; v != "" {
+		c.Port = v
+	}
+	var err error
+	if v := values["TOKEN_PREVIOUS_UNTIL"]; v != "" {
+		c.TokenPreviousUntil, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("TOKEN_PREVIOUS_UNTIL must be an RFC3339 time: %v", err)
+		}
+	}
+	if c.TokenPreviousSecret != "" && c.TokenPreviousUntil.IsZero() {
+		return nil, errors.New("TOKEN_PREVIOUS_SECRET requires TOKEN_PREVIOUS_UNTIL")
+	}
+	if v := values["GAME_ID_SPACE"]; v != "" {
+		c.GameIDSpace, err = strconv.Atoi(v)
+		if err != nil || c.GameIDSpace < 1 {
+			return nil, errors.New("GAME_ID_SPACE must be a positive integer")
+		}
+	}
+	if v := values["MAX_ACTIONS"]; v != "" {
+		c.MaxActions, err = strconv.Atoi(v)
+		if err != nil || c.MaxActions < 1 {
+			return nil, errors.New("MAX_ACTIONS must be a positive integer")
+		}
+	}
+	if v := values["ACTION_WINDOW"]; v != "" {
+		c.ActionWindow, err = time.ParseDuration(v)
+		if err != nil || c.ActionWindow <= 0 {
+			return nil, errors.New("ACTION_WINDOW must be a positive duration, e.g. 10s")
+		}
+	}
+	if v := values["TOMBSTONE_WINDOW"]; v != "" {
+		c.TombstoneWindow, err = time.ParseDuration(v)
+		if err != nil || c.TombstoneWindow < 0 {
+			return nil, errors.New("TOMBSTONE_WINDOW must be a duration, e.g. 10m")
+		}
+	}
+	if v := values["INACTIVE_TIMEOUT"]; v != "" {
+		c.InactiveTimeout, err = time.ParseDuration(v)
+		if err != nil || c.InactiveTimeout < 0 {
+			return nil, errors.New("INACTIVE_TIMEOUT must be a duration, e.g. 5m, or 0 to keep players indefinitely")
+		}
+	}
+	if v := values["GAME_TTL"]; v != "" {
+		c.GameTTL, err = time.ParseDuration(v)
+		if err != nil || c.GameTTL <= 0 {
+			return nil, errors.New("GAME_TTL must be a positive duration, e.g. 12h")
+		}
+	}
+	if v := values["HISTORY_TTL"]; v != "" {
+		c.HistoryTTL, err = time.ParseDuration(v)
+		if err != nil || c.HistoryTTL <= 0 {
+			return nil, errors.New("HISTORY_TTL must be a positive duration, e.g. 2h")
+		}
+	}
+	if v := values["HISTORY_SIZE"]; v != "" {
+		c.HistorySize, err = strconv.Atoi(v)
+		if err != nil || c.HistorySize < 0 {
+			return nil, errors.New("HISTORY_SIZE must be a number, or 0 to keep no history")
+		}
+	}
+	if v := values["MAX_ROUNDS"]; v != "" {
+		c.MaxRounds, err = strconv.Atoi(v)
+		if err != nil || c.MaxRounds < 1 {
+			return nil, errors.New("MAX_ROUNDS must be a positive integer")
+		}
+	}
+	if v := values["SETUP_SKIPS"]; v != "" {
+		c.SetupSkips, err = strconv.Atoi(v)
+		if err != nil || c.SetupSkips < 0 {
+			return nil, errors.New("SETUP_SKIPS must be a number, or 0 to turn skipping off")
+		}
+	}
+	// a comma separated list of enabled feature flags
+	for _, name := range strings.Split(values["FEATURES"], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			c.Features[name] = true
+		}
+	}
+	// a comma separated list of hosts, e.g. an internal deck server
+	for _, host := range strings.Split(values["FETCH_ALLOWED_HOSTS"], ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			c.FetchAllowedHosts = append(c.FetchAllowedHosts, host)
+		}
+	}
+	return c, nil
+}
+
+// Change describes a setting that differs between two configurations.
+// Secret values are never included.
+type Change struct {
+	Field           string `json:"field"`
+	Old             string `json:"old,omitempty"`
+	New             string `json:"new,omitempty"`
+	RequiresRestart bool   `json:"requiresRestart"`
+}
+
+func (c Change) String() string {
+	s := c.Field + " changed"
+	if c.Old != "" || c.New != "" {
+		s = fmt.Sprintf("%s: %q -> %q", c.Field, c.Old, c.New)
+	}
+	if c.RequiresRestart {
+		s += " (requires restart)"
+	}
+	return s
+}
+
+// Reload loads the configuration and activates its reloadable settings.
+// Settings that need a restart keep their current values and are reported
+// as changes with RequiresRestart set. On error nothing changes.
+func Reload() ([]Change, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	next, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	old := Current()
+	changes := diff(old, next)
+	for _, change := range changes {
+		if change.RequiresRestart {
+			reflect.ValueOf(next).Elem().FieldByName(change.Field).Set(reflect.ValueOf(old).Elem().FieldByName(change.Field))
+		}
+	}
+	current.Store(next)
+	return changes, nil
+}
+
+func diff(old, next *Config) []Change {
+	var changes []Change
+	o, n := reflect.ValueOf(old).Elem(), reflect.ValueOf(next).Elem()
+	t := o.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if reflect.DeepEqual(o.Field(i).Interface(), n.Field(i).Interface()) {
+			continue
+		}
+		change := Change{Field: field.Name, RequiresRestart: restartFields[field.Name]}
+		if field.Tag.Get("config") != "secret" {
+			change.Old = fmt.Sprint(o.Field(i).Interface())
+			change.New = fmt.Sprint(n.Field(i).Interface())
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	c, err := parse(map[string]string{
+		"PORT":                  "8080",
+		"TOKEN_SECRET":          "new",
+		"TOKEN_PREVIOUS_SECRET": "old",
+		"TOKEN_PREVIOUS_UNTIL":  "2020-07-01T12:00:00Z",
+		"ACTION_WINDOW":         "5s",
+		"INACTIVE_TIMEOUT":      "0",
+		"GAME_TTL":              "2h",
+		"GAME_ID_SPACE":         "500",
+		"MAX_ROUNDS":            "12",
+		"SETUP_SKIPS":           "0",
+		"HISTORY_TTL":           "30m",
+		"HISTORY_SIZE":          "0",
+		"FETCH_ALLOWED_HOSTS":   "decks.internal, 10.0.0.5",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "8080", c.Port)
+	assert.Equal(t, time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC), c.TokenPreviousUntil)
+	assert.Equal(t, 10, c.MaxActions)
+	assert.Equal(t, time.Second*5, c.ActionWindow)
+	assert.Equal(t, time.Duration(0), c.InactiveTimeout)
+	assert.Equal(t, time.Hour*2, c.GameTTL)
+	assert.Equal(t, 500, c.GameIDSpace)
+	assert.Equal(t, 12, c.MaxRounds)
+	assert.Equal(t, 0, c.SetupSkips)
+	assert.Equal(t, time.Minute*30, c.HistoryTTL)
+	assert.Equal(t, 0, c.HistorySize)
+	assert.Equal(t, []string{"decks.internal", "10.0.0.5"}, c.FetchAllowedHosts)
+
+	for _, values := range []map[string]string{
+		{"TOKEN_PREVIOUS_SECRET": "old"},
+		{"TOKEN_PREVIOUS_UNTIL": "tomorrow"},
+		{"MAX_ACTIONS": "0"},
+		{"ACTION_WINDOW": "10"},
+		{"INACTIVE_TIMEOUT": "-1m"},
+		{"GAME_TTL": "0"},
+		{"GAME_ID_SPACE": "0"},
+		{"MAX_ROUNDS": "-1"},
+		{"SETUP_SKIPS": "-1"},
+		{"HISTORY_TTL": "0"},
+		{"HISTORY_SIZE": "-1"},
+	} {
+		_, err = parse(values)
+		assert.Error(t, err, values)
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "config.json")
+	os.Setenv("CONFIG_FILE", file)
+	defer os.Unsetenv("CONFIG_FILE")
+	defer Set(Current())
+
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{"MAX_ACTIONS": "5"}`), 0600))
+	_, err = Reload()
+	assert.NoError(t, err)
+	assert.Equal(t, 5, Current().MaxActions)
+
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{"MAX_ACTIONS": "20", "ADMIN_SECRET": "shh", "PORT": "1"}`), 0600))
+	changes, err := Reload()
+	assert.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Field: "Port", Old: "7777", New: "1", RequiresRestart: true},
+		{Field: "AdminSecret"},
+		{Field: "MaxActions", Old: "5", New: "20"},
+	}, changes)
+	assert.Equal(t, "7777", Current().Port)
+	assert.Equal(t, "shh", Current().AdminSecret)
+	assert.Equal(t, 20, Current().MaxActions)
+
+	// invalid files leave the config alone
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{"MAX_ACTIONS": "-1"}`), 0600))
+	_, err = Reload()
+	assert.Error(t, err)
+	assert.Equal(t, 20, Current().MaxActions)
+}
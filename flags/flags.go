@@ -0,0 +1,64 @@
+// Package flags gates game mechanics that can ship dark. A flag is enabled
+// for the whole server by listing it in the FEATURES setting; games can then
+// opt into it individually.
+package flags
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+)
+
+const (
+	CzarMode       = "czar_mode"
+	Chaos          = "chaos"
+	AudienceVoting = "audience_voting"
+)
+
+// Known lists every flag, enabled or not.
+var Known = []string{CzarMode, Chaos, AudienceVoting}
+
+var (
+	ErrUnknown  = errors.New("unknown feature")
+	ErrDisabled = errors.New("feature is not enabled on this server")
+)
+
+// Enabled reports whether name is enabled server-wide.
+func Enabled(name string) bool {
+	return config.Current().Features[name]
+}
+
+// Active returns the enabled flags, sorted.
+func Active() []string {
+	var active []string
+	for _, name := range Known {
+		if Enabled(name) {
+			active = append(active, name)
+		}
+	}
+	sort.Strings(active)
+	return active
+}
+
+// All maps every known flag to whether it's enabled.
+func All() map[string]bool {
+	all := make(map[string]bool)
+	for _, name := range Known {
+		all[name] = Enabled(name)
+	}
+	return all
+}
+
+// Check returns ErrUnknown or ErrDisabled unless a game may use name.
+func Check(name string) error {
+	for _, known := range Known {
+		if name == known {
+			if !Enabled(name) {
+				return ErrDisabled
+			}
+			return nil
+		}
+	}
+	return ErrUnknown
+}
@@ -0,0 +1,22 @@
+package flags
+
+import (
+	"testing"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlags(t *testing.T) {
+	defer config.Set(config.Current())
+	config.Set(&config.Config{Features: map[string]bool{AudienceVoting: true, CzarMode: true, "retired": true}})
+
+	assert.True(t, Enabled(CzarMode))
+	assert.False(t, Enabled(Chaos))
+	assert.Equal(t, []string{AudienceVoting, CzarMode}, Active())
+	assert.Equal(t, map[string]bool{CzarMode: true, Chaos: false, AudienceVoting: true}, All())
+
+	assert.NoError(t, Check(CzarMode))
+	assert.Equal(t, ErrDisabled, Check(Chaos))
+	assert.Equal(t, ErrUnknown, Check("retired"))
+}
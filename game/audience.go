@@ -0,0 +1,84 @@
+package game
+
+import "errors"
+
+var ErrInvalidAudienceWeight = errors.New("audience weight must be more than 0 and at most 1")
+
+// WithAudienceVoting lets spectators vote for one card a round, each vote
+// counting weight of a player's vote towards the winner. With pooled, the
+// audience's votes are shared out so that together they count weight of a
+// player's vote, however many spectators vote. Check weight with
+// ValidateAudienceWeight. Judge games ignore the audience.
+func WithAudienceVoting(weight float64, pooled bool) Option {
+	return func(g *Game) {
+		g.AudienceWeight, g.AudiencePooled = weight, pooled
+	}
+}
+
+func ValidateAudienceWeight(weight float64) error {
+	if !(weight > 0 && weight <= 1) {
+		return ErrInvalidAudienceWeight
+	}
+	return nil
+}
+
+// audienceVoting reports whether spectators can vote.
+func (g *Game) audienceVoting() bool {
+	return g.AudienceWeight > 0 && g.VotingMode != VotingJudge
+}
+
+// audienceVote records spectatorName's vote for card, replacing any they'd
+// cast this round. Rounds don't wait for the audience, so it never settles
+// one. The caller must hold the game's lock and have checked the phase.
+func (g *Game) audienceVote(spectatorName string, cards []Card) error {
+	if !g.audienceVoting() {
+		return ErrSpectator
+	}
+	if len(cards) != 1 {
+		return ErrVoteCount
+	}
+	card := NormalizeCard(string(cards[0]))
+	round := g.Rounds[g.RoundsRemaining-1]
+	if vote, ok := round.AudienceVotes[spectatorName]; ok && vote == card {
+		return nil
+	}
+	err := g.allowAction(spectatorName)
+	if err != nil {
+		return err
+	}
+	if !round.onBallot(card) {
+		return ErrNotOnBallot
+	}
+	if round.AudienceVotes == nil {
+		round.AudienceVotes = make(map[string]Card)
+	}
+	round.AudienceVotes[spectatorName] = card
+	g.Rounds[g.RoundsRemaining-1] = round
+	return nil
+}
+
+// audienceTally weighs the audience's votes in round for each card.
+func (g *Game) audienceTally(round Round) map[Card]float64 {
+	if len(round.AudienceVotes) == 0 {
+		return nil
+	}
+	weight := g.AudienceWeight
+	if g.AudiencePooled {
+		weight /= float64(len(round.AudienceVotes))
+	}
+	tally := make(map[Card]float64)
+	for _, card := range round.AudienceVotes {
+		tally[card] += weight
+	}
+	return tally
+}
+
+// dropAudienceVotes removes the audience's votes for card, e.g. when the
+// player who played it leaves.
+func (round *Round) dropAudienceVotes(card Card) {
+	for spectator, vote := range round.AudienceVotes {
+		if vote == card {
+			delete(round.AudienceVotes, spectator)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudienceVoting(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "cy", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+		AudienceWeight:  0.5,
+	}
+	for _, name := range []string{"dan", "ed", "flo"} {
+		assert.NoError(t, g.AddSpectator(name, ""))
+	}
+	assert.Equal(t, ErrWrongPhase, g.Vote("dan", "a1"))
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Play("cy", "c1"))
+
+	assert.Equal(t, ErrNotOnBallot, g.Vote("dan", "a2"))
+	assert.Equal(t, ErrVoteCount, g.Vote("dan", "a1", "b1"))
+	assert.NoError(t, g.Vote("dan", "b1"))
+	assert.NoError(t, g.Vote("dan", "a1"), "the audience can change their vote")
+	assert.NoError(t, g.Vote("ed", "a1"))
+	assert.NoError(t, g.Vote("flo", "c1"))
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("bob", "c1"))
+	assert.Equal(t, VOTE, g.CurrentAction, "the audience doesn't settle a round")
+	assert.Equal(t, map[string]Card{"dan": "a1"}, g.ViewFor("dan", HandDealt).Rounds[1].AudienceVotes)
+	assert.NoError(t, g.RemovePlayer("flo"))
+	assert.NoError(t, g.Vote("cy", "a1"))
+
+	round := g.Rounds[1]
+	assert.Equal(t, map[Card]float64{"a1": 1}, round.AudienceTally)
+	assert.Equal(t, "al", round.Winner, "a1 and c1 tie on player votes, the audience breaks it")
+	assert.Equal(t, map[Card]int{"a1": 1, "b1": 1, "c1": 1}, round.VoteCounts)
+
+	// pooled, three audience votes count as one player vote between them
+	g.AudienceWeight, g.AudiencePooled = 1, true
+	assert.NoError(t, g.AddSpectator("flo", ""))
+	assert.NoError(t, g.Play("al", "a2"))
+	assert.NoError(t, g.Play("bob", "b2"))
+	assert.NoError(t, g.Play("cy", "c2"))
+	assert.NoError(t, g.Vote("dan", "b2"))
+	assert.NoError(t, g.Vote("ed", "c2"))
+	assert.NoError(t, g.Vote("flo", "c2"))
+	assert.NoError(t, g.Vote("al", "b2"))
+	assert.NoError(t, g.Vote("bob", "a2"))
+	assert.NoError(t, g.Vote("cy", "c2"))
+	round = g.Rounds[0]
+	assert.InDelta(t, 2.0/3, round.AudienceTally["c2"], 1e-9)
+	assert.Equal(t, "cy", round.Winner)
+	assert.True(t, g.Finished)
+}
+
+func TestAudienceVotingJudge(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+		},
+		Spectators:      []string{"dan"},
+		Rounds:          make([]Round, 1),
+		RoundsRemaining: 1,
+		CurrentAction:   VOTE,
+		VotingMode:      VotingJudge,
+		AudienceWeight:  1,
+	}
+	assert.Equal(t, ErrSpectator, g.Vote("dan", "a1"), "the judge decides alone")
+}
@@ -0,0 +1,96 @@
+package game
+
+// WithAutoPlay plays a random card for anyone who hasn't played when the
+// play phase's deadline passes, rather than leaving them out of the round.
+func WithAutoPlay() Option {
+	return func(g *Game) {
+		g.AutoPlay = true
+	}
+}
+
+// WithAutoVote votes at random for anyone who hasn't voted when the vote
+// phase's deadline passes. Without it they're skipped and the round is
+// settled on the votes cast.
+func WithAutoVote() Option {
+	return func(g *Game) {
+		g.AutoVote = true
+	}
+}
+
+// playTimeout is called when the play phase's deadline passes. In AutoPlay
+// games, every player yet to play has a random card from their hand played
+// for them and marked in the round's AutoPlayed. Auto-played cards count
+// like any other, so once everyone has a play the round moves to voting.
+func (g *Game) playTimeout() {
+	if !g.AutoPlay || g.CurrentAction != PLAY || g.RoundsRemaining <= 0 {
+		return
+	}
+	for _, player := range g.Players {
+		if _, ok := g.Rounds[g.RoundsRemaining-1].Plays[player.Name]; ok || len(player.Punchlines) == 0 || !player.inRound(g.roundNumber()) || player.Name == g.judge() || g.checkSuddenDeath(player.Name) != nil {
+			continue
+		}
+		for left := g.playsLeft(g.Rounds[g.RoundsRemaining-1], player.Name); left > 0; left-- {
+			hand := g.player(player.Name).Punchlines
+			if len(hand) == 0 {
+				break
+			}
+			g.play(player.Name, hand[g.random().Intn(len(hand))])
+		}
+		round := g.Rounds[g.RoundsRemaining-1]
+		if round.AutoPlayed == nil {
+			round.AutoPlayed = make(map[string]bool)
+		}
+		round.AutoPlayed[player.Name] = true
+		g.Rounds[g.RoundsRemaining-1] = round
+	}
+}
+
+// voteTimeout is called when the vote phase's deadline passes. In AutoVote
+// games, every voter yet to vote has a random vote cast for them, with as
+// many cards as the voting mode takes, and is marked in the round's
+// AutoVoted. The vote is never for their own card, or in team games a
+// teammate's; anyone left nothing to vote for is skipped.
+func (g *Game) voteTimeout() {
+	if !g.AutoVote || g.CurrentAction != VOTE || g.RoundsRemaining <= 0 {
+		return
+	}
+	round := g.Rounds[g.RoundsRemaining-1]
+	judge := g.judge()
+	for _, player := range g.Players {
+		if _, ok := round.Votes[player.Name]; ok || !player.inRound(g.roundNumber()) || (judge != "" && player.Name != judge) {
+			continue
+		}
+		var choices []Card
+		for _, card := range round.Ballot {
+			if own, ok := round.Plays[player.Name]; ok && card == own {
+				continue
+			}
+			if g.checkTeammate(round, player.Name, card) != nil {
+				continue
+			}
+			choices = append(choices, card)
+		}
+		if len(choices) == 0 {
+			continue
+		}
+		g.random().Shuffle(len(choices), func(i, j int) { choices[i], choices[j] = choices[j], choices[i] })
+		if len(choices) > g.maxVotes() {
+			choices = choices[:g.maxVotes()]
+		}
+		if round.Votes == nil {
+			round.Votes = make(map[string]Card)
+		}
+		round.Votes[player.Name] = choices[0]
+		if g.multipleVotes() {
+			if round.Rankings == nil {
+				round.Rankings = make(map[string][]Card)
+			}
+			round.Rankings[player.Name] = choices
+		}
+		if round.AutoVoted == nil {
+			round.AutoVoted = make(map[string]bool)
+		}
+		round.AutoVoted[player.Name] = true
+	}
+	g.Rounds[g.RoundsRemaining-1] = round
+}
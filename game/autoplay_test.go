@@ -0,0 +1,108 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayTimeout(t *testing.T) {
+	newGame := func() *Game {
+		return &Game{
+			Players: []Player{
+				{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+				{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+				{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+			},
+			Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+			Rounds:          make([]Round, 1),
+			RoundsRemaining: 1,
+			CurrentAction:   PLAY,
+		}
+	}
+
+	g := newGame()
+	assert.NoError(t, g.Play("al", "a1"))
+	g.playTimeout()
+	assert.Len(t, g.Rounds[0].Plays, 1, "not an auto-play game")
+	assert.Equal(t, PLAY, g.CurrentAction)
+
+	g = newGame()
+	WithAutoPlay()(g)
+	assert.NoError(t, g.Play("al", "a1"))
+	g.playTimeout()
+	round := g.Rounds[0]
+	assert.Len(t, round.Plays, 3)
+	assert.Equal(t, map[string]bool{"bob": true, "carl": true}, round.AutoPlayed)
+	assert.Contains(t, []Card{"b1", "b2", "b3", "b4", "b5", "b6"}, round.Plays["bob"])
+	assert.NotContains(t, g.Players[1].Punchlines, round.Plays["bob"])
+	assert.Len(t, g.Players[1].Punchlines, defaultHandSize-1, "replaced when the round is settled, like a normal play")
+	assert.Equal(t, VOTE, g.CurrentAction)
+
+	// playTimeout doesn't vote, see voteTimeout
+	g.playTimeout()
+	assert.Empty(t, g.Rounds[0].Votes)
+	assert.NoError(t, g.Vote("al", round.Plays["bob"]))
+	assert.Equal(t, VOTE, g.CurrentAction)
+}
+
+func TestVoteTimeout(t *testing.T) {
+	newGame := func(opts ...Option) *Game {
+		g := &Game{
+			Players: []Player{
+				{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+				{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+				{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+				{Name: "dan", Punchlines: []Card{"d1", "d2", "d3", "d4", "d5", "d6"}},
+			},
+			Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8"},
+			Rounds:          make([]Round, 2),
+			RoundsRemaining: 2,
+			CurrentAction:   PLAY,
+			rng:             rand.New(rand.NewSource(1)),
+		}
+		for _, opt := range opts {
+			opt(g)
+		}
+		for _, player := range g.Players {
+			assert.NoError(t, g.Play(player.Name, player.Punchlines[0]))
+		}
+		assert.NoError(t, g.Vote("al", "b1"))
+		return g
+	}
+
+	g := newGame()
+	g.voteTimeout()
+	assert.Len(t, g.Rounds[1].Votes, 1, "not an auto-vote game")
+	g = newGame(WithAutoPlay())
+	g.voteTimeout()
+	assert.Len(t, g.Rounds[1].Votes, 1, "auto-play doesn't vote")
+
+	for i := 0; i < 20; i++ {
+		g = newGame(WithAutoVote())
+		g.voteTimeout()
+		round := g.Rounds[1]
+		assert.Len(t, round.Votes, 4)
+		assert.Equal(t, map[string]bool{"bob": true, "carl": true, "dan": true}, round.AutoVoted)
+		assert.Equal(t, Card("b1"), round.Votes["al"])
+		for name, vote := range round.Votes {
+			assert.NotEqual(t, round.Plays[name], vote, "not their own card")
+		}
+		assert.Equal(t, VOTE, g.CurrentAction, "expire settles the round")
+	}
+
+	g = newGame(WithAutoVote(), WithRankedVoting(2))
+	g.voteTimeout()
+	round := g.Rounds[1]
+	assert.Len(t, round.Rankings["carl"], 2)
+	assert.NotContains(t, round.Rankings["carl"], Card("c1"))
+	assert.Equal(t, round.Rankings["carl"][0], round.Votes["carl"])
+
+	g = newGame(WithAutoVote())
+	g.expire()
+	assert.Equal(t, PLAY, g.CurrentAction)
+	assert.Equal(t, 1, g.RoundsRemaining)
+	assert.Len(t, g.Rounds[1].AutoVoted, 3)
+	assert.Equal(t, 4, len(g.Rounds[1].Votes), "settled on every vote")
+}
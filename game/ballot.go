@@ -0,0 +1,85 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+)
+
+var ErrNotOnBallot = errors.New("that card wasn't played this round")
+
+// fillBallot lays the round's played cards out in a random order for
+// voting, so the order doesn't give away who played what.
+func (g *Game) fillBallot(round *Round) {
+	round.Ballot = make([]Card, 0, len(round.Plays))
+	for _, card := range round.Plays {
+		round.Ballot = append(round.Ballot, card)
+	}
+	// sorted first so a seeded game deals the same ballot
+	sort.Slice(round.Ballot, func(i, j int) bool { return round.Ballot[i] < round.Ballot[j] })
+	g.random().Shuffle(len(round.Ballot), func(i, j int) {
+		round.Ballot[i], round.Ballot[j] = round.Ballot[j], round.Ballot[i]
+	})
+	round.fillBallotPairs()
+}
+
+// onBallot reports whether card can be voted for in round. Rounds that
+// reached voting without a ballot, i.e. built directly, go by the plays.
+func (round Round) onBallot(card Card) bool {
+	if round.Ballot != nil {
+		return containsCard(round.Ballot, card)
+	}
+	for _, played := range round.Plays {
+		if played == card {
+			return true
+		}
+	}
+	return false
+}
+
+// removeFromBallot takes a withdrawn card off the round's ballot.
+func (round *Round) removeFromBallot(card Card) {
+	delete(round.BallotPairs, card)
+	for i, c := range round.Ballot {
+		if c == card {
+			round.Ballot = append(round.Ballot[:i], round.Ballot[i+1:]...)
+			return
+		}
+	}
+}
+
+// MarshalJSON hides who played what until the round is settled. Played
+// always says who has played; Plays is left out until the round is settled,
+// so during the play phase the cards aren't shown at all and during the
+// vote phase they're only on the Ballot.
+func (round Round) MarshalJSON() ([]byte, error) {
+	type plain Round
+	out := struct {
+		plain
+		Played map[string]bool `json:"played,omitempty"`
+	}{plain: plain(round)}
+	if len(round.Plays) > 0 {
+		out.Played = make(map[string]bool, len(round.Plays))
+		for name := range round.Plays {
+			out.Played[name] = true
+		}
+	}
+	if !round.resolved {
+		out.Plays = nil
+		out.WriteIns = nil
+		out.Pairs = nil
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON reads a round as MarshalJSON wrote it. Plays are only
+// written once a round is settled, so a round with them was settled.
+func (round *Round) UnmarshalJSON(b []byte) error {
+	type plain Round
+	err := json.Unmarshal(b, (*plain)(round))
+	if err != nil {
+		return err
+	}
+	round.resolved = round.Plays != nil
+	return nil
+}
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBallot(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+		rng:             rand.New(rand.NewSource(1)),
+	}
+	type shown struct {
+		Plays  map[string]Card `json:"plays"`
+		Played map[string]bool `json:"played"`
+		Ballot []Card          `json:"ballot"`
+	}
+	show := func(round Round) shown {
+		var s shown
+		b, err := json.Marshal(round)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(b, &s))
+		return s
+	}
+
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.Equal(t, shown{Played: map[string]bool{"al": true, "bob": true}}, show(g.Rounds[1]), "who played, but not what")
+
+	assert.NoError(t, g.Play("carl", "c1"))
+	round := g.Rounds[1]
+	assert.ElementsMatch(t, []Card{"a1", "b1", "c1"}, round.Ballot)
+	assert.Equal(t, shown{
+		Played: map[string]bool{"al": true, "bob": true, "carl": true},
+		Ballot: round.Ballot,
+	}, show(round), "the cards, but not whose")
+
+	assert.Equal(t, ErrNotOnBallot, g.Vote("al", "a2"))
+	assert.Equal(t, ErrNotOnBallot, g.Vote("al", "never played"))
+	assert.Equal(t, ErrPlayerNotFound, g.Vote("dan", "b1"))
+	assert.Empty(t, g.Rounds[1].Votes)
+	assert.NoError(t, g.RemovePlayer("carl"))
+	assert.ElementsMatch(t, []Card{"a1", "b1"}, g.Rounds[1].Ballot)
+	assert.Equal(t, ErrNotOnBallot, g.Vote("al", "c1"), "withdrawn")
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("bob", "a1"))
+
+	assert.Equal(t, map[string]Card{"al": "a1", "bob": "b1"}, show(g.Rounds[1]).Plays, "revealed once settled")
+}
@@ -0,0 +1,102 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// SupportedPicks are the black card pick counts that make sense as a setup:
+// every round deals each setup on its own, so only single-blank prompts fit.
+var SupportedPicks = map[int]bool{1: true}
+
+// CAHImport is the result of converting a CAH-format card set.
+type CAHImport struct {
+	Setups     []RatedCard   `json:"setups"`
+	Punchlines []RatedCard   `json:"punchlines"`
+	Skipped    []SkippedCard `json:"skipped"`
+}
+
+// SkippedCard is a card from a CAH set that the import left out.
+type SkippedCard struct {
+	Text   string `json:"text"`
+	Reason string `json:"reason"`
+}
+
+type cahSet struct {
+	Black      []cahBlackCard `json:"black"`
+	White      []cahWhiteCard `json:"white"`
+	BlackCards []cahBlackCard `json:"blackCards"`
+	WhiteCards []cahWhiteCard `json:"whiteCards"`
+}
+
+type cahBlackCard struct {
+	Text string `json:"text"`
+	Pick int    `json:"pick"`
+}
+
+// cahWhiteCard accepts both the compact format (plain strings) and the full
+// format ({"text": ...}).
+type cahWhiteCard struct {
+	Text string
+}
+
+func (c *cahWhiteCard) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &c.Text); err == nil {
+		return nil
+	}
+	var card struct {
+		Text string `json:"text"`
+	}
+	err := json.Unmarshal(data, &card)
+	c.Text = card.Text
+	return err
+}
+
+// ImportCAH converts a card set in the widely circulated CAH JSON format:
+// black cards become setups and white cards become punchlines. The format
+// carries no ratings, so every card gets the supplied one.
+func ImportCAH(r io.Reader, rating string) (*CAHImport, error) {
+	if _, ok := ratingRanks[rating]; !ok {
+		return nil, fmt.Errorf("unknown rating %q", rating)
+	}
+	data, err := readDeck(r)
+	if err != nil {
+		return nil, err
+	}
+	var set cahSet
+	err = json.Unmarshal(data, &set)
+	if err != nil {
+		return nil, ErrMalformedJSON
+	}
+
+	result := &CAHImport{}
+	setups, punchlines := make(map[string]bool), make(map[string]bool)
+	add := func(cards *[]RatedCard, seen map[string]bool, text string) {
+		card := NormalizeCard(text)
+		switch {
+		case card == "":
+			result.Skipped = append(result.Skipped, SkippedCard{Text: text, Reason: "empty"})
+		case seen[dedupKey(card)]:
+			result.Skipped = append(result.Skipped, SkippedCard{Text: text, Reason: "duplicate"})
+		default:
+			seen[dedupKey(card)] = true
+			*cards = append(*cards, RatedCard{Text: card, Rating: rating})
+		}
+	}
+	for _, black := range append(set.Black, set.BlackCards...) {
+		pick := black.Pick
+		if pick == 0 {
+			pick = 1
+		}
+		if !SupportedPicks[pick] {
+			result.Skipped = append(result.Skipped, SkippedCard{Text: black.Text, Reason: fmt.Sprintf("pick %d", pick)})
+			continue
+		}
+		add(&result.Setups, setups, black.Text)
+	}
+	for _, white := range append(set.White, set.WhiteCards...) {
+		add(&result.Punchlines, punchlines, white.Text)
+	}
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportCAH(t *testing.T) {
+	tests := []struct {
+		set            string
+		rating         string
+		expectedImport *CAHImport
+		expectedError  string
+	}{
+		{
+			set: `{
+				"black": [{"text": "Why can't I sleep at night?", "pick": 1}, {"text": "_ + _ = _", "pick": 3}, {"text": "No pick"}],
+				"white": ["Patience", " patience ", "Blatant  racism", ""]
+			}`,
+			rating: "PG-13",
+			expectedImport: &CAHImport{
+				Setups: []RatedCard{
+					{Text: "Why can't I sleep at night?", Rating: "PG-13"},
+					{Text: "No pick", Rating: "PG-13"},
+				},
+				Punchlines: []RatedCard{
+					{Text: "Patience", Rating: "PG-13"},
+					{Text: "Blatant racism", Rating: "PG-13"},
+				},
+				Skipped: []SkippedCard{
+					{Text: "_ + _ = _", Reason: "pick 3"},
+					{Text: " patience ", Reason: "duplicate"},
+					{Text: "", Reason: "empty"},
+				},
+			},
+		},
+		{
+			set:    `{"blackCards": [{"text": "Prompt", "pick": 1}], "whiteCards": [{"text": "Answer", "pack": 0}]}`,
+			rating: "G",
+			expectedImport: &CAHImport{
+				Setups:     []RatedCard{{Text: "Prompt", Rating: "G"}},
+				Punchlines: []RatedCard{{Text: "Answer", Rating: "G"}},
+			},
+		},
+		{
+			set:           `{}`,
+			rating:        "NC-17",
+			expectedError: `unknown rating "NC-17"`,
+		},
+		{
+			set:           `{"white": [`,
+			rating:        "R",
+			expectedError: ErrMalformedJSON.Error(),
+		},
+	}
+	for _, test := range tests {
+		imported, err := ImportCAH(strings.NewReader(test.set), test.rating)
+		if test.expectedError != "" {
+			assert.EqualError(t, err, test.expectedError)
+		} else {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, test.expectedImport, imported)
+	}
+}
@@ -0,0 +1,321 @@
+package game
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/metrics"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Deck names one of the two card piles a CardSource can supply.
+type Deck string
+
+const (
+	SetupDeck     Deck = "setups"
+	PunchlineDeck Deck = "punchlines"
+
+	maxDeckSize = 5 << 20 // bytes, after decompression
+)
+
+// RatedCard is a card as it appears in a deck file, before cleanliness filtering.
+type RatedCard struct {
+	Text   Card   `json:"text"`
+	Rating string `json:"rating"`
+}
+
+// CardSource supplies the unfiltered contents of a deck.
+type CardSource interface {
+	Cards(ctx context.Context, deck Deck) ([]RatedCard, error)
+}
+
+// deckCache remembers parsed decks by location along with the version (ETag,
+// object generation) they were parsed from, so a source can skip downloading
+// a deck that hasn't changed.
+type deckCache struct {
+	mu      sync.Mutex
+	entries map[string]deckCacheEntry // location:entry
+}
+
+type deckCacheEntry struct {
+	version string
+	cards   []RatedCard
+}
+
+func (c *deckCache) get(location string) (deckCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[location]
+	return entry, ok
+}
+
+func (c *deckCache) put(location, version string, cards []RatedCard) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]deckCacheEntry)
+	}
+	c.entries[location] = deckCacheEntry{version: version, cards: cards}
+}
+
+// flightKey identifies a deck download. Sources are compared by identity, so
+// they must be comparable; every CardSource here is a pointer.
+type flightKey struct {
+	source CardSource
+	deck   Deck
+}
+
+type flight struct {
+	done  chan struct{}
+	cards []RatedCard
+	err   error
+}
+
+// flightGroup collapses concurrent fetches of the same deck into one, so a
+// burst of new games on a cold cache downloads each deck once.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[flightKey]*flight
+}
+
+var deckFlights flightGroup
+
+const deckFetchTimeout = time.Second * 30
+
+// fetch returns source's deck, joining a fetch already in flight if there is
+// one. The fetch itself isn't tied to any caller's context, so one caller
+// giving up doesn't fail it for the others; ctx only bounds how long this
+// caller waits.
+func (f *flightGroup) fetch(ctx context.Context, source CardSource, deck Deck) ([]RatedCard, error) {
+	key := flightKey{source: source, deck: deck}
+	f.mu.Lock()
+	if f.flights == nil {
+		f.flights = make(map[flightKey]*flight)
+	}
+	fl, ok := f.flights[key]
+	if !ok {
+		fl = &flight{done: make(chan struct{})}
+		f.flights[key] = fl
+		go func() {
+			fetchCtx, cancel := context.WithTimeout(context.Background(), deckFetchTimeout)
+			defer cancel()
+			fl.cards, fl.err = source.Cards(fetchCtx, deck)
+			f.mu.Lock()
+			delete(f.flights, key)
+			f.mu.Unlock()
+			close(fl.done)
+		}()
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-fl.done:
+		return fl.cards, fl.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func getCards(ctx context.Context, source CardSource, deck Deck, cleanliness string) ([]Card, error) {
+	rated, err := getRatedCards(ctx, source, deck, cleanliness)
+	if err != nil {
+		return nil, err
+	}
+	return cardTexts(rated), nil
+}
+
+// getRatedCards is getCards keeping each card's rating.
+func getRatedCards(ctx context.Context, source CardSource, deck Deck, cleanliness string) ([]RatedCard, error) {
+	rated, err := deckFlights.fetch(ctx, source, deck)
+	if err != nil {
+		metrics.CountError("deck_fetch")
+		return nil, err
+	}
+	if source == cardSource {
+		recordLoadedDeck(deck, rated)
+	}
+	return filterRated(rated, cleanliness)
+}
+
+func filterCards(rated []RatedCard, cleanliness string) ([]Card, error) {
+	kept, err := filterRated(rated, cleanliness)
+	if err != nil {
+		return nil, err
+	}
+	return cardTexts(kept), nil
+}
+
+func filterRated(rated []RatedCard, cleanliness string) ([]RatedCard, error) {
+	var kept []RatedCard
+	for _, card := range rated {
+		cleanEnough, err := isCleanEnough(card.Rating, cleanliness)
+		if err != nil {
+			return nil, err
+		}
+		if !cleanEnough {
+			continue
+		}
+		kept = append(kept, card)
+	}
+	return kept, nil
+}
+
+func cardTexts(rated []RatedCard) []Card {
+	var cards []Card
+	for _, card := range rated {
+		cards = append(cards, card.Text)
+	}
+	return cards
+}
+
+// parseDeck decodes a deck file regardless of where it came from. Gzipped
+// content is detected by its magic number, and JSON (an array of RatedCard)
+// is distinguished from CSV (text,rating) by its first non-space byte.
+func parseDeck(r io.Reader) ([]RatedCard, error) {
+	data, err := readDeck(r)
+	if err != nil {
+		return nil, err
+	}
+	if isJSONDeck(data) {
+		return parseJSONDeck(data)
+	}
+	return parseCSVDeck(bytes.NewReader(data))
+}
+
+func readDeck(r io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxDeckSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		data, err = ioutil.ReadAll(io.LimitReader(zr, maxDeckSize+1))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(data) > maxDeckSize {
+		return nil, ErrDeckTooLarge
+	}
+	return data, nil
+}
+
+func isJSONDeck(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+func parseJSONDeck(data []byte) ([]RatedCard, error) {
+	var cards []RatedCard
+	err := json.Unmarshal(data, &cards)
+	if err != nil {
+		return nil, ErrMalformedJSON
+	}
+	for i := range cards {
+		cards[i].Text = NormalizeCard(string(cards[i].Text))
+	}
+	return cards, nil
+}
+
+func parseCSVDeck(r io.Reader) ([]RatedCard, error) {
+	var cards []RatedCard
+	err := readCSVDeck(r, func(line int, card RatedCard, err error) error {
+		if err != nil {
+			return err
+		}
+		cards = append(cards, card)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// readCSVDeck calls fn with each record of a CSV deck as a card, or with
+// the error it couldn't be parsed with, and the line it starts on. It stops
+// at the first error fn returns, or a read error other than a
+// *csv.ParseError, and returns it.
+func readCSVDeck(r io.Reader, fn func(line int, card RatedCard, err error) error) error {
+	reader := csv.NewReader(r)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		var line int
+		var card RatedCard
+		if parseErr, ok := err.(*csv.ParseError); ok {
+			line = parseErr.Line
+		} else if err != nil {
+			return err
+		} else {
+			line, _ = reader.FieldPos(0)
+			card, err = parseCSVRecord(record)
+		}
+		if err := fn(line, card, err); err != nil {
+			return err
+		}
+	}
+}
+
+func parseCSVRecord(line []string) (RatedCard, error) {
+	if len(line) != 2 {
+		return RatedCard{}, ErrMalformedCSV
+	}
+	return RatedCard{
+		Text:   NormalizeCard(line[0]),
+		Rating: line[1],
+	}, nil
+}
+
+var quoteReplacer = strings.NewReplacer(
+	"\u2018", "'", "\u2019", "'", "\u201a", "'", "\u201b", "'",
+	"\u201c", `"`, "\u201d", `"`, "\u201e", `"`, "\u201f", `"`,
+)
+
+// NormalizeCard is the canonical form of a card's text: NFC, straight quotes,
+// trimmed, and with internal runs of whitespace (including NBSP) collapsed.
+// Deck text and every card string received from a client go through it, so
+// cards compare equal however the client's input mangled them.
+func NormalizeCard(text string) Card {
+	text = quoteReplacer.Replace(norm.NFC.String(text))
+	return Card(strings.Join(strings.Fields(text), " "))
+}
+
+var ratingRanks = map[string]int{
+	"G":     0,
+	"PG":    1,
+	"PG-13": 2,
+	"R":     3,
+	"X":     4,
+}
+
+func isCleanEnough(cardCleanliness, cleanliness string) (bool, error) {
+	var ok bool
+	var cardRank, rank int
+	cardRank, ok = ratingRanks[cardCleanliness]
+	if !ok {
+		return false, ErrMalformedCSV
+	}
+	rank, ok = ratingRanks[cleanliness]
+	if !ok {
+		return false, ErrMalformedCSV
+	}
+	if cardRank <= rank {
+		return true, nil
+	}
+	return false, nil
+}
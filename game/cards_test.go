@@ -0,0 +1,81 @@
+package game
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stinkyfingers/differencebetween/api/metrics"
+	"github.com/stinkyfingers/differencebetween/api/testingsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCard(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected Card
+	}{
+		{text: "Caf\u00e9", expected: "Caf\u00e9"},
+		{text: "Cafe\u0301", expected: "Caf\u00e9"},
+		{text: "Nothing. They\u2019re the same", expected: "Nothing. They're the same"},
+		{text: "\u201cQuoted\u201d", expected: `"Quoted"`},
+		{text: "Patience\u00a0", expected: "Patience"},
+		{text: "  A   few\tdollars \n", expected: "A few dollars"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, NormalizeCard(test.text), test.text)
+	}
+}
+
+func TestParseDeckNormalizes(t *testing.T) {
+	cards, err := parseDeck(strings.NewReader("Cafe\u0301\u00a0,G\nThey\u2019re,G"))
+	assert.NoError(t, err)
+	assert.Equal(t, []RatedCard{{Text: "Caf\u00e9", Rating: "G"}, {Text: "They're", Rating: "G"}}, cards)
+}
+
+type failingSource struct{}
+
+func (failingSource) Cards(ctx context.Context, deck Deck) ([]RatedCard, error) {
+	return nil, ErrDeckTooLarge
+}
+
+func TestGetCardsCountsFailures(t *testing.T) {
+	before := metrics.ErrorCount("deck_fetch")
+	_, err := getCards(context.Background(), failingSource{}, SetupDeck, "R")
+	assert.Equal(t, ErrDeckTooLarge, err)
+	assert.Equal(t, before+1, metrics.ErrorCount("deck_fetch"))
+}
+
+func TestGetCardsSharesConcurrentFetches(t *testing.T) {
+	client := &testingsupport.S3{
+		GetObjectOutput: &s3.GetObjectOutput{
+			Body: ioutil.NopCloser(strings.NewReader("test,R\ntest2,G")),
+		},
+		Delay: time.Millisecond * 50,
+	}
+	source := &S3CardSource{Client: client, SetupsKey: "setups"}
+
+	// one caller giving up doesn't abort the fetch the rest are waiting on
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := getSetups(canceled, source, "R")
+	assert.Equal(t, context.Canceled, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cards, err := getSetups(context.Background(), source, "R")
+			assert.NoError(t, err)
+			assert.Equal(t, []Card{"test", "test2"}, cards)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&client.Gets))
+}
@@ -0,0 +1,78 @@
+package game
+
+import (
+	"log"
+
+	"github.com/stinkyfingers/differencebetween/api/flags"
+)
+
+// Chaos modifiers, recorded in Round.Chaos for the round they start.
+const (
+	ChaosRotateHands = "rotate_hands"
+)
+
+// chaosOdds is the one-in chance of a modifier at the start of each round
+// in chaos games.
+const chaosOdds = 5
+
+// RotateHands has everyone pass their hand to the left, at the host's
+// request, in games with the chaos feature. It's only allowed between
+// rounds.
+func (g *Game) RotateHands(playerName string) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.HasFeature(flags.Chaos) {
+		return flags.ErrDisabled
+	}
+	if !g.isHost(playerName) {
+		return ErrNotHost
+	}
+	if g.over() {
+		return ErrGameFinished
+	}
+	if !g.betweenRounds() {
+		return ErrMidRound
+	}
+	g.rotateHands()
+	return nil
+}
+
+// betweenRounds reports whether the current round has yet to see a play,
+// counting the first card of a pair, see WithPairPlay.
+func (g *Game) betweenRounds() bool {
+	if g.CurrentAction == LOBBY || g.CurrentAction == FINISHED {
+		return true
+	}
+	if g.CurrentAction != PLAY {
+		return false
+	}
+	if g.RoundsRemaining <= 0 {
+		return true
+	}
+	round := g.Rounds[g.RoundsRemaining-1]
+	return len(round.Plays) == 0 && len(round.Pairs) == 0
+}
+
+// rotateHands gives each player the hand of the player before them, so in a
+// two player game the hands swap. Hands move whole, so a short hand stays
+// short.
+func (g *Game) rotateHands() {
+	if len(g.Players) < 2 {
+		return
+	}
+	last := g.Players[len(g.Players)-1].Punchlines
+	for i := len(g.Players) - 1; i > 0; i-- {
+		g.Players[i].Punchlines = g.Players[i-1].Punchlines
+	}
+	g.Players[0].Punchlines = last
+	log.Printf("game %d: hands rotated", g.ID)
+}
+
+// chaos may apply a modifier to the round about to start in chaos games.
+func (g *Game) chaos() {
+	if g.RoundsRemaining <= 0 || !g.HasFeature(flags.Chaos) || g.random().Intn(chaosOdds) != 0 {
+		return
+	}
+	g.rotateHands()
+	g.Rounds[g.RoundsRemaining-1].Chaos = ChaosRotateHands
+}
@@ -0,0 +1,86 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stinkyfingers/differencebetween/api/flags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotateHands(t *testing.T) {
+	defer config.Set(config.Current())
+	cfg := *config.Current()
+	cfg.Features = map[string]bool{flags.Chaos: true}
+	config.Set(&cfg)
+
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2"}},
+			{Name: "carl", Punchlines: []Card{"c1"}},
+		},
+		Rounds:          make([]Round, 1),
+		RoundsRemaining: 1,
+		CurrentAction:   PLAY,
+	}
+	assert.Equal(t, flags.ErrDisabled, g.RotateHands("al"), "game didn't opt in")
+	WithFeatures(flags.Chaos)(g)
+	assert.Equal(t, ErrNotHost, g.RotateHands("bob"))
+	assert.NoError(t, g.RotateHands("al"))
+	assert.Equal(t, []Card{"c1"}, g.Players[0].Punchlines)
+	assert.Equal(t, []Card{"a1", "a2", "a3"}, g.Players[1].Punchlines)
+	assert.Equal(t, []Card{"b1", "b2"}, g.Players[2].Punchlines)
+
+	g.Rounds[0].Pairs = map[string][]Card{"bob": {"a1"}}
+	assert.Equal(t, ErrMidRound, g.RotateHands("al"), "half a pair played")
+	g.Rounds[0].Plays = map[string]Card{"bob": "a1"}
+	assert.Equal(t, ErrMidRound, g.RotateHands("al"))
+
+	g.Players = g.Players[:2]
+	g.rotateHands()
+	assert.Equal(t, []Card{"a1", "a2", "a3"}, g.Players[0].Punchlines, "two players swap")
+	assert.Equal(t, []Card{"c1"}, g.Players[1].Punchlines)
+
+	cfg.Features = map[string]bool{}
+	assert.Equal(t, flags.ErrDisabled, g.RotateHands("al"), "disabled server-wide")
+}
+
+func TestChaosRotatesHandsBetweenRounds(t *testing.T) {
+	defer config.Set(config.Current())
+	cfg := *config.Current()
+	cfg.Features = map[string]bool{flags.Chaos: true}
+	config.Set(&cfg)
+
+	newGame := func() *Game {
+		return &Game{
+			Players: []Player{
+				{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+				{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			},
+			Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+			Rounds:          make([]Round, 2),
+			RoundsRemaining: 2,
+			CurrentAction:   PLAY,
+			Features:        []string{flags.Chaos},
+		}
+	}
+	rotated := 0
+	for seed := int64(0); seed < 50; seed++ {
+		g := newGame()
+		g.rng = rand.New(rand.NewSource(seed))
+		assert.NoError(t, g.Play("al", "a1"))
+		assert.NoError(t, g.Play("bob", "b1"))
+		assert.NoError(t, g.Vote("al", "b1"))
+		assert.NoError(t, g.Vote("bob", "a1"))
+		if g.Rounds[0].Chaos == ChaosRotateHands {
+			rotated++
+			assert.Contains(t, g.Players[0].Punchlines, Card("b2"))
+			assert.Contains(t, g.Players[1].Punchlines, Card("a2"))
+		} else {
+			assert.Contains(t, g.Players[0].Punchlines, Card("a2"))
+		}
+	}
+	assert.True(t, rotated > 0 && rotated < 50, "rotated %d of 50", rotated)
+}
@@ -0,0 +1,53 @@
+package game
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxMessageLength = 280 // runes
+	maxMessages      = 50  // kept per game, see PostMessage
+)
+
+var (
+	ErrMessageEmpty   = errors.New("message is empty")
+	ErrMessageTooLong = errors.New("message is too long")
+)
+
+type ChatMessage struct {
+	Author    string    `json:"author"`
+	Text      string    `json:"text"`
+	Time      time.Time `json:"time"`
+	Spectator bool      `json:"spectator,omitempty"` // posted by a spectator rather than a player
+}
+
+// PostMessage adds playerName's message to the game's chat. Spectators can
+// chat too, and their messages say so. Messages count towards the sender's
+// rate limit, and only the latest 50 are kept.
+func (g *Game) PostMessage(playerName, text string) error {
+	text = string(NormalizeCard(text))
+	if text == "" {
+		return ErrMessageEmpty
+	}
+	if utf8.RuneCountInString(text) > maxMessageLength {
+		return ErrMessageTooLong
+	}
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	spectator := g.isSpectator(playerName)
+	if _, err := g.hand(playerName); err != nil && !spectator {
+		return err
+	}
+	err := g.allowAction(playerName)
+	if err != nil {
+		return err
+	}
+	if len(g.Messages) >= maxMessages {
+		n := copy(g.Messages, g.Messages[len(g.Messages)-maxMessages+1:])
+		g.Messages = g.Messages[:n]
+	}
+	g.Messages = append(g.Messages, ChatMessage{Author: playerName, Text: text, Time: now(), Spectator: spectator})
+	return nil
+}
@@ -0,0 +1,49 @@
+package game
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostMessage(t *testing.T) {
+	clock := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+	defer config.Set(config.Current())
+	cfg := *config.Current()
+	cfg.MaxActions = 3
+	cfg.ActionWindow = time.Minute
+	config.Set(&cfg)
+	g := &Game{Players: []Player{{Name: "al"}, {Name: "bob"}}, Spectators: []string{"sam"}}
+
+	assert.Equal(t, ErrMessageEmpty, g.PostMessage("al", "  "))
+	assert.Equal(t, ErrMessageTooLong, g.PostMessage("al", strings.Repeat("x", maxMessageLength+1)))
+	assert.Equal(t, ErrPlayerNotFound, g.PostMessage("carl", "hi"))
+	assert.NoError(t, g.PostMessage("al", " you're going down "))
+	assert.NoError(t, g.PostMessage("sam", "popcorn"))
+	assert.Equal(t, []ChatMessage{
+		{Author: "al", Text: "you're going down", Time: clock},
+		{Author: "sam", Text: "popcorn", Time: clock, Spectator: true},
+	}, g.Messages)
+
+	assert.NoError(t, g.PostMessage("al", "2"))
+	assert.NoError(t, g.PostMessage("al", "3"))
+	assert.Equal(t, ErrTooManyActions, g.PostMessage("al", "4"))
+
+	assert.NoError(t, g.RenamePlayer("al", "alice"))
+	assert.Equal(t, "alice", g.Messages[0].Author)
+
+	for i := 0; i < maxMessages; i++ {
+		clock = clock.Add(time.Minute)
+		assert.NoError(t, g.PostMessage("bob", strconv.Itoa(i)))
+	}
+	assert.Len(t, g.Messages, maxMessages)
+	assert.Equal(t, "0", g.Messages[0].Text, "the oldest are dropped")
+	assert.Equal(t, strconv.Itoa(maxMessages-1), g.Messages[maxMessages-1].Text)
+	assert.Equal(t, g.Messages, g.ViewFor("sam", HandDealt).Messages)
+}
@@ -0,0 +1,128 @@
+package game
+
+import (
+	"context"
+	"log"
+)
+
+// SetCleanliness changes the highest card rating the host wants for the
+// rest of the game. Setups rated above it are replaced in the rounds that
+// haven't started, and punchlines rated above it leave the deck and the
+// discards; with swapHands they're swapped out of hands too, otherwise
+// players keep what they hold. A higher rating only widens what's fetched
+// from then on. If there aren't enough clean setups to replace the dirty
+// ones it returns ErrTooFewSetups and changes nothing.
+func (g *Game) SetCleanliness(ctx context.Context, playerName, cleanliness string, swapHands bool) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.isHost(playerName) {
+		return ErrNotHost
+	}
+	if _, ok := ratingRanks[cleanliness]; !ok {
+		return ErrUnknownRating
+	}
+	if g.over() {
+		return ErrGameFinished
+	}
+
+	slots := g.dirtySetups(cleanliness)
+	clean := len(g.cleanCards(g.setupPool, cleanliness))
+	if clean < len(slots) {
+		rated, err := getRatedCards(ctx, g.source, SetupDeck, cleanliness)
+		if err != nil {
+			return err
+		}
+		g.refillSetupPool(g.rate(rated), len(g.setupPool)+len(slots)-clean)
+	}
+
+	pool := g.cleanCards(g.setupPool, cleanliness)
+	if len(pool) < len(slots) {
+		return ErrTooFewSetups
+	}
+	for _, slot := range slots {
+		g.Rounds[slot.round].Setup[slot.side] = pool[len(pool)-1]
+		pool = pool[:len(pool)-1]
+	}
+	g.setupPool = pool
+	g.Punchlines = g.cleanCards(g.Punchlines, cleanliness)
+	g.discards = g.cleanCards(g.discards, cleanliness)
+	if swapHands {
+		g.swapDirtyCards(cleanliness)
+	}
+	g.Cleanliness = cleanliness
+	g.maybeReplenish()
+	log.Printf("game %d: cleanliness set to %s, %d setups replaced", g.ID, cleanliness, len(slots))
+	return nil
+}
+
+// setupSlot is one of a round's setups, see dirtySetups.
+type setupSlot struct {
+	round, side int
+}
+
+// dirtySetups lists the setups rated above cleanliness in rounds still to
+// come, including the current one while nobody has played in it.
+func (g *Game) dirtySetups(cleanliness string) []setupSlot {
+	upcoming := g.RoundsRemaining - 1
+	if g.betweenRounds() {
+		upcoming = g.RoundsRemaining
+	}
+	var slots []setupSlot
+	for i := 0; i < upcoming; i++ {
+		for side, setup := range g.Rounds[i].Setup {
+			if !g.cleanEnough(setup, cleanliness) {
+				slots = append(slots, setupSlot{round: i, side: side})
+			}
+		}
+	}
+	return slots
+}
+
+// swapDirtyCards drops the cards rated above cleanliness from every hand
+// and deals replacements. Running out leaves hands short, as in deal.
+func (g *Game) swapDirtyCards(cleanliness string) {
+	for i := range g.Players {
+		g.Players[i].Punchlines = g.cleanCards(g.Players[i].Punchlines, cleanliness)
+	}
+	for i := range g.Players {
+		if err := g.topUp(&g.Players[i]); err != nil {
+			log.Printf("game %d: swapping dirty cards: %v", g.ID, err)
+			return
+		}
+	}
+}
+
+// cleanCards returns the cards in cards no dirtier than cleanliness.
+func (g *Game) cleanCards(cards []Card, cleanliness string) []Card {
+	var kept []Card
+	for _, card := range cards {
+		if g.cleanEnough(card, cleanliness) {
+			kept = append(kept, card)
+		}
+	}
+	return kept
+}
+
+// cleanEnough reports whether card is rated no higher than cleanliness.
+// Cards the game never saw a rating for, such as those in games restored
+// from before ratings were kept, are assumed to be.
+func (g *Game) cleanEnough(card Card, cleanliness string) bool {
+	rating, ok := g.ratings[card]
+	if !ok {
+		return true
+	}
+	clean, err := isCleanEnough(rating, cleanliness)
+	return err != nil || clean
+}
+
+// rate records the ratings of cards fetched for the game and returns their
+// text.
+func (g *Game) rate(rated []RatedCard) []Card {
+	if g.ratings == nil {
+		g.ratings = make(map[Card]string, len(rated))
+	}
+	for _, card := range rated {
+		g.ratings[card.Text] = card.Rating
+	}
+	return cardTexts(rated)
+}
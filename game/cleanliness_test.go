@@ -0,0 +1,72 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetCleanliness(t *testing.T) {
+	defer config.Set(config.Current())
+	cfg := *config.Current()
+	cfg.MaxActions = 100
+	config.Set(&cfg)
+	source := &staticSource{}
+	for i := 0; i < 20; i++ {
+		*source = append(*source,
+			RatedCard{Text: Card(fmt.Sprintf("clean %d", i)), Rating: "G"},
+			RatedCard{Text: Card(fmt.Sprintf("dirty %d", i)), Rating: "R"},
+		)
+	}
+	ctx := context.Background()
+	g, err := NewGame(ctx, Player{Name: "al"}, 3, "R", WithCardSource(source), WithHandSize(4), WithMinPlayers(2))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	assert.NoError(t, g.AddPlayer(Player{Name: "bob"}, ""))
+	assert.NoError(t, g.Start("al", false))
+	al, bob := g.Players[0].Punchlines[0], g.Players[1].Punchlines[0]
+	assert.NoError(t, g.Play("al", al))
+	assert.NoError(t, g.Play("bob", bob))
+	assert.NoError(t, g.Vote("al", bob))
+	assert.NoError(t, g.Vote("bob", al))
+	played := g.Rounds[2].Setup
+	g.Rounds[1].Setup = []Card{"dirty 0", "clean 0"}
+	g.Players[0].Punchlines[0] = "dirty 1"
+
+	assert.Equal(t, ErrNotHost, g.SetCleanliness(ctx, "bob", "G", false))
+	assert.Equal(t, ErrUnknownRating, g.SetCleanliness(ctx, "al", "filthy", false))
+	assert.Equal(t, "R", g.Cleanliness)
+
+	assert.NoError(t, g.SetCleanliness(ctx, "al", "G", false))
+	assert.Equal(t, "G", g.Cleanliness)
+	assert.Equal(t, played, g.Rounds[2].Setup, "rounds already played keep their setups")
+	assert.Equal(t, Card("clean 0"), g.Rounds[1].Setup[1])
+	for i := 0; i < 2; i++ {
+		for _, setup := range g.Rounds[i].Setup {
+			assert.True(t, strings.HasPrefix(string(setup), "clean"), "setup %s", setup)
+		}
+	}
+	for _, card := range append(append([]Card(nil), g.Punchlines...), g.discards...) {
+		assert.True(t, strings.HasPrefix(string(card), "clean"), "punchline %s", card)
+	}
+	assert.Equal(t, Card("dirty 1"), g.Players[0].Punchlines[0], "hands are kept unless swapped")
+
+	assert.NoError(t, g.SetCleanliness(ctx, "al", "G", true))
+	assert.Len(t, g.Players[0].Punchlines, 4)
+	for _, player := range g.Players {
+		for _, card := range player.Punchlines {
+			assert.True(t, strings.HasPrefix(string(card), "clean"), "%s holds %s", player.Name, card)
+		}
+	}
+
+	// every clean setup is dealt or in the pool, so there's nothing to swap in
+	g.Rounds[0].Setup[0] = "dirty 2"
+	g.setupPool = nil
+	*source = (*source)[:2]
+	assert.Equal(t, ErrTooFewSetups, g.SetCleanliness(ctx, "al", "G", false))
+	assert.Equal(t, Card("dirty 2"), g.Rounds[0].Setup[0])
+}
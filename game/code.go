@@ -0,0 +1,66 @@
+package game
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// codeAlphabet leaves out I, L and O, which are easily mistaken for
+	// digits or each other when read out.
+	codeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ"
+	codeLength   = 5
+	codeAttempts = 100
+)
+
+var (
+	ErrInvalidCode = errors.New("join codes are 5 letters")
+
+	codes = make(map[string]int) // join code:game id, guarded by gamesMu
+)
+
+// takeCode gives g a join code that no other game in memory has. The caller
+// must hold gamesMu.
+func (g *Game) takeCode() error {
+	b := make([]byte, codeLength)
+	for attempt := 0; attempt < codeAttempts; attempt++ {
+		for i := range b {
+			b[i] = codeAlphabet[g.random().Intn(len(codeAlphabet))]
+		}
+		if _, taken := codes[string(b)]; !taken {
+			g.Code = string(b)
+			codes[g.Code] = g.ID
+			return nil
+		}
+	}
+	return ErrNoGamesAvailable
+}
+
+// NormalizeCode uppercases a join code as typed and checks its shape.
+func NormalizeCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != codeLength {
+		return "", ErrInvalidCode
+	}
+	for _, r := range code {
+		if !strings.ContainsRune(codeAlphabet, r) {
+			return "", ErrInvalidCode
+		}
+	}
+	return code, nil
+}
+
+// GetGameByCode is GetGame for a join code, which is case insensitive.
+func GetGameByCode(code string) (*Game, error) {
+	code, err := NormalizeCode(code)
+	if err != nil {
+		return nil, err
+	}
+	gamesMu.RLock()
+	defer gamesMu.RUnlock()
+	id, ok := codes[code]
+	if !ok {
+		return nil, ErrGameNotFound
+	}
+	return getGame(id)
+}
@@ -0,0 +1,54 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinCodes(t *testing.T) {
+	source := &staticSource{}
+	for _, text := range []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"} {
+		*source = append(*source, RatedCard{Text: text, Rating: "G"})
+	}
+	g, err := NewGame(context.Background(), Player{Name: "al"}, 1, "R", WithCardSource(source))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	code, err := NormalizeCode(g.Code)
+	assert.NoError(t, err)
+	assert.Equal(t, g.Code, code)
+
+	found, err := GetGameByCode(" " + strings.ToLower(g.Code))
+	assert.NoError(t, err, "case and spaces don't matter")
+	assert.Equal(t, g, found)
+	_, err = GetGameByCode("ABC1")
+	assert.Equal(t, ErrInvalidCode, err)
+	_, err = GetGameByCode("OOOOO")
+	assert.Equal(t, ErrInvalidCode, err, "no ambiguous letters")
+
+	assert.NoError(t, Delete(g.ID))
+	_, err = GetGameByCode(g.Code)
+	assert.Equal(t, ErrGameGone, err, "still reserved while restorable")
+	_, err = Restore(g.ID)
+	assert.NoError(t, err)
+
+	deleteGame(g.ID)
+	_, err = GetGameByCode(g.Code)
+	assert.Equal(t, ErrGameNotFound, err)
+	assert.NotContains(t, codes, g.Code, "freed")
+}
+
+func TestJoinCodesUnique(t *testing.T) {
+	saved := codes
+	defer func() { codes = saved }()
+	codes = make(map[string]int)
+
+	g := &Game{}
+	for i := 0; i < 2000; i++ {
+		g.ID = i
+		assert.NoError(t, g.takeCode())
+	}
+	assert.Len(t, codes, 2000)
+}
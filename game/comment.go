@@ -0,0 +1,121 @@
+package game
+
+import (
+	"errors"
+	"sort"
+	"unicode/utf8"
+)
+
+const (
+	maxCommentLength = 140 // runes
+	tallyTolerance   = 1e-9
+)
+
+var (
+	ErrRoundNotFound    = errors.New("round does not exist")
+	ErrRoundNotFinished = errors.New("round has not finished")
+	ErrNotRoundWinner   = errors.New("only the round's winner can comment")
+	ErrCommentClosed    = errors.New("commenting on this round has closed")
+	ErrCommentTooLong   = errors.New("comment is too long")
+	ErrCommentEmpty     = errors.New("comment is empty")
+)
+
+// roundWinner returns the player whose card got the most votes, or points
+// in a ranked round, or "" if the top cards tied.
+func roundWinner(round Round) string {
+	winners, _ := roundWinners(round)
+	if len(winners) != 1 {
+		return ""
+	}
+	return winners[0]
+}
+
+// roundWinners returns every player whose card got the top tally, with the
+// audience's weighted votes added, sorted, along with the winning card if
+// there's only one.
+func roundWinners(round Round) ([]string, Card) {
+	tally := round.Tallies
+	if tally == nil {
+		tally = voteCounts(round)
+	}
+	totals := make(map[Card]float64, len(tally))
+	for card, votes := range tally {
+		totals[card] = float64(votes)
+	}
+	for card, votes := range round.AudienceTally {
+		totals[card] += votes
+	}
+	most := 0.0
+	for _, votes := range totals {
+		if votes > most {
+			most = votes
+		}
+	}
+	if most == 0 {
+		return nil, ""
+	}
+	var winners []string
+	var top Card
+	for player, card := range round.Plays {
+		// audience votes are fractions, so allow for rounding in a tie
+		if totals[card] > most-tallyTolerance {
+			winners = append(winners, player)
+			top = card
+		}
+	}
+	sort.Strings(winners)
+	if len(winners) != 1 {
+		top = ""
+	}
+	return winners, top
+}
+
+// voteCounts counts the votes for each card, first choices only in ranked
+// rounds.
+func voteCounts(round Round) map[Card]int {
+	counts := make(map[Card]int)
+	for _, card := range round.Votes {
+		counts[card]++
+	}
+	return counts
+}
+
+// roundIndex converts a 1-based round number, in the order rounds are
+// played, to its index in Rounds, which are played from the end.
+func (g *Game) roundIndex(number int) (int, error) {
+	if number < 1 || number > len(g.Rounds) {
+		return 0, ErrRoundNotFound
+	}
+	return len(g.Rounds) - number, nil
+}
+
+// Comment lets the winner of round number (1-based) attach a one-liner to
+// it. Only the most recently completed round takes comments, so the window
+// closes when the next round completes.
+func (g *Game) Comment(playerName string, number int, text string) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	index, err := g.roundIndex(number)
+	if err != nil {
+		return err
+	}
+	if index < g.RoundsRemaining {
+		return ErrRoundNotFinished
+	}
+	if index > g.RoundsRemaining {
+		return ErrCommentClosed
+	}
+	round := g.Rounds[index]
+	if round.Winner == "" || round.Winner != playerName {
+		return ErrNotRoundWinner
+	}
+	comment := string(NormalizeCard(text))
+	if comment == "" {
+		return ErrCommentEmpty
+	}
+	if utf8.RuneCountInString(comment) > maxCommentLength {
+		return ErrCommentTooLong
+	}
+	g.Rounds[index].Comment = comment
+	return nil
+}
@@ -0,0 +1,53 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundWinner(t *testing.T) {
+	round := Round{
+		Plays: map[string]Card{"al": "a", "bob": "b", "carl": "c"},
+		Votes: map[string]Card{"al": "b", "bob": "c", "carl": "b"},
+	}
+	assert.Equal(t, "bob", roundWinner(round))
+	round.Votes["carl"] = "a"
+	assert.Equal(t, "", roundWinner(round), "three-way tie")
+}
+
+func TestComment(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	assert.Equal(t, ErrRoundNotFinished, g.Comment("al", 1, "too soon"))
+
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("bob", "b1"))
+	assert.Equal(t, "bob", g.Rounds[1].Winner)
+
+	assert.Equal(t, ErrNotRoundWinner, g.Comment("al", 1, "sour grapes"))
+	assert.Equal(t, ErrRoundNotFound, g.Comment("bob", 3, "hi"))
+	assert.Equal(t, ErrCommentTooLong, g.Comment("bob", 1, strings.Repeat("x", maxCommentLength+1)))
+	assert.Equal(t, ErrCommentEmpty, g.Comment("bob", 1, "   "))
+	assert.NoError(t, g.Comment("bob", 1, "  I’ll  be here all week "))
+	assert.Equal(t, "I'll be here all week", g.Rounds[1].Comment)
+
+	// the next round completing closes the window
+	assert.NoError(t, g.Play("al", "a2"))
+	assert.NoError(t, g.Play("bob", "b2"))
+	assert.NoError(t, g.Vote("al", "a2"))
+	assert.NoError(t, g.Vote("bob", "a2"))
+	assert.Equal(t, ErrCommentClosed, g.Comment("bob", 1, "again"))
+	assert.NoError(t, g.Comment("al", 2, "gg"))
+}
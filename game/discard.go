@@ -0,0 +1,45 @@
+package game
+
+import "sort"
+
+// discard puts the cards played in a settled round on the discard pile,
+// leaving out write-ins. They're sorted first so a seeded game reshuffles
+// them the same way.
+func (g *Game) discard(round Round) {
+	played := make([]Card, 0, len(round.Plays))
+	for name, card := range round.Plays {
+		if pair, ok := round.Pairs[name]; ok {
+			played = append(played, pair...)
+		} else if !round.WriteIns[name] {
+			played = append(played, card)
+		}
+	}
+	sort.Slice(played, func(i, j int) bool { return played[i] < played[j] })
+	g.discards = append(g.discards, played...)
+}
+
+// reshuffle shuffles the discard pile back into the deck, under the cards
+// still in it so those are dealt first. Only played cards are discarded, so
+// nothing in a hand can come back round.
+func (g *Game) reshuffle() {
+	if len(g.discards) == 0 {
+		return
+	}
+	discards := g.discards
+	g.random().Shuffle(len(discards), func(i, j int) {
+		discards[i], discards[j] = discards[j], discards[i]
+	})
+	// the deck keeps the room it had when full, so usually they fit under
+	// it in place, and the discards' own room is kept for the next lot
+	size := len(discards) + len(g.Punchlines)
+	if cap(g.Punchlines) < size {
+		punchlines := make([]Card, len(g.Punchlines), size)
+		copy(punchlines, g.Punchlines)
+		g.Punchlines = punchlines
+	}
+	deck := len(g.Punchlines)
+	g.Punchlines = g.Punchlines[:size]
+	copy(g.Punchlines[len(discards):], g.Punchlines[:deck])
+	copy(g.Punchlines, discards)
+	g.discards = discards[:0]
+}
@@ -0,0 +1,57 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReshuffleDiscards(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+		},
+		Punchlines:      []Card{"1", "2"},
+		Rounds:          make([]Round, 5),
+		RoundsRemaining: 5,
+		CurrentAction:   PLAY,
+		rng:             rand.New(rand.NewSource(1)),
+	}
+	for round := 0; round < 4; round++ {
+		al, bob := g.Players[0].Punchlines[0], g.Players[1].Punchlines[0]
+		assert.NoError(t, g.Play("al", al))
+		assert.NoError(t, g.Play("bob", bob))
+		assert.NoError(t, g.Vote("al", bob))
+		assert.NoError(t, g.Vote("bob", al))
+
+		seen := make(map[Card]bool)
+		for _, cards := range [][]Card{g.Players[0].Punchlines, g.Players[1].Punchlines, g.Punchlines, g.discards} {
+			for _, card := range cards {
+				assert.False(t, seen[card], "%s is in two places", card)
+				seen[card] = true
+			}
+		}
+		assert.Len(t, seen, 14, "round %d", round)
+		assert.Len(t, g.Players[0].Punchlines, defaultHandSize)
+		assert.Len(t, g.Players[1].Punchlines, defaultHandSize)
+	}
+	assert.Equal(t, 1, g.RoundsRemaining, "still going after the deck ran out")
+
+	// the discards go under what's left of the deck, in room it already has
+	g = &Game{Punchlines: make([]Card, 4, 8), discards: []Card{"d1", "d2", "d3"}, rng: rand.New(rand.NewSource(1))}
+	copy(g.Punchlines, []Card{"1", "2", "3", "4"})
+	g.Punchlines = g.Punchlines[:2]
+	deck := &g.Punchlines[:1][0]
+	g.reshuffle()
+	assert.ElementsMatch(t, []Card{"d1", "d2", "d3"}, g.Punchlines[:3])
+	assert.Equal(t, []Card{"1", "2"}, g.Punchlines[3:], "dealt first")
+	assert.True(t, deck == &g.Punchlines[0], "not reallocated")
+	assert.Empty(t, g.discards)
+	assert.Equal(t, 3, cap(g.discards), "kept for the next discards")
+
+	// with nothing discarded there's nothing to fall back on
+	g = &Game{Players: []Player{{Name: "al"}}, Punchlines: []Card{"1", "2"}}
+	assert.Equal(t, ErrTooFewPunchlines, g.dealPunchlines())
+}
@@ -0,0 +1,101 @@
+package game
+
+import "context"
+
+// ExtendRounds adds n rounds after the last one. Only the host can, and
+// only between rounds; a finished game can start a Rematch instead. The new
+// rounds get setups not yet dealt in this game, fetching the deck again if
+// the unused ones run short, and the game may not end up longer than the
+// configured MaxRounds. Games played to a target score add their own rounds
+// and return ErrTargetScore.
+func (g *Game) ExtendRounds(ctx context.Context, playerName string, n int) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.isHost(playerName) {
+		return ErrNotHost
+	}
+	if g.over() {
+		return ErrGameFinished
+	}
+	if g.suddenDeath() {
+		return ErrSuddenDeath
+	}
+	if g.TargetScore > 0 {
+		return ErrTargetScore
+	}
+	if err := ValidateRounds(n); err != nil {
+		return err
+	}
+	if err := ValidateRounds(len(g.Rounds) + n); err != nil {
+		return err
+	}
+	if !g.betweenRounds() {
+		return ErrMidRound
+	}
+	needed := n * g.setupSize()
+	if len(g.setupPool) < needed {
+		rated, err := getRatedCards(ctx, g.source, SetupDeck, g.Cleanliness)
+		if err != nil {
+			return err
+		}
+		g.refillSetupPool(g.rate(rated), needed)
+	}
+	if len(g.setupPool) < needed {
+		return ErrTooFewSetups
+	}
+
+	added := make([]Round, n)
+	for i := len(added) - 1; i >= 0; i-- {
+		added[i].Setup = g.drawSetup()
+	}
+	g.prependRounds(added)
+	return nil
+}
+
+// prependRounds adds rounds after the last one. Rounds run from the end of
+// the slice, so the new ones go at the front and everything kept by index
+// moves along.
+func (g *Game) prependRounds(added []Round) {
+	n := len(added)
+	g.Rounds = append(added, g.Rounds...)
+	g.RoundsRemaining += n
+	timings := make(map[int]*roundTiming, len(g.timings))
+	for index, timing := range g.timings {
+		timings[index+n] = timing
+	}
+	g.timings = timings
+}
+
+// refillSetupPool adds setups that aren't already dealt in this game or
+// waiting in its pool, shuffled, under the existing pool. Setups from
+// earlier games, see withoutSetups and WithSession, are left out too while
+// there are enough others to make needed.
+func (g *Game) refillSetupPool(setups []Card, needed int) {
+	taken := make(map[Card]bool, len(g.Rounds)*g.setupSize()+len(g.setupPool))
+	for _, round := range g.Rounds {
+		for _, setup := range round.Setup {
+			taken[setup] = true
+		}
+	}
+	for _, setup := range g.setupPool {
+		taken[setup] = true
+	}
+	var fresh, unused []Card
+	for _, setup := range setups {
+		if taken[setup] {
+			continue
+		}
+		taken[setup] = true
+		fresh = append(fresh, setup)
+		if !g.usedSetups[setup] {
+			unused = append(unused, setup)
+		}
+	}
+	if len(g.setupPool)+len(unused) >= needed {
+		fresh = unused
+	}
+	g.random().Shuffle(len(fresh), func(i, j int) {
+		fresh[i], fresh[j] = fresh[j], fresh[i]
+	})
+	g.setupPool = append(fresh, g.setupPool...)
+}
@@ -0,0 +1,68 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtendRounds(t *testing.T) {
+	defer config.Set(config.Current())
+	cfg := *config.Current()
+	cfg.MaxRounds = 12
+	cfg.MaxActions = 100
+	config.Set(&cfg)
+	source := &staticSource{}
+	for i := 0; i < 20; i++ {
+		*source = append(*source, RatedCard{Text: Card(strconv.Itoa(i)), Rating: "G"})
+	}
+	ctx := context.Background()
+	g, err := NewGame(ctx, Player{Name: "al"}, 2, "R", WithCardSource(source), WithHandSize(4), WithMinPlayers(2))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	assert.NoError(t, g.AddPlayer(Player{Name: "bob"}, ""))
+	assert.NoError(t, g.Start("al", false))
+	first, timing := g.Rounds[1].Setup, g.timings[1]
+
+	assert.Equal(t, ErrNotHost, g.ExtendRounds(ctx, "bob", 1))
+	assert.True(t, errors.Is(g.ExtendRounds(ctx, "al", 0), ErrInvalidRounds))
+	assert.True(t, errors.Is(g.ExtendRounds(ctx, "al", 11), ErrInvalidRounds), "more than MaxRounds in all")
+	al := g.Players[0].Punchlines[0]
+	assert.NoError(t, g.Play("al", al))
+	assert.Equal(t, ErrMidRound, g.ExtendRounds(ctx, "al", 1))
+	g.Players[0].Punchlines = append(g.Players[0].Punchlines, al)
+	g.Rounds[1].Plays = nil
+
+	assert.NoError(t, g.ExtendRounds(ctx, "al", 3))
+	assert.Len(t, g.Rounds, 5)
+	assert.Equal(t, 5, g.RoundsRemaining)
+	assert.Equal(t, first, g.Rounds[4].Setup, "still on the first round")
+
+	// with the unused setups gone, the deck is fetched again
+	g.setupPool = nil
+	assert.NoError(t, g.ExtendRounds(ctx, "al", 2))
+	assert.Equal(t, 7, g.RoundsRemaining)
+	assert.Equal(t, timing, g.timings[6], "the first round's timing moves with it")
+	seen := make(map[Card]bool)
+	for _, round := range g.Rounds {
+		for _, setup := range round.Setup {
+			assert.NotEmpty(t, setup)
+			assert.False(t, seen[setup], "setup %s dealt twice", setup)
+			seen[setup] = true
+		}
+	}
+	assert.Equal(t, ErrTooFewSetups, g.ExtendRounds(ctx, "al", 4), "only 6 setups left")
+
+	for !g.Finished {
+		al, bob := g.Players[0].Punchlines[0], g.Players[1].Punchlines[0]
+		assert.NoError(t, g.Play("al", al))
+		assert.NoError(t, g.Play("bob", bob))
+		assert.NoError(t, g.Vote("al", bob))
+		assert.NoError(t, g.Vote("bob", al))
+	}
+	assert.Equal(t, ErrGameFinished, g.ExtendRounds(ctx, "al", 1))
+}
@@ -0,0 +1,126 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+)
+
+var (
+	ErrInvalidDeckURL = errors.New("deck urls must be http or https urls")
+	ErrPrivateAddress = errors.New("url must be for a public address")
+)
+
+// nonPublicNets are the addresses player-given urls may not reach, besides
+// loopback, link-local and multicast ones, which net.IP reports itself.
+var nonPublicNets = parseNets(
+	"0.0.0.0/8",      // this network
+	"10.0.0.0/8",     // private
+	"100.64.0.0/10",  // carrier-grade NAT
+	"172.16.0.0/12",  // private
+	"192.168.0.0/16", // private
+	"fc00::/7",       // unique local
+)
+
+// publicTransport is for urls players give, such as custom decks and
+// webhooks. It won't connect to addresses that aren't public, so those urls
+// can't reach the server's own network, unless the host is one of the
+// configured FetchAllowedHosts. The address checked is the one dialed, so
+// it holds for every redirect and for names that resolve differently later.
+var publicTransport = &http.Transport{
+	DialContext:           dialPublic,
+	MaxIdleConns:          100,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: time.Second,
+}
+
+func dialPublic(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowedHost(host) {
+		dialer.Control = func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return ErrPrivateAddress
+			}
+			return nil
+		}
+	}
+	return dialer.DialContext(ctx, network, addr)
+}
+
+// checkPublicURL reports whether u is an http or https url that isn't
+// plainly for a non-public address. Names are checked as they're dialed,
+// see publicTransport.
+func checkPublicURL(u *url.URL, invalid error) error {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return invalid
+	}
+	host := strings.ToLower(u.Hostname())
+	if allowedHost(host) {
+		return nil
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateAddress
+	}
+	if ip := net.ParseIP(host); ip != nil && !publicIP(ip) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// ValidateDeckURL checks a custom deck's url, see publicTransport.
+func ValidateDeckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ErrInvalidDeckURL
+	}
+	return checkPublicURL(u, ErrInvalidDeckURL)
+}
+
+func allowedHost(host string) bool {
+	for _, allowed := range config.Current().FetchAllowedHosts {
+		if strings.EqualFold(host, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func publicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, n := range nonPublicNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseNets(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets[i] = n
+	}
+	return nets
+}
@@ -1,35 +1,129 @@
 package game
 
 import (
-	"encoding/csv"
+	"context"
 	"errors"
-	"io"
 	"log"
 	"math/rand"
-	"os"
-	"strings"
+	"net/http"
+	"sync"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stinkyfingers/differencebetween/api/flags"
+	"github.com/stinkyfingers/differencebetween/api/metrics"
 )
 
 type Game struct {
-	ID              int       `json:"id"`
-	Players         []Player  `json:"players"`
-	Punchlines      []Card    `json:"punchlines"`
-	Rounds          []Round   `json:"rounds"`
-	RoundsRemaining int       `json:"roundsRemaining"` // zero indexed
-	CurrentAction   string    `json:"currentAction"`   // play or vote
-	Created         time.Time `json:"-"`
+	ID              int           `json:"id"`
+	Code            string        `json:"code"` // to join by, see GetGameByCode
+	Host            string        `json:"host"` // can kick, start and change settings, see Kick
+	Players         []Player      `json:"players"`
+	Spectators      []string      `json:"spectators"` // watching, see AddSpectator
+	Punchlines      []Card        `json:"punchlines"`
+	Rounds          []Round       `json:"rounds"`
+	RoundsRemaining int           `json:"roundsRemaining"`       // zero indexed
+	TargetScore     int           `json:"targetScore,omitempty"` // rounds a player must win, see WithTargetScore
+	RoundLimit      int           `json:"roundLimit,omitempty"`  // most rounds a game played to a target score may go on for
+	CurrentAction   string        `json:"currentAction"`         // play or vote, lobby until the game starts, finished after the last round
+	Finished        bool          `json:"finished"`              // the last round has been settled
+	FinalWinners    []string      `json:"winners"`               // top scorers once finished, see Winners
+	TieBreak        string        `json:"tieBreak"`              // how a tie for the top score is settled, see WithTieBreak
+	TieBrokenBy     string        `json:"tieBrokenBy,omitempty"` // how it was, if there was one, see finalWinners
+	SuddenDeath     []string      `json:"suddenDeath,omitempty"` // the tied players, once sudden death starts
+	Unready         []string      `json:"unready,omitempty"`     // players the host started without
+	Cleanliness     string        `json:"cleanliness"`           // highest card rating dealt
+	Features        []string      `json:"features,omitempty"`
+	Replenish       bool          `json:"replenish"`                // fetch more punchlines when low
+	AutoPlay        bool          `json:"autoPlay"`                 // play for players who time out
+	AutoVote        bool          `json:"autoVote"`                 // vote for players who time out
+	Locale          string        `json:"locale"`                   // for generated text, see WithLocale
+	League          string        `json:"league,omitempty"`         // groups recurring games, see WithLeague
+	Session         string        `json:"session,omitempty"`        // games played back to back, see WithSession
+	VotingMode      string        `json:"votingMode"`               // VotingSingle, VotingRanked, VotingJudge or VotingMulti
+	RankCount       int           `json:"rankCount,omitempty"`      // cards each voter ranks, in ranked games
+	VotesPerPlayer  int           `json:"votesPerPlayer,omitempty"` // cards each voter picks, in multi games
+	WriteIns        int           `json:"writeIns,omitempty"`       // punchlines each player may write in, see WithWriteIns
+	SetupSize       int           `json:"setupSize,omitempty"`      // setups in each round, 2 if not set, see WithSetupSize
+	PairPlay        bool          `json:"pairPlay,omitempty"`       // players play a card per setup, see WithPairPlay
+	AudienceWeight  float64       `json:"audienceWeight,omitempty"` // of each spectator's vote, 0 if they can't, see WithAudienceVoting
+	AudiencePooled  bool          `json:"audiencePooled,omitempty"` // AudienceWeight is shared by the whole audience
+	HandSize        int           `json:"handSize"`                 // cards dealt to each player, see WithHandSize
+	MinPlayers      int           `json:"minPlayers"`               // Start fails with ErrNotEnoughPlayers below this
+	MaxPlayers      int           `json:"maxPlayers"`               // AddPlayer fails with ErrGameFull beyond this
+	PlaySeconds     int           `json:"playSeconds"`              // play phase time limit, 0 for none
+	VoteSeconds     int           `json:"voteSeconds"`              // vote phase time limit, 0 for none
+	PhaseDeadline   *time.Time    `json:"phaseDeadline,omitempty"`  // when CurrentAction times out
+	SkipsUsed       int           `json:"skipsUsed"`                // setups redrawn, see SkipSetup
+	RematchID       *int          `json:"rematchId,omitempty"`      // the game Rematch started after this one
+	Teams           bool          `json:"teams,omitempty"`          // played in teams, see WithTeams
+	Paused          bool          `json:"paused,omitempty"`         // see Pause
+	PausedBy        string        `json:"pausedBy,omitempty"`       // the host who paused it
+	PausedAt        *time.Time    `json:"pausedAt,omitempty"`
+	Messages        []ChatMessage `json:"messages,omitempty"` // the latest chat, see PostMessage
+	Created         time.Time     `json:"-"`
+
+	mu         *gameLock // taken by the methods that change the game
+	views      *viewCache
+	source     CardSource
+	setupPool  []Card               // unused setups, shuffled
+	discards   []Card               // punchlines played in settled rounds, see reshuffle
+	usedSetups map[Card]bool        // dealt in earlier games, see withoutSetups and WithSession
+	judged     map[string]bool      // who's judged since everyone last had a turn, see assignJudge
+	actions    map[string]actionLog // per player, for rate limiting
+	rng        *rand.Rand
+
+	replenisher *replenisher
+	webhook     *webhook
+	readyCheck  bool                 // see WithReadyCheck
+	pinHash     string               // needed to join, see WithPIN
+	observers   []ObserverKey        // read-only keys, see AddObserver
+	ratings     map[Card]string      // of the cards the game has had, see SetCleanliness
+	timings     map[int]*roundTiming // by index in Rounds, for Pacing
+	tokenGens   map[string]int       // by name, see TokenGeneration
+	specTokens  map[string]string    // spectators' token hashes by name, see IssueToken
+	abandoned   bool                 // everyone left, see ReapExpired
 }
 
 type Round struct {
-	Setup [2]Card         `json:"setup"`
-	Plays map[string]Card `json:"plays"` // Player:Card
-	Votes map[string]Card `json:"votes"` // Player:Card
+	Setup  []Card          `json:"setup"`            // SetupSize cards, see WithSetupSize
+	Plays  map[string]Card `json:"plays"`            // Player:Card, hidden until the round is settled, see MarshalJSON
+	Ballot []Card          `json:"ballot,omitempty"` // the plays in a random order, from the vote phase on
+	Votes  map[string]Card `json:"votes"`            // Player:Card, their first choice in ranked games
+	Judge  string          `json:"judge,omitempty"`  // picks the winner in judge games, see WithJudge
+
+	SkipRequests []string `json:"skipRequests,omitempty"` // players asking for new setups, see SkipSetup
+
+	Rankings map[string][]Card `json:"rankings,omitempty"` // Player:Cards, best first in ranked games, as picked in multi games
+	Tallies  map[Card]int      `json:"tallies,omitempty"`  // Card:points once a ranked or multi round ends
+
+	VoteCounts map[Card]int `json:"voteCounts,omitempty"` // Card:votes once the round ends, first choices in ranked rounds
+	Results    []CardResult `json:"results,omitempty"`    // every play, best first, once the round ends
+
+	AutoPlayed  map[string]bool `json:"autoPlayed,omitempty"`  // Player:true if their play was made for them
+	AutoVoted   map[string]bool `json:"autoVoted,omitempty"`   // Player:true if their vote was cast for them
+	Winner      string          `json:"winner,omitempty"`      // empty until voting ends, or on a tie
+	Winners     []string        `json:"winners,omitempty"`     // everyone whose card tied for the top, each scoring a point
+	WinningCard Card            `json:"winningCard,omitempty"` // empty on a tie
+	Comment     string          `json:"comment,omitempty"`     // the winner's one-liner
+	Chaos       string          `json:"chaos,omitempty"`       // the chaos modifier applied as the round started
+
+	WinningTeams []string `json:"winningTeams,omitempty"` // the winners' teams, each scoring a point, see WithTeams
+
+	Reactions map[Card]map[string]string `json:"reactions,omitempty"` // Card:Player:emoji, see React
+
+	WriteIns map[string]bool `json:"writeIns,omitempty"` // Player:true if their play was written in, see PlayWriteIn
+
+	AudienceVotes map[string]Card  `json:"audienceVotes,omitempty"` // Spectator:Card, see WithAudienceVoting
+	AudienceTally map[Card]float64 `json:"audienceTally,omitempty"` // Card:weighted audience votes once the round ends
+
+	Pairs       map[string][]Card `json:"pairs,omitempty"`       // Player:Cards, one per setup, in pair-play games; hidden until the round is settled
+	BallotPairs map[Card][]Card   `json:"ballotPairs,omitempty"` // Card:Cards, the set each card on the ballot stands for, see WithPairPlay
+
+	resolved bool // see MarshalJSON
 }
 
 type Card string
@@ -37,24 +131,94 @@ type Card string
 type Player struct {
 	Name       string `json:"name"`
 	Punchlines []Card `json:"punchlines"`
+	Ready      bool   `json:"ready,omitempty"` // in the lobby of a ready check game
+	Score      int    `json:"score"`           // rounds won, including ties
+	Mulliganed bool   `json:"mulliganed"`      // has used their one Mulligan
+
+	WriteInsRemaining int `json:"writeInsRemaining,omitempty"` // see PlayWriteIn
+
+	Connected bool      `json:"connected"` // has a websocket open
+	LastSeen  time.Time `json:"lastSeen"`  // last connect or ping, see DropInactive
+
+	JoinedAtRound int    `json:"joinedAtRound,omitempty"` // first round number played, 0 if there from the start
+	Waiting       bool   `json:"waiting,omitempty"`       // joined mid-round, so sitting out until the next
+	Team          string `json:"team,omitempty"`          // in games played in teams, see WithTeams
+	TokenHash     string `json:"-"`
+	ReconnectHash string `json:"-"` // see IssueReconnectToken
 }
 
 type Play struct {
 	Name      string `json:"name"`
 	Punchline Card   `json:"punchline"`
 	Vote      Card   `json:"vote"`
+	Votes     []Card `json:"votes,omitempty"` // ranked, best first, instead of Vote
 	Ping      string `json:"ping"`
+	Retract   bool   `json:"retract,omitempty"` // take back this round's play, see RetractPlay
+	WriteIn   string `json:"writeIn,omitempty"` // played instead of Punchline, see PlayWriteIn
+}
+
+// Option customizes a game at creation.
+type Option func(*Game)
+
+// WithCardSource makes the game draw its decks from source instead of the
+// server-wide default, e.g. for a custom deck hosted at a URL.
+func WithCardSource(source CardSource) Option {
+	return func(g *Game) {
+		g.source = source
+	}
+}
+
+// WithSeed seeds the game's random source, which shuffles the decks, picks
+// the id and join code and rolls chaos, so the same seed and decks give the
+// same game.
+func WithSeed(seed int64) Option {
+	return func(g *Game) {
+		g.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithFeatures opts the game into feature flags. Callers should check
+// flags.Check first; HasFeature also requires the flag to still be enabled.
+func WithFeatures(names ...string) Option {
+	return func(g *Game) {
+		g.Features = append(g.Features, names...)
+	}
+}
+
+// HasFeature reports whether the game opted into name and it's enabled
+// server-wide.
+func (g *Game) HasFeature(name string) bool {
+	if !flags.Enabled(name) {
+		return false
+	}
+	for _, feature := range g.Features {
+		if feature == name {
+			return true
+		}
+	}
+	return false
 }
 
 var (
-	s3Client s3iface.S3API
+	s3Client   s3iface.S3API
+	cardSource CardSource
 
 	ErrTooFewSetups     = errors.New("not enough setup cards")
 	ErrTooFewPunchlines = errors.New("not enough punchline cards")
 	ErrNoGamesAvailable = errors.New("no game ids are available")
 	ErrMalformedCSV     = errors.New("malformed csv file")
+	ErrMalformedJSON    = errors.New("malformed json file")
+	ErrDeckTooLarge     = errors.New("deck file is too large")
+	ErrUnknownDeck      = errors.New("unknown deck")
+	ErrTooManyRedirects = errors.New("too many redirects fetching deck")
+	ErrPlayerNotFound   = errors.New("player does not exist")
+	ErrGameNotFound     = errors.New("game does not exist")
+	ErrGameFinished     = errors.New("game has finished")
+	ErrCardNotInHand    = errors.New("card is not in your hand")
+	ErrWrongPhase       = errors.New("that action isn't allowed in this phase of the round")
 
-	games = make(map[int]*Game)
+	games   = make(map[int]*Game)
+	gamesMu sync.RWMutex // guards games and tombstones, not the games themselves
 )
 
 const (
@@ -69,9 +233,10 @@ const (
 
 	region = "us-west-1"
 
-	handSize = 6
 	PLAY     = "play"
 	VOTE     = "vote"
+	LOBBY    = "lobby" // waiting for players, until the host calls Start
+	FINISHED = "finished"
 )
 
 func init() {
@@ -80,7 +245,8 @@ func init() {
 	// 	return
 	// }
 	var sess *session.Session
-	if os.Getenv("DIFF_ENV") == "local" {
+	cfg := config.Current()
+	if cfg.DiffEnv == "local" {
 		sess = session.Must(session.NewSessionWithOptions(session.Options{
 			Profile: "jds",
 		}))
@@ -93,213 +259,629 @@ func init() {
 	}
 	sess.Config.WithRegion(region)
 	s3Client = s3.New(sess)
+
+	if cfg.SetupsURL != "" && cfg.PunchlinesURL != "" {
+		source := NewHTTPCardSource(cfg.SetupsURL, cfg.PunchlinesURL)
+		// configured rather than given by a player, so it may be private
+		source.Client.Transport = http.DefaultTransport
+		cardSource = source
+		return
+	}
+	if bucket := cfg.GCSBucket; bucket != "" {
+		var err error
+		cardSource, err = NewGCSCardSource(context.Background(), bucket, setupsFile, punchlinesFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	cardSource = &S3CardSource{
+		Client:        s3Client,
+		Bucket:        differenceBetweenCardsBucket,
+		SetupsKey:     setupsFile,
+		PunchlinesKey: punchlinesFile,
+	}
 }
 
-func NewGame(player Player, rounds int, cleanliness string) (*Game, error) {
-	punchlines, err := getPunchlines(cleanliness)
+// NewGame creates a game of rounds, or one played to a target score, see
+// WithTargetScore, with player as its host. It waits in the
+// LOBBY, with its decks fetched and rounds set up but no cards dealt, while
+// everyone joins, until the host calls Start.
+func NewGame(ctx context.Context, player Player, rounds int, cleanliness string, opts ...Option) (*Game, error) {
+	player.Name = NormalizePlayerName(player.Name)
+	if err := ValidatePlayerName(player.Name); err != nil {
+		return nil, err
+	}
+	player.LastSeen = now()
+	g := &Game{
+		mu:              new(gameLock),
+		Host:            player.Name,
+		Players:         []Player{player},
+		Spectators:      []string{},
+		RoundsRemaining: rounds,
+		CurrentAction:   LOBBY,
+		VotingMode:      VotingSingle,
+		HandSize:        defaultHandSize,
+		TieBreak:        TieBreakShared,
+		MinPlayers:      defaultMinPlayers,
+		Locale:          DefaultLocale,
+		Cleanliness:     cleanliness,
+		source:          cardSource,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if err := g.setRounds(rounds); err != nil {
+		return nil, err
+	}
+	team, err := g.checkTeam(player.Team)
 	if err != nil {
 		return nil, err
 	}
-	setups, err := getSetups(cleanliness)
-
+	g.Players[0].Team = team
+	ratedPunchlines, err := getRatedCards(ctx, g.source, PunchlineDeck, cleanliness)
 	if err != nil {
 		return nil, err
 	}
-	id, err := findID()
+	ratedSetups, err := getRatedCards(ctx, g.source, SetupDeck, cleanliness)
 	if err != nil {
 		return nil, err
 	}
-	g := &Game{
-		ID:              id,
-		Players:         []Player{player},
-		Punchlines:      punchlines,
-		RoundsRemaining: rounds,
-		CurrentAction:   PLAY,
+	punchlines, setups := g.rate(ratedPunchlines), g.rate(ratedSetups)
+	capacity := deckCapacity(setups, punchlines, g.HandSize, g.setupSize())
+	if violations := capacity.check(g.RoundsRemaining, g.MaxPlayers); len(violations) > 0 {
+		return nil, violations[0]
 	}
+	if g.MaxPlayers == 0 {
+		g.MaxPlayers = defaultMaxPlayers
+		if g.MaxPlayers > capacity.MaxPlayers {
+			g.MaxPlayers = capacity.MaxPlayers
+		}
+	}
+	if g.MinPlayers > g.MaxPlayers {
+		g.MinPlayers = g.MaxPlayers
+	}
+	g.Punchlines = punchlines
+	g.markSeen(punchlines)
+	g.shufflePunchlines()
+	g.excludeSessionSetups()
 	err = g.createRounds(setups)
 	if err != nil {
 		return nil, err
 	}
-	err = g.dealPunchlines()
+	g.ID, err = findID(g.random())
 	if err != nil {
 		return nil, err
 	}
+	g.Created = now()
+	gamesMu.Lock()
+	err = g.takeCode()
+	if err != nil {
+		gamesMu.Unlock()
+		gameIDs.release(g.ID)
+		return nil, err
+	}
 	games[g.ID] = g
+	gamesMu.Unlock()
+	if g.Session != "" {
+		g.recordSession()
+	}
+	recordUsage(usageCreated, g)
 	return g, nil
 }
 
+// Count returns the number of games in memory.
+func Count() int {
+	gamesMu.RLock()
+	defer gamesMu.RUnlock()
+	n := 0
+	for _, g := range games {
+		if g != nil {
+			n++
+		}
+	}
+	return n
+}
+
 func GetGame(id int) (*Game, error) {
-	if g, ok := games[id]; !ok {
-		return nil, errors.New("game does not exist")
-	} else {
+	gamesMu.RLock()
+	defer gamesMu.RUnlock()
+	return getGame(id)
+}
+
+// getGame is GetGame for callers holding gamesMu.
+func getGame(id int) (*Game, error) {
+	if g := games[id]; g != nil {
 		return g, nil
 	}
+	if _, ok := tombstones[id]; ok {
+		return nil, ErrGameGone
+	}
+	return nil, ErrGameNotFound
+}
+
+// findID takes a free game id, picked with rng so a seeded game gets the
+// same id from the same pool.
+func findID(rng *rand.Rand) (int, error) {
+	return gameIDs.take(rng)
+}
+
+// deleteGame removes a game and frees its id for reuse.
+func deleteGame(id int) {
+	gamesMu.Lock()
+	defer gamesMu.Unlock()
+	g, ok := games[id]
+	if !ok {
+		return
+	}
+	delete(games, id)
+	if g != nil {
+		delete(codes, g.Code)
+	}
+	gameIDs.release(id)
 }
 
-func findID() (int, error) {
-	maxAttempts := 100
-	for i := 0; i < maxAttempts; i++ {
-		rand.Seed(time.Now().UnixNano())
-		id := rand.Intn(99)
-		if game, ok := games[id]; !ok {
-			return id, nil
-		} else if game.Created.Add(time.Hour * 12).After(time.Now()) {
-			games[id] = nil
-			return id, nil
+// liveGames returns every game in memory, for sweeps that may delete games
+// as they go.
+func liveGames() []*Game {
+	gamesMu.RLock()
+	defer gamesMu.RUnlock()
+	var live []*Game
+	for _, g := range games {
+		if g != nil {
+			live = append(live, g)
 		}
 	}
-	return 0, ErrNoGamesAvailable
+	return live
 }
 
+// createRounds deals each round SetupSize distinct setups by partially
+// shuffling setups, and keeps the unused, still shuffled rest as the game's
+// setup pool.
 func (g *Game) createRounds(setups []Card) error {
-	setupsNeeded := g.RoundsRemaining * 2
-	if setupsNeeded > len(setups) {
+	setups = uniqueCards(g.unusedSetups(setups))
+	size := g.setupSize()
+	if g.RoundsRemaining > maxRounds(len(setups), size) {
 		return ErrTooFewSetups
 	}
+	setupsNeeded := g.RoundsRemaining * size
 	g.Rounds = make([]Round, g.RoundsRemaining)
-	rand.Seed(time.Now().UnixNano())
-	setupsMap := make(map[int]Card)
+	for i := range g.Rounds {
+		g.Rounds[i].Setup = make([]Card, size)
+	}
+	rng := g.random()
 	for i := 0; i < setupsNeeded; i++ {
-		index := rand.Intn(len(setups))
-		if _, ok := setupsMap[index]; ok {
-			i--
-			continue
-		}
-		setupsMap[index] = setups[index]
-		setup := g.Rounds[i/2].Setup
-		setup[i%2] = setups[index]
-		g.Rounds[i/2].Setup = setup
+		j := i + rng.Intn(len(setups)-i)
+		setups[i], setups[j] = setups[j], setups[i]
+		g.Rounds[i/size].Setup[i%size] = setups[i]
 	}
+	g.setupPool = setups[setupsNeeded:]
 	return nil
 }
 
-func getSetups(cleanliness string) ([]Card, error) {
-	return getCardsCsv(setupsFile, cleanliness)
+func getSetups(ctx context.Context, source CardSource, cleanliness string) ([]Card, error) {
+	return getCards(ctx, source, SetupDeck, cleanliness)
 }
 
-func getPunchlines(cleanliness string) ([]Card, error) {
-	return getCardsCsv(punchlinesFile, cleanliness)
+func getPunchlines(ctx context.Context, source CardSource, cleanliness string) ([]Card, error) {
+	return getCards(ctx, source, PunchlineDeck, cleanliness)
 }
 
-func getCardsCsv(key, cleanliness string) ([]Card, error) {
-	var cards []Card
-	resp, err := s3Client.GetObject(&s3.GetObjectInput{
-		Bucket: aws.String(differenceBetweenCardsBucket),
-		Key:    aws.String(key),
-	})
+// AddPlayer joins player to the game, dealing them a hand. Games with a
+// PIN return ErrWrongPIN unless pin matches it.
+func (g *Game) AddPlayer(player Player, pin string) error {
+	player.Name = NormalizePlayerName(player.Name)
+	if err := ValidatePlayerName(player.Name); err != nil {
+		return err
+	}
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if err := g.checkPIN(pin); err != nil {
+		return err
+	}
+	team, err := g.checkTeam(player.Team)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	reader := csv.NewReader(resp.Body)
-	for {
-		line, err := reader.Read()
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return nil, err
-		}
-		if len(line) != 2 {
-			return nil, ErrMalformedCSV
-		}
-		cleanEnough, err := isCleanEnough(line[1], cleanliness)
-		if err != nil {
-			return nil, err
-		}
-		if !cleanEnough {
-			continue
-		}
-		cards = append(cards, Card(strings.TrimSpace(line[0])))
+	player.Team = team
+	return g.addPlayer(player)
+}
+
+// addPlayer is AddPlayer without the PIN, for players already let in.
+func (g *Game) addPlayer(player Player) error {
+	if g.nameTaken(player.Name) {
+		return ErrNameTaken
+	}
+	if g.full() {
+		return ErrGameFull
 	}
-	return cards, nil
+	player.JoinedAtRound = g.joinRound()
+	player.Waiting = !player.inRound(g.roundNumber())
+	player.LastSeen = now()
+	player.WriteInsRemaining = g.WriteIns
+	g.Players = append(g.Players, player)
+	if g.CurrentAction == LOBBY {
+		g.resetReady()
+		return nil
+	}
+	return g.dealPunchlines()
 }
 
-func isCleanEnough(cardCleanliness, cleanliness string) (bool, error) {
-	var ok bool
-	var cardRank, rank int
-	ranks := map[string]int{
-		"G":     0,
-		"PG":    1,
-		"PG-13": 2,
-		"R":     3,
-		"X":     4,
+// Play records playerName's card for the current round. The card must be
+// in their hand. In pair-play games it's their card for the next setup they
+// haven't answered, see WithPairPlay.
+func (g *Game) Play(playerName string, card Card) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if g.isSpectator(playerName) {
+		return ErrSpectator
 	}
-	cardRank, ok = ranks[cardCleanliness]
-	if !ok {
-		return false, ErrMalformedCSV
+	if g.over() {
+		return ErrGameFinished
 	}
-	rank, ok = ranks[cleanliness]
-	if !ok {
-		return false, ErrMalformedCSV
+	card = NormalizeCard(string(card))
+	if repeat, err := g.repeatedPlay(playerName, card); repeat {
+		return err
+	}
+	if g.Paused {
+		return ErrGamePaused
+	}
+	if g.CurrentAction != PLAY {
+		return ErrWrongPhase
+	}
+	if g.judge() == playerName {
+		return ErrJudge
+	}
+	hand, err := g.hand(playerName)
+	if err != nil {
+		return err
+	}
+	err = g.allowAction(playerName)
+	if err != nil {
+		return err
+	}
+	err = g.checkParticipant(playerName)
+	if err != nil {
+		return err
+	}
+	err = g.checkSuddenDeath(playerName)
+	if err != nil {
+		return err
 	}
-	if cardRank <= rank {
-		return true, nil
+	if !containsCard(hand, card) {
+		return ErrCardNotInHand
 	}
-	return false, nil
+	g.recordAction(playerName, PLAY)
+	g.play(playerName, card)
+	return nil
 }
 
-func (g *Game) AddPlayer(player Player) error {
-	for _, p := range g.Players {
-		if p.Name == player.Name {
-			return errors.New("player name already exists")
+// hand returns playerName's punchlines.
+func (g *Game) hand(playerName string) ([]Card, error) {
+	for _, player := range g.Players {
+		if player.Name == playerName {
+			return player.Punchlines, nil
 		}
 	}
-	g.Players = append(g.Players, player)
-	return g.dealPunchlines()
+	return nil, ErrPlayerNotFound
+}
+
+// player returns playerName's entry in g.Players, or nil.
+func (g *Game) player(playerName string) *Player {
+	for i := range g.Players {
+		if g.Players[i].Name == playerName {
+			return &g.Players[i]
+		}
+	}
+	return nil
 }
 
-func (g *Game) Play(playerName string, card Card) {
+func containsCard(cards []Card, card Card) bool {
+	for _, c := range cards {
+		if c == card {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Game) play(playerName string, card Card) {
+	g.removeFromHand(playerName, card)
 	round := g.Rounds[g.RoundsRemaining-1]
+	if g.PairPlay {
+		var done bool
+		card, done = g.playPair(&round, playerName, card)
+		if !done {
+			g.Rounds[g.RoundsRemaining-1] = round
+			return
+		}
+	}
 	if round.Plays == nil {
 		round.Plays = make(map[string]Card)
 	}
 	round.Plays[playerName] = card
 	g.Rounds[g.RoundsRemaining-1] = round
-	if len(round.Plays) == len(g.Players) {
-		g.CurrentAction = VOTE
+	if len(round.Plays) == g.playsNeeded() {
+		g.startVoting()
 	}
-	// rm used punchline
-	for i, player := range g.Players {
-		for j, punchline := range player.Punchlines {
+}
+
+// removeFromHand takes a played card out of playerName's hand, keeping the
+// rest in the order dealt.
+func (g *Game) removeFromHand(playerName string, card Card) {
+	for i := range g.Players {
+		if g.Players[i].Name != playerName {
+			continue
+		}
+		hand := g.Players[i].Punchlines
+		for j, punchline := range hand {
 			if punchline == card {
-				g.Players[i].Punchlines[j] = g.Players[i].Punchlines[len(g.Players[i].Punchlines)-1]
-				g.Players[i].Punchlines = g.Players[i].Punchlines[:len(g.Players[i].Punchlines)-1]
+				g.Players[i].Punchlines = append(hand[:j], hand[j+1:]...)
+				break
 			}
 		}
 	}
-	g.dealPunchlines()
 }
 
-func (g *Game) Vote(playerName string, card Card) {
+// Vote records playerName's vote: one card, or in ranked games up to
+// RankCount cards, best first. Only the game's players can vote, and only
+// for cards on the round's ballot. Voting again replaces their vote, until
+// the last vote settles the round.
+func (g *Game) Vote(playerName string, cards ...Card) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	spectator := g.isSpectator(playerName)
+	if spectator && !g.audienceVoting() {
+		return ErrSpectator
+	}
+	if g.over() {
+		return ErrGameFinished
+	}
+	if g.Paused {
+		return ErrGamePaused
+	}
+	if g.CurrentAction != VOTE {
+		return ErrWrongPhase
+	}
+	if spectator {
+		return g.audienceVote(playerName, cards)
+	}
+	if _, err := g.hand(playerName); err != nil {
+		return err
+	}
+	err := g.allowAction(playerName)
+	if err != nil {
+		return err
+	}
+	for i := range cards {
+		cards[i] = NormalizeCard(string(cards[i]))
+	}
+	err = g.checkParticipant(playerName)
+	if err != nil {
+		return err
+	}
+	if judge := g.judge(); judge != "" && judge != playerName {
+		return ErrNotJudge
+	}
+	if g.repeatedVote(playerName, cards) {
+		return nil
+	}
 	round := g.Rounds[g.RoundsRemaining-1]
+	err = g.checkVote(round, playerName, cards)
+	if err != nil {
+		return err
+	}
+	g.recordAction(playerName, VOTE)
 	if round.Votes == nil {
 		round.Votes = make(map[string]Card)
 	}
-	round.Votes[playerName] = card
+	round.Votes[playerName] = cards[0]
+	if g.multipleVotes() {
+		if round.Rankings == nil {
+			round.Rankings = make(map[string][]Card)
+		}
+		round.Rankings[playerName] = cards
+	}
 	g.Rounds[g.RoundsRemaining-1] = round
-	if len(round.Votes) == len(g.Players) {
-		g.RoundsRemaining--
-		g.dealPunchlines()
-		g.CurrentAction = PLAY
+	if len(round.Votes) == g.votesNeeded() {
+		g.finishRound()
+	}
+	return nil
+}
+
+// resolveRound records round's winners and scores a point for each, and
+// for each of their teams.
+func (g *Game) resolveRound(round *Round) {
+	round.resolved = true
+	round.VoteCounts = voteCounts(*round)
+	round.AudienceTally = g.audienceTally(*round)
+	round.Winners, round.WinningCard = roundWinners(*round)
+	if len(round.Winners) == 1 {
+		round.Winner = round.Winners[0]
+	}
+	round.Results = roundResults(*round)
+	for _, winner := range round.Winners {
+		for i := range g.Players {
+			if g.Players[i].Name == winner {
+				g.Players[i].Score++
+			}
+		}
 	}
+	round.WinningTeams = g.winningTeams(*round)
 }
 
+// over reports whether the game has no rounds left, whether or not it's been
+// marked Finished. Anything that changes the game checks it first, so a late
+// or repeated request, e.g. a second vote on the last round, can't reach
+// for a round that isn't there.
+func (g *Game) over() bool {
+	return g.Finished || g.RoundsRemaining <= 0
+}
+
+func (g *Game) startPlaying() {
+	if g.RoundsRemaining > 0 {
+		g.timing().PlayStarted = now()
+	}
+	g.setDeadline()
+}
+
+func (g *Game) startVoting() {
+	g.CurrentAction = VOTE
+	g.returnUnfinishedPairs(&g.Rounds[g.RoundsRemaining-1])
+	g.fillBallot(&g.Rounds[g.RoundsRemaining-1])
+	g.timing().VoteStarted = now()
+	g.setDeadline()
+}
+
+// finishRound settles the current round on the votes it has and moves on to
+// the next round's play phase, or finishes the game after the last round.
+func (g *Game) finishRound() {
+	if g.over() {
+		return
+	}
+	round := g.Rounds[g.RoundsRemaining-1]
+	switch g.VotingMode {
+	case VotingRanked:
+		round.Tallies = g.rankedTallies(round)
+	case VotingMulti:
+		round.Tallies = multiTallies(round)
+	}
+	g.resolveRound(&round)
+	g.Rounds[g.RoundsRemaining-1] = round
+	g.RoundsRemaining--
+	if g.RoundsRemaining == 0 {
+		g.addTargetRound()
+	}
+	if g.RoundsRemaining == 0 {
+		g.addSuddenDeathRound()
+	}
+	if g.RoundsRemaining > 0 {
+		g.deal(round)
+	}
+	g.seatWaiting()
+	g.assignJudge()
+	g.chaos()
+	g.CurrentAction = PLAY
+	if g.RoundsRemaining == 0 {
+		g.CurrentAction = FINISHED
+		g.Finished = true
+		g.FinalWinners, g.TieBrokenBy = g.finalWinners()
+	}
+	g.startPlaying()
+	if g.Finished {
+		recordUsage(usageFinished, g)
+		g.recordHistory()
+		g.deliverResult()
+	}
+}
+
+// deal replaces the cards played in round once it's settled, so hands stay
+// the same size during a round. Running out of punchlines leaves players
+// short rather than failing.
+func (g *Game) deal(round Round) {
+	g.discard(round)
+	for i := range g.Players {
+		if _, ok := round.Plays[g.Players[i].Name]; !ok || round.WriteIns[g.Players[i].Name] {
+			continue
+		}
+		err := g.topUp(&g.Players[i])
+		if err != nil {
+			metrics.CountError("deal")
+			break
+		}
+	}
+	g.maybeReplenish()
+}
+
+// gameLock is a game's lock. Every Lock counts as a change to the game,
+// see ViewJSON, so methods that only read and are called often take it with
+// lockToRead instead.
+type gameLock struct {
+	mu      sync.Mutex
+	version uint64
+}
+
+func (l *gameLock) Lock() {
+	l.mu.Lock()
+	l.version++
+}
+
+func (l *gameLock) Unlock() {
+	l.mu.Unlock()
+}
+
+// lockToRead takes the lock without counting as a change.
+func (l *gameLock) lockToRead() {
+	l.mu.Lock()
+}
+
+// mutex returns the game's lock, creating it on first use. NewGame creates
+// it, so that only happens for games built directly, before they're shared.
+func (g *Game) mutex() *gameLock {
+	if g.mu == nil {
+		g.mu = new(gameLock)
+	}
+	return g.mu
+}
+
+// seeds hands out a distinct seed to each game's random source, so games
+// created in the same nanosecond still get different decks.
+var seeds = struct {
+	sync.Mutex
+	rng *rand.Rand
+}{rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// random returns the game's random source, creating it on first use.
+func (g *Game) random() *rand.Rand {
+	if g.rng == nil {
+		seeds.Lock()
+		seed := seeds.rng.Int63()
+		seeds.Unlock()
+		g.rng = rand.New(rand.NewSource(seed))
+	}
+	return g.rng
+}
+
+// shufflePunchlines puts the punchline deck in a random order once, so
+// dealing can take cards off the end and the whole deal follows from the
+// game's random source.
+func (g *Game) shufflePunchlines() {
+	g.random().Shuffle(len(g.Punchlines), func(i, j int) {
+		g.Punchlines[i], g.Punchlines[j] = g.Punchlines[j], g.Punchlines[i]
+	})
+}
+
+// dealPunchlines tops up every hand from the end of the shuffled deck. The
+// caller must hold the game's lock unless the game isn't shared yet.
 func (g *Game) dealPunchlines() error {
-	rand.Seed(time.Now().UnixNano())
 	for playerIndex := range g.Players {
-		cardsNeeded := handSize - len(g.Players[playerIndex].Punchlines)
-		if cardsNeeded > len(g.Punchlines) {
-			return ErrTooFewPunchlines
-		}
-		for i := 0; i < cardsNeeded; i++ {
-			index := rand.Intn(len(g.Punchlines))
-			card := g.Punchlines[index]
-			g.Punchlines[index] = g.Punchlines[len(g.Punchlines)-1]
-			g.Punchlines = g.Punchlines[:len(g.Punchlines)-1]
-			punchlines := g.Players[playerIndex].Punchlines
-			punchlines = append(punchlines, card)
-			g.Players[playerIndex].Punchlines = punchlines
+		err := g.topUp(&g.Players[playerIndex])
+		if err != nil {
+			return err
 		}
 	}
 	return nil
 }
+
+// topUp deals player back up to the game's hand size from the end of the
+// deck, reshuffling the discards into it if it runs out.
+func (g *Game) topUp(player *Player) error {
+	handSize := g.handSize()
+	cardsNeeded := handSize - len(player.Punchlines)
+	if cardsNeeded > len(g.Punchlines) {
+		g.reshuffle()
+	}
+	if cardsNeeded > len(g.Punchlines) {
+		return ErrTooFewPunchlines
+	}
+	if cap(player.Punchlines) < handSize {
+		// sized once, so topping up a hand never reallocates
+		hand := make([]Card, len(player.Punchlines), handSize)
+		copy(hand, player.Punchlines)
+		player.Punchlines = hand
+	}
+	rest := len(g.Punchlines) - cardsNeeded
+	player.Punchlines = append(player.Punchlines, g.Punchlines[rest:]...)
+	g.Punchlines = g.Punchlines[:rest]
+	return nil
+}
@@ -0,0 +1,87 @@
+package game
+
+import (
+	"strconv"
+	"testing"
+)
+
+func benchGame(players int) *Game {
+	g := &Game{
+		Rounds:          make([]Round, 1),
+		RoundsRemaining: 1,
+		CurrentAction:   PLAY,
+	}
+	for i := 0; i < 500; i++ {
+		g.Punchlines = append(g.Punchlines, Card("punchline "+strconv.Itoa(i)))
+	}
+	for i := 0; i < players; i++ {
+		g.Players = append(g.Players, Player{Name: "player " + strconv.Itoa(i)})
+	}
+	return g
+}
+
+func BenchmarkDealPunchlines(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		g := benchGame(8)
+		b.StartTimer()
+		g.dealPunchlines()
+	}
+}
+
+// BenchmarkShuffleAndDeal covers a fresh game's whole deal, including
+// seeding its random source once.
+func BenchmarkShuffleAndDeal(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		g := benchGame(10)
+		b.StartTimer()
+		g.shufflePunchlines()
+		g.dealPunchlines()
+	}
+}
+
+func BenchmarkPlay(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		g := benchGame(8)
+		g.dealPunchlines()
+		b.StartTimer()
+		for _, player := range g.Players {
+			g.Play(player.Name, player.Punchlines[0])
+		}
+	}
+}
+
+// BenchmarkReshuffle covers putting the discards back under a deck that's
+// run low, as happens every few rounds in a long game.
+func BenchmarkReshuffle(b *testing.B) {
+	g := benchGame(8)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rest := len(g.Punchlines) - 100
+		g.discards = append(g.discards, g.Punchlines[rest:]...)
+		g.Punchlines = g.Punchlines[:rest]
+		g.reshuffle()
+	}
+}
+
+// BenchmarkPushUnchanged covers sending every player the game again when
+// nothing has changed since the last push, e.g. when a spectator connects.
+func BenchmarkPushUnchanged(b *testing.B) {
+	g := benchGame(8)
+	g.dealPunchlines()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, player := range g.Players {
+			if _, err := g.ViewJSON(player.Name, HandDealt); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -1,13 +1,21 @@
 package game
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"io/ioutil"
+	"math/rand"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stinkyfingers/differencebetween/api/flags"
 	"github.com/stinkyfingers/differencebetween/api/testingsupport"
 	"github.com/stretchr/testify/assert"
 )
@@ -46,8 +54,8 @@ func TestGetCardsCsv(t *testing.T) {
 		},
 	}
 	for _, test := range tests {
-		s3Client = test.s3Client
-		cards, err := getCardsCsv("setups", test.cleanliness)
+		source := &S3CardSource{Client: test.s3Client, SetupsKey: "setups"}
+		cards, err := getSetups(context.Background(), source, test.cleanliness)
 		if test.expectedError != "" {
 			assert.EqualError(t, err, test.expectedError)
 		} else {
@@ -82,6 +90,32 @@ func TestCreateRounds(t *testing.T) {
 	}
 }
 
+func TestCreateRoundsUsesEverySetup(t *testing.T) {
+	g := Game{RoundsRemaining: 3}
+	cards := []Card{"test1", "test2", "test3", "test4", "test5", "test6"}
+	err := g.createRounds(append([]Card(nil), cards...))
+	assert.NoError(t, err)
+	var used []Card
+	for _, round := range g.Rounds {
+		used = append(used, round.Setup[0], round.Setup[1])
+	}
+	assert.ElementsMatch(t, cards, used)
+	assert.Empty(t, g.setupPool)
+}
+
+func TestCreateRoundsKeepsSetupPool(t *testing.T) {
+	g := Game{RoundsRemaining: 2, rng: rand.New(rand.NewSource(1))}
+	cards := []Card{"test1", "test2", "test3", "test4", "test5", "test6", "test7"}
+	err := g.createRounds(append([]Card(nil), cards...))
+	assert.NoError(t, err)
+	used := append([]Card(nil), g.setupPool...)
+	for _, round := range g.Rounds {
+		used = append(used, round.Setup[0], round.Setup[1])
+	}
+	assert.ElementsMatch(t, cards, used)
+	assert.Len(t, g.setupPool, 3)
+}
+
 func TestErrCreateRounds(t *testing.T) {
 	g := Game{
 		RoundsRemaining: 3,
@@ -155,10 +189,497 @@ func TestDealPunchlines(t *testing.T) {
 
 func TestLive(t *testing.T) {
 	t.Skip("skip live test")
-	setups, err := getSetups("R")
+	setups, err := getSetups(context.Background(), cardSource, "R")
 	if err != nil {
 		t.Error(err)
 	}
 
 	t.Log(setups)
 }
+
+func TestPlayNormalizesClientCard(t *testing.T) {
+	g := Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"Caf\u00e9", "x"}},
+			{Name: "bob", Punchlines: []Card{"They're the same", "y"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"},
+		Rounds:          make([]Round, 1),
+		RoundsRemaining: 1,
+		CurrentAction:   PLAY,
+	}
+	g.Play("al", "Cafe\u0301\u00a0")
+	g.Play("bob", "They\u2019re the same")
+	assert.Equal(t, Card("Caf\u00e9"), g.Rounds[0].Plays["al"])
+	assert.Equal(t, Card("They're the same"), g.Rounds[0].Plays["bob"])
+	assert.NotContains(t, g.Players[0].Punchlines, Card("Caf\u00e9"))
+	assert.NotContains(t, g.Players[1].Punchlines, Card("They're the same"))
+
+	g.Vote("al", "They\u2019re the same")
+	assert.Equal(t, Card("They're the same"), g.Rounds[0].Votes["al"])
+}
+
+func TestActionRateLimit(t *testing.T) {
+	start := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	clock := start
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	maxActions, actionWindow := config.Current().MaxActions, config.Current().ActionWindow
+	g := Game{}
+	for i := 0; i < maxActions; i++ {
+		assert.NoError(t, g.allowAction("al"))
+		clock = clock.Add(time.Millisecond * 100)
+	}
+	assert.Equal(t, ErrTooManyActions, g.allowAction("al"))
+	assert.NoError(t, g.allowAction("bob"), "limits are per player")
+
+	// the oldest action leaves the window, freeing one slot
+	clock = start.Add(actionWindow)
+	assert.NoError(t, g.allowAction("al"))
+	assert.Equal(t, ErrTooManyActions, g.allowAction("al"))
+	assert.Len(t, g.actions["al"], maxActions)
+
+	clock = clock.Add(actionWindow)
+	for i := 0; i < maxActions; i++ {
+		assert.NoError(t, g.allowAction("al"))
+	}
+}
+
+func TestPlayRateLimited(t *testing.T) {
+	g := Game{
+		Players:         []Player{{Name: "al", Punchlines: []Card{"a1"}}, {Name: "bob", Punchlines: []Card{"b1"}}},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"},
+		Rounds:          make([]Round, 1),
+		RoundsRemaining: 1,
+		CurrentAction:   PLAY,
+		actions:         map[string]actionLog{"al": make(actionLog, config.Current().MaxActions)},
+	}
+	for i := range g.actions["al"] {
+		g.actions["al"][i] = time.Now()
+	}
+	assert.Equal(t, ErrTooManyActions, g.Play("al", "a1"))
+	assert.Empty(t, g.Rounds[0].Plays)
+	assert.NoError(t, g.Play("bob", "b1"))
+}
+
+func TestPlayChecksHand(t *testing.T) {
+	g := Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+		Rounds:          make([]Round, 1),
+		RoundsRemaining: 1,
+		CurrentAction:   PLAY,
+	}
+	assert.Equal(t, ErrCardNotInHand, g.Play("al", "b1"))
+	assert.Equal(t, ErrCardNotInHand, g.Play("al", "never dealt"))
+	assert.Equal(t, ErrPlayerNotFound, g.Play("carl", "a1"))
+	assert.Empty(t, g.Rounds[0].Plays)
+	assert.Equal(t, []Card{"a1", "a2"}, g.Players[0].Punchlines)
+
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.Equal(t, PLAY, g.CurrentAction, "still waiting on bob")
+}
+
+func TestHasFeature(t *testing.T) {
+	defer config.Set(config.Current())
+	config.Set(&config.Config{Features: map[string]bool{flags.CzarMode: true}})
+
+	g := Game{}
+	WithFeatures(flags.CzarMode, flags.Chaos)(&g)
+	assert.True(t, g.HasFeature(flags.CzarMode))
+	assert.False(t, g.HasFeature(flags.Chaos), "disabled server-wide")
+	assert.False(t, (&Game{}).HasFeature(flags.CzarMode), "not opted in")
+}
+
+func TestShuffledDealIsFair(t *testing.T) {
+	deck := []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}
+	rng := rand.New(rand.NewSource(1))
+	dealt := make(map[Card]int)
+	const games = 2000
+	for i := 0; i < games; i++ {
+		g := Game{
+			Players:    []Player{{Name: "al"}},
+			Punchlines: append([]Card(nil), deck...),
+			rng:        rng,
+		}
+		g.shufflePunchlines()
+		assert.NoError(t, g.dealPunchlines())
+		for _, card := range g.Players[0].Punchlines {
+			dealt[card]++
+		}
+	}
+	// each card should be dealt in half the games; 150 is about 6.7 standard deviations
+	for _, card := range deck {
+		assert.InDelta(t, games/2, dealt[card], 150, string(card))
+	}
+}
+
+func TestSeededDealIsReproducible(t *testing.T) {
+	deal := func() []Player {
+		g := Game{
+			Players:    []Player{{Name: "al"}, {Name: "bob"}},
+			Punchlines: []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14"},
+			rng:        rand.New(rand.NewSource(42)),
+		}
+		g.shufflePunchlines()
+		assert.NoError(t, g.dealPunchlines())
+		return g.Players
+	}
+	assert.Equal(t, deal(), deal())
+}
+
+func TestWithSeed(t *testing.T) {
+	savedIDs := gameIDs
+	defer func() { gameIDs = savedIDs }()
+	source := &staticSource{}
+	for _, text := range []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"} {
+		*source = append(*source, RatedCard{Text: text, Rating: "G"})
+	}
+	newGame := func() *Game {
+		gameIDs = newIDPool(100)
+		g, err := NewGame(context.Background(), Player{Name: "al"}, 1, "R", WithCardSource(source), WithSeed(7))
+		assert.NoError(t, err)
+		assert.NoError(t, g.Start("al", false))
+		deleteGame(g.ID)
+		return g
+	}
+	g := newGame()
+	assert.Equal(t, []Card{"1", "5", "7", "2", "3", "10"}, g.Players[0].Punchlines)
+	assert.Equal(t, []Card{"7", "1"}, g.Rounds[0].Setup)
+	assert.Equal(t, 81, g.ID)
+
+	again := newGame()
+	assert.Equal(t, g.Players[0].Punchlines, again.Players[0].Punchlines)
+	assert.Equal(t, g.Rounds[0].Setup, again.Rounds[0].Setup)
+	assert.Equal(t, g.ID, again.ID)
+	assert.Equal(t, g.Code, again.Code)
+}
+
+func TestScores(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2"}},
+			{Name: "carl", Punchlines: []Card{"c1", "c2"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Play("carl", "c1"))
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("bob", "c1"))
+	assert.Empty(t, g.Rounds[1].Winners, "votes still out")
+	assert.Equal(t, VOTE, g.CurrentAction)
+	assert.NoError(t, g.Vote("carl", "b1"))
+
+	round := g.Rounds[1]
+	assert.Equal(t, PLAY, g.CurrentAction)
+	assert.Equal(t, "bob", round.Winner)
+	assert.Equal(t, []string{"bob"}, round.Winners)
+	assert.Equal(t, Card("b1"), round.WinningCard)
+	assert.Equal(t, map[Card]int{"b1": 2, "c1": 1}, round.VoteCounts)
+	assert.Equal(t, []int{0, 1, 0}, scores(g))
+
+	// a three-way tie scores everyone
+	assert.NoError(t, g.Play("al", "a2"))
+	assert.NoError(t, g.Play("bob", "b2"))
+	assert.NoError(t, g.Play("carl", "c2"))
+	assert.NoError(t, g.Vote("al", "b2"))
+	assert.NoError(t, g.Vote("bob", "c2"))
+	assert.NoError(t, g.Vote("carl", "a2"))
+	round = g.Rounds[0]
+	assert.Equal(t, "", round.Winner)
+	assert.Equal(t, []string{"al", "bob", "carl"}, round.Winners)
+	assert.Equal(t, Card(""), round.WinningCard)
+	assert.Equal(t, map[Card]int{"a2": 1, "b2": 1, "c2": 1}, round.VoteCounts)
+	assert.Equal(t, []int{1, 2, 1}, scores(g))
+
+	// history survives a trip through the game's JSON
+	b, err := json.Marshal(g)
+	assert.NoError(t, err)
+	var decoded Game
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	for i, round := range decoded.Rounds {
+		assert.Equal(t, g.Rounds[i].Plays, round.Plays)
+		assert.Equal(t, g.Rounds[i].Winner, round.Winner)
+		assert.Equal(t, g.Rounds[i].Winners, round.Winners)
+		assert.Equal(t, g.Rounds[i].WinningCard, round.WinningCard)
+		assert.Equal(t, g.Rounds[i].VoteCounts, round.VoteCounts)
+	}
+	again, err := json.Marshal(&decoded)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(b), string(again))
+}
+
+func TestWinners(t *testing.T) {
+	newGame := func() *Game {
+		return &Game{
+			Players: []Player{
+				{Name: "al", Punchlines: []Card{"a1", "a2"}},
+				{Name: "bob", Punchlines: []Card{"b1", "b2"}},
+				{Name: "carl", Punchlines: []Card{"c1", "c2"}},
+			},
+			Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+			Rounds:          make([]Round, 1),
+			RoundsRemaining: 1,
+			CurrentAction:   PLAY,
+		}
+	}
+	g := newGame()
+	_, err := g.Winners()
+	assert.Equal(t, ErrNotFinished, err)
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Play("carl", "c1"))
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("bob", "a1"))
+	assert.NoError(t, g.Vote("carl", "b1"))
+	winners, err := g.Winners()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bob"}, names(winners))
+	assert.Equal(t, []string{"bob"}, g.FinalWinners)
+
+	// a three-way tie
+	g = newGame()
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Play("carl", "c1"))
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("bob", "c1"))
+	assert.NoError(t, g.Vote("carl", "a1"))
+	winners, err = g.Winners()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"al", "bob", "carl"}, names(winners))
+
+	// nobody voted
+	g = newGame()
+	g.finishRound()
+	winners, err = g.Winners()
+	assert.NoError(t, err)
+	assert.Empty(t, winners)
+	assert.Equal(t, []string{}, g.FinalWinners)
+}
+
+func names(players []Player) []string {
+	var names []string
+	for _, player := range players {
+		names = append(names, player.Name)
+	}
+	return names
+}
+
+func TestFinished(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+		Rounds:          make([]Round, 1),
+		RoundsRemaining: 1,
+		CurrentAction:   PLAY,
+	}
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.False(t, g.Finished)
+	assert.NoError(t, g.Vote("bob", "b1"))
+	assert.True(t, g.Finished)
+	assert.Equal(t, FINISHED, g.CurrentAction)
+	assert.Equal(t, 0, g.RoundsRemaining)
+	assert.Nil(t, g.PhaseDeadline)
+
+	hand := append([]Card(nil), g.Players[0].Punchlines...)
+	assert.Equal(t, ErrGameFinished, g.Play("al", hand[0]))
+	assert.Equal(t, ErrGameFinished, g.Vote("al", "b1"))
+	assert.Equal(t, hand, g.Players[0].Punchlines)
+	assert.Equal(t, map[string]Card{"al": "b1", "bob": "b1"}, g.Rounds[0].Votes)
+}
+
+func TestNoRoundsLeft(t *testing.T) {
+	// a client submitting the last vote twice
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+		Rounds:          make([]Round, 1),
+		RoundsRemaining: 1,
+		CurrentAction:   PLAY,
+	}
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("bob", "a1"))
+	assert.Equal(t, ErrGameFinished, g.Vote("bob", "a1"))
+	assert.Equal(t, 0, g.RoundsRemaining)
+	assert.Equal(t, ErrGameFinished, g.Play("al", "a2"))
+
+	// out of rounds without being marked finished, e.g. from older state
+	g = &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2"}},
+		},
+		Rounds:        make([]Round, 1),
+		CurrentAction: VOTE,
+	}
+	assert.Equal(t, ErrGameFinished, g.Play("al", "a1"))
+	assert.Equal(t, ErrGameFinished, g.Vote("al", "b1"))
+	assert.Equal(t, ErrGameFinished, g.SkipSetup("al"))
+	assert.Equal(t, ErrGameFinished, g.Mulligan("al"))
+	assert.Equal(t, ErrGameFinished, g.SetTimers("al", 30, 30))
+	g.finishRound()
+	g.expire()
+	assert.NoError(t, g.RemovePlayer("bob"))
+	assert.Equal(t, 0, g.RoundsRemaining)
+}
+
+func TestPlayLeavesOtherHandsAlone(t *testing.T) {
+	g := Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"same", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "same", "b3", "b4", "b5", "b6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+		Rounds:          make([]Round, 1),
+		RoundsRemaining: 1,
+		CurrentAction:   PLAY,
+	}
+	assert.NoError(t, g.Play("al", "same"))
+	assert.Equal(t, []Card{"a2", "a3", "a4", "a5", "a6"}, g.Players[0].Punchlines)
+	assert.Equal(t, []Card{"b1", "same", "b3", "b4", "b5", "b6"}, g.Players[1].Punchlines)
+}
+
+func TestDealAfterRound(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}, JoinedAtRound: 2},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.Len(t, g.Players[0].Punchlines, defaultHandSize-1, "not until the round is settled")
+	assert.Len(t, g.Punchlines, 6)
+
+	assert.NoError(t, g.Vote("bob", "a1"))
+	assert.Equal(t, []Card{"a2", "a3", "a4", "a5", "a6", "6"}, g.Players[0].Punchlines)
+	assert.Equal(t, []Card{"b2", "b3", "b4", "b5", "b6", "5"}, g.Players[1].Punchlines)
+	assert.Equal(t, []Card{"c1", "c2", "c3", "c4", "c5", "c6"}, g.Players[2].Punchlines, "carl didn't play")
+	assert.Equal(t, []Card{"1", "2", "3", "4"}, g.Punchlines)
+}
+
+func TestWrongPhase(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2"}},
+			{Name: "carl", Punchlines: []Card{"c1", "c2"}, JoinedAtRound: 2},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	assert.Equal(t, ErrWrongPhase, g.Vote("al", "b1"), "nothing played")
+	assert.NoError(t, g.Play("al", "a1"))
+	before := g.Rounds[1]
+	assert.Equal(t, ErrWrongPhase, g.Vote("bob", "a1"), "bob hasn't played")
+	assert.Equal(t, before, g.Rounds[1])
+	assert.Nil(t, g.Rounds[1].Votes)
+	assert.Equal(t, PLAY, g.CurrentAction)
+
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.Equal(t, ErrWrongPhase, g.Play("carl", "c1"), "voting has started")
+	assert.Equal(t, map[string]Card{"al": "a1", "bob": "b1"}, g.Rounds[1].Plays)
+	assert.Contains(t, g.Players[2].Punchlines, Card("c1"))
+	assert.Equal(t, VOTE, g.CurrentAction)
+}
+
+func scores(g *Game) []int {
+	var scores []int
+	for _, player := range g.Players {
+		scores = append(scores, player.Score)
+	}
+	return scores
+}
+
+func TestConcurrentActions(t *testing.T) {
+	defer config.Set(config.Current())
+	cfg := *config.Current()
+	cfg.MaxActions = 1000000
+	config.Set(&cfg)
+	source := &staticSource{}
+	for i := 0; i < 80; i++ {
+		*source = append(*source, RatedCard{Text: Card(strconv.Itoa(i)), Rating: "G"})
+	}
+	g, err := NewGame(context.Background(), Player{Name: "p0"}, 5, "R", WithCardSource(source))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+
+	for _, name := range []string{"p1", "p2", "p3"} {
+		assert.NoError(t, g.AddPlayer(Player{Name: name}, ""))
+	}
+	assert.NoError(t, g.Start("p0", false))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		name, late := "p"+strconv.Itoa(i), i > 3
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if late {
+				assert.NoError(t, g.AddPlayer(Player{Name: name}, ""), "joining mid-game")
+			}
+			for pass := 0; pass < 100; pass++ {
+				g.Ping(name)
+				for _, card := range *source {
+					if g.Play(name, card.Text) == ErrGameFinished {
+						return
+					}
+					g.Vote(name, card.Text)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	cards := len(g.Punchlines)
+	for _, player := range g.Players {
+		assert.True(t, len(player.Punchlines) <= defaultHandSize, player.Name)
+		cards += len(player.Punchlines)
+	}
+	for _, round := range g.Rounds {
+		assert.True(t, len(round.Plays) <= len(g.Players))
+		cards += len(round.Plays)
+	}
+	assert.Equal(t, len(*source), cards, "no card lost or dealt twice")
+}
+
+func TestGamesGetDistinctSeeds(t *testing.T) {
+	deck := func() []Card {
+		g := &Game{}
+		for i := 0; i < 20; i++ {
+			g.Punchlines = append(g.Punchlines, Card(strconv.Itoa(i)))
+		}
+		g.shufflePunchlines()
+		return g.Punchlines
+	}
+	assert.NotEqual(t, deck(), deck())
+}
@@ -0,0 +1,94 @@
+package game
+
+import (
+	"context"
+	"io"
+	"strconv"
+
+	"cloud.google.com/go/storage"
+)
+
+// GCSClient is the part of the Google Cloud Storage API that GCSCardSource
+// needs. NewReader returns the generation of the content it reads, which
+// may be newer than the one Generation returned before.
+type GCSClient interface {
+	Generation(ctx context.Context, bucket, object string) (int64, error)
+	NewReader(ctx context.Context, bucket, object string) (io.ReadCloser, int64, error)
+}
+
+// GCSCardSource reads decks from objects in a Google Cloud Storage bucket.
+// Decks are cached by object generation, so an unchanged deck costs a single
+// metadata read.
+type GCSCardSource struct {
+	Client           GCSClient
+	Bucket           string
+	SetupsObject     string
+	PunchlinesObject string
+
+	cache deckCache
+}
+
+// NewGCSCardSource connects to GCS with application-default credentials.
+func NewGCSCardSource(ctx context.Context, bucket, setupsObject, punchlinesObject string) (*GCSCardSource, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &GCSCardSource{
+		Client:           &gcsClient{client: client},
+		Bucket:           bucket,
+		SetupsObject:     setupsObject,
+		PunchlinesObject: punchlinesObject,
+	}, nil
+}
+
+func (g *GCSCardSource) Cards(ctx context.Context, deck Deck) ([]RatedCard, error) {
+	var object string
+	switch deck {
+	case SetupDeck:
+		object = g.SetupsObject
+	case PunchlineDeck:
+		object = g.PunchlinesObject
+	default:
+		return nil, ErrUnknownDeck
+	}
+	generation, err := g.Client.Generation(ctx, g.Bucket, object)
+	if err != nil {
+		return nil, err
+	}
+	if cached, ok := g.cache.get(object); ok && cached.version == strconv.FormatInt(generation, 10) {
+		return cached.cards, nil
+	}
+	r, generation, err := g.Client.NewReader(ctx, g.Bucket, object)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	cards, err := parseDeck(r)
+	if err != nil {
+		return nil, err
+	}
+	// the generation of what was read, in case it changed since the check
+	g.cache.put(object, strconv.FormatInt(generation, 10), cards)
+	return cards, nil
+}
+
+type gcsClient struct {
+	client *storage.Client
+}
+
+func (c *gcsClient) Generation(ctx context.Context, bucket, object string) (int64, error) {
+	attrs, err := c.client.Bucket(bucket).Object(object).Attrs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return attrs.Generation, nil
+}
+
+func (c *gcsClient) NewReader(ctx context.Context, bucket, object string) (io.ReadCloser, int64, error) {
+	r, err := c.client.Bucket(bucket).Object(object).NewReader(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return r, r.Attrs.Generation, nil
+}
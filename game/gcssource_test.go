@@ -0,0 +1,72 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stinkyfingers/differencebetween/api/testingsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGCSCardSource(t *testing.T) {
+	client := &testingsupport.GCS{
+		Objects: map[string][]byte{
+			"decks/setups.csv":     []byte("test,R\ntest2,PG"),
+			"decks/punchlines.csv": []byte(`[{"text":"json1","rating":"G"}]`),
+		},
+		Generations: map[string]int64{
+			"decks/setups.csv":     1,
+			"decks/punchlines.csv": 1,
+		},
+	}
+	source := &GCSCardSource{
+		Client:           client,
+		Bucket:           "decks",
+		SetupsObject:     "setups.csv",
+		PunchlinesObject: "punchlines.csv",
+	}
+
+	setups, err := getSetups(context.Background(), source, "PG")
+	assert.NoError(t, err)
+	assert.Equal(t, []Card{"test2"}, setups)
+	punchlines, err := getPunchlines(context.Background(), source, "R")
+	assert.NoError(t, err)
+	assert.Equal(t, []Card{"json1"}, punchlines)
+	assert.Equal(t, 2, client.Reads)
+
+	// unchanged generation is served from cache
+	_, err = getSetups(context.Background(), source, "R")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, client.Reads)
+
+	// a new generation is downloaded again
+	client.Objects["decks/setups.csv"] = []byte("test3,R")
+	client.Generations["decks/setups.csv"] = 2
+	setups, err = getSetups(context.Background(), source, "R")
+	assert.NoError(t, err)
+	assert.Equal(t, []Card{"test3"}, setups)
+	assert.Equal(t, 3, client.Reads)
+
+	// written between the generation check and the read: cached under the
+	// generation read, so the next check downloads nothing
+	client.BeforeRead = func() {
+		client.Objects["decks/setups.csv"] = []byte("test5,R")
+		client.Generations["decks/setups.csv"] = 4
+	}
+	client.Objects["decks/setups.csv"] = []byte("test4,R")
+	client.Generations["decks/setups.csv"] = 3
+	setups, err = getSetups(context.Background(), source, "R")
+	assert.NoError(t, err)
+	assert.Equal(t, []Card{"test5"}, setups)
+	assert.Equal(t, 4, client.Reads)
+	client.BeforeRead = nil
+	setups, err = getSetups(context.Background(), source, "R")
+	assert.NoError(t, err)
+	assert.Equal(t, []Card{"test5"}, setups)
+	assert.Equal(t, 4, client.Reads)
+
+	client.Err = errors.New("oh no")
+	_, err = getSetups(context.Background(), source, "R")
+	assert.EqualError(t, err, "oh no")
+}
@@ -0,0 +1,58 @@
+package game
+
+import "errors"
+
+// Hand orders for ViewFor. Hands are always kept in the order dealt, with
+// played cards removed and new cards added at the end, so HandDealt is
+// stable between fetches and only changes where a card was played.
+const (
+	HandDealt = "dealt"
+	HandAlpha = "alpha"
+)
+
+const (
+	defaultHandSize = 6
+	minHandSize     = 3
+	maxHandSize     = 12
+)
+
+var (
+	ErrInvalidHandOrder = errors.New("sort must be dealt or alpha")
+	ErrInvalidHandSize  = errors.New("hand size must be between 3 and 12")
+)
+
+// WithHandSize deals each player size cards instead of 6. Smaller hands
+// make the deck last for big groups; bigger ones give small groups more to
+// choose from. Check size with ValidateHandSize; 0 means the default.
+func WithHandSize(size int) Option {
+	return func(g *Game) {
+		if size == 0 {
+			size = defaultHandSize
+		}
+		g.HandSize = size
+	}
+}
+
+func ValidateHandSize(size int) error {
+	if size != 0 && (size < minHandSize || size > maxHandSize) {
+		return ErrInvalidHandSize
+	}
+	return nil
+}
+
+// handSize is how many cards each player holds, the default for games that
+// weren't made by NewGame.
+func (g *Game) handSize() int {
+	if g.HandSize == 0 {
+		return defaultHandSize
+	}
+	return g.HandSize
+}
+
+func ValidateHandOrder(order string) error {
+	switch order {
+	case "", HandDealt, HandAlpha:
+		return nil
+	}
+	return ErrInvalidHandOrder
+}
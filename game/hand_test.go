@@ -0,0 +1,79 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandOrder(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"c", "a", "e", "b", "f", "d"}},
+			{Name: "bob", Punchlines: []Card{"z", "y", "x", "w", "v", "u"}},
+		},
+		Punchlines:      []Card{"2", "1"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	fetch := func(order string) string {
+		j, err := json.Marshal(g.ViewFor("al", order))
+		assert.NoError(t, err)
+		return string(j)
+	}
+	for _, order := range []string{HandDealt, HandAlpha} {
+		assert.Equal(t, fetch(order), fetch(order), order)
+	}
+	assert.Equal(t, []Card{"a", "b", "c", "d", "e", "f"}, g.ViewFor("al", HandAlpha).Players[0].Punchlines)
+	assert.Equal(t, []Card{"z", "y", "x", "w", "v", "u"}, g.Players[1].Punchlines, "only al's hand")
+	assert.Equal(t, []Card{"c", "a", "e", "b", "f", "d"}, g.Players[0].Punchlines, "the game is unchanged")
+
+	assert.NoError(t, g.Play("al", "a"))
+	assert.NoError(t, g.Play("bob", "z"))
+	assert.NoError(t, g.Vote("al", "z"))
+	assert.NoError(t, g.Vote("bob", "a"))
+	assert.Equal(t, []Card{"c", "e", "b", "f", "d", "1"}, g.ViewFor("al", HandDealt).Players[0].Punchlines)
+	assert.Equal(t, []Card{"1", "b", "c", "d", "e", "f"}, g.ViewFor("al", HandAlpha).Players[0].Punchlines)
+
+	assert.NoError(t, ValidateHandOrder(""))
+	assert.Equal(t, ErrInvalidHandOrder, ValidateHandOrder("random"))
+}
+
+func TestHandSize(t *testing.T) {
+	assert.NoError(t, ValidateHandSize(0))
+	assert.NoError(t, ValidateHandSize(3))
+	assert.NoError(t, ValidateHandSize(12))
+	assert.Equal(t, ErrInvalidHandSize, ValidateHandSize(2))
+	assert.Equal(t, ErrInvalidHandSize, ValidateHandSize(13))
+
+	source := &staticSource{}
+	for _, text := range []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13"} {
+		*source = append(*source, RatedCard{Text: text, Rating: "G"})
+	}
+	g, err := NewGame(context.Background(), Player{Name: "al"}, 1, "R", WithCardSource(source), WithHandSize(3))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	assert.NoError(t, g.AddPlayer(Player{Name: "bob"}, ""))
+	assert.NoError(t, g.AddPlayer(Player{Name: "carl"}, ""))
+	assert.NoError(t, g.Start("al", false))
+	assert.Equal(t, 3, g.HandSize)
+	assert.Len(t, g.Players[0].Punchlines, 3)
+	assert.NoError(t, g.AddPlayer(Player{Name: "dee"}, ""))
+	assert.Len(t, g.Players[3].Punchlines, 3, "late joiners get the game's hand size too")
+
+	capacity, err := ValidateGameSettings(context.Background(), Settings{Rounds: 1, Cleanliness: "R", Options: []Option{WithCardSource(source), WithHandSize(12)}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, capacity.MaxPlayers)
+
+	small := &staticSource{}
+	for _, text := range []Card{"1", "2", "3", "4", "5", "6", "7", "8"} {
+		*small = append(*small, RatedCard{Text: text, Rating: "G"})
+	}
+	_, err = ValidateGameSettings(context.Background(), Settings{Rounds: 1, Cleanliness: "R", Options: []Option{WithCardSource(small), WithHandSize(10)}})
+	assert.Equal(t, Violations{ErrTooFewPunchlines}, err, "not even one hand")
+	_, err = NewGame(context.Background(), Player{Name: "al"}, 1, "R", WithCardSource(small), WithHandSize(10))
+	assert.Equal(t, ErrTooFewPunchlines, err)
+}
@@ -0,0 +1,123 @@
+package game
+
+import (
+	"sync"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+)
+
+var (
+	history   = make(map[int]GameSummary)
+	historyMu sync.Mutex
+)
+
+// GameSummary is what's kept of a finished game once it's removed: the
+// final scores and how each round's plays did, with no hands or decks.
+type GameSummary struct {
+	ID       int            `json:"id"`
+	League   string         `json:"league,omitempty"`
+	Players  []PlayerScore  `json:"players"` // the final Scoreboard
+	Rounds   []RoundSummary `json:"rounds"`  // in the order played
+	Winners  []string       `json:"winners"`
+	Finished time.Time      `json:"finished"`
+
+	TieBrokenBy string `json:"tieBrokenBy,omitempty"` // see Game.TieBrokenBy
+}
+
+type RoundSummary struct {
+	Number      int      `json:"number"`
+	Setup       []Card   `json:"setup"`
+	WinningCard Card     `json:"winningCard,omitempty"` // empty on a tie
+	Winners     []string `json:"winners,omitempty"`
+
+	Results   []CardResult               `json:"results,omitempty"`   // every play, best first
+	Reactions map[Card]map[string]string `json:"reactions,omitempty"` // Card:Player:emoji
+}
+
+// GetHistory returns the summary of the finished game with id, which stays
+// available for the configured HistoryTTL after it finishes, even once the
+// game itself is gone. If the id has since been reused, it's the summary of
+// the latest game to finish with it.
+func GetHistory(id int) (*GameSummary, error) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	summary, ok := history[id]
+	if !ok || summary.Finished.Before(now().Add(-config.Current().HistoryTTL)) {
+		return nil, ErrGameNotFound
+	}
+	return &summary, nil
+}
+
+func (g *Game) gameSummary() GameSummary {
+	summary := GameSummary{
+		ID:       g.ID,
+		League:   g.League,
+		Players:  g.scoreboard(),
+		Rounds:   []RoundSummary{},
+		Winners:  append([]string{}, g.FinalWinners...),
+		Finished: now(),
+
+		TieBrokenBy: g.TieBrokenBy,
+	}
+	for number := 1; number <= len(g.Rounds); number++ {
+		round := g.Rounds[len(g.Rounds)-number]
+		summary.Rounds = append(summary.Rounds, RoundSummary{
+			Number:      number,
+			Setup:       round.Setup,
+			WinningCard: round.WinningCard,
+			Winners:     append([]string(nil), round.Winners...),
+			Results:     append([]CardResult(nil), round.Results...),
+			Reactions:   copyReactions(round.Reactions),
+		})
+	}
+	return summary
+}
+
+// copyReactions copies a round's reactions, so its summary doesn't change
+// with the round.
+func copyReactions(reactions map[Card]map[string]string) map[Card]map[string]string {
+	if len(reactions) == 0 {
+		return nil
+	}
+	copied := make(map[Card]map[string]string, len(reactions))
+	for card, byPlayer := range reactions {
+		copied[card] = make(map[string]string, len(byPlayer))
+		for name, emoji := range byPlayer {
+			copied[card][name] = emoji
+		}
+	}
+	return copied
+}
+
+// recordHistory keeps g's summary as it finishes. Beyond the configured
+// HistorySize, the oldest summary is dropped.
+func (g *Game) recordHistory() {
+	size := config.Current().HistorySize
+	if size == 0 {
+		return
+	}
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	history[g.ID] = g.gameSummary()
+	for len(history) > size {
+		oldest := -1
+		for id, summary := range history {
+			if oldest < 0 || summary.Finished.Before(history[oldest].Finished) {
+				oldest = id
+			}
+		}
+		delete(history, oldest)
+	}
+}
+
+// reapHistory forgets summaries of games finished before cutoff.
+func reapHistory(cutoff time.Time) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	for id, summary := range history {
+		if summary.Finished.Before(cutoff) {
+			delete(history, id)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package game
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHistory(t *testing.T) {
+	start := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	clock := start
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+	defer config.Set(config.Current())
+	cfg := *config.Current()
+	cfg.GameTTL = time.Hour
+	cfg.HistoryTTL = time.Hour * 2
+	cfg.HistorySize = 2
+	config.Set(&cfg)
+	savedGames, savedIDs, savedHistory := games, gameIDs, history
+	defer func() { games, gameIDs, history = savedGames, savedIDs, savedHistory }()
+	games, gameIDs, history = make(map[int]*Game), newIDPool(3), make(map[int]GameSummary)
+
+	play := func() *Game {
+		id, err := findID(testRNG)
+		assert.NoError(t, err)
+		g := &Game{
+			ID: id,
+			Players: []Player{
+				{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+				{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			},
+			Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+			Rounds:          []Round{{Setup: []Card{"dogs", "cats"}}, {Setup: []Card{"tea", "coffee"}}},
+			RoundsRemaining: 2,
+			CurrentAction:   PLAY,
+			Created:         clock,
+		}
+		games[id] = g
+		assert.NoError(t, g.Play("al", "a1"))
+		assert.NoError(t, g.Play("bob", "b1"))
+		assert.NoError(t, g.Vote("al", "b1"))
+		assert.NoError(t, g.Vote("bob", "a1"))
+		_, err = GetHistory(id)
+		assert.Equal(t, ErrGameNotFound, err, "not finished")
+		assert.NoError(t, g.Play("al", "a2"))
+		assert.NoError(t, g.Play("bob", "b2"))
+		assert.NoError(t, g.Vote("al", "b2"))
+		assert.NoError(t, g.Vote("bob", "b2"))
+		assert.True(t, g.Finished)
+		return g
+	}
+
+	g := play()
+	clock = start.Add(time.Hour + time.Second)
+	assert.Equal(t, 1, ReapExpired())
+	_, err := GetGame(g.ID)
+	assert.Equal(t, ErrGameNotFound, err)
+
+	summary, err := GetHistory(g.ID)
+	assert.NoError(t, err, "still there once the game is gone")
+	j, err := json.Marshal(summary)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"id": `+strconv.Itoa(g.ID)+`,
+		"players": [
+			{"name": "bob", "score": 2, "roundsWon": 1, "votes": 3},
+			{"name": "al", "score": 1, "roundsWon": 0, "votes": 1}
+		],
+		"rounds": [
+			{"number": 1, "setup": ["tea", "coffee"], "winners": ["al", "bob"], "results": [
+				{"card": "a1", "player": "al", "votes": 1, "won": true},
+				{"card": "b1", "player": "bob", "votes": 1, "won": true}
+			]},
+			{"number": 2, "setup": ["dogs", "cats"], "winningCard": "b2", "winners": ["bob"], "results": [
+				{"card": "b2", "player": "bob", "votes": 2, "won": true},
+				{"card": "a2", "player": "al", "votes": 0, "won": false}
+			]}
+		],
+		"winners": ["bob"],
+		"finished": "2020-07-01T12:00:00Z"
+	}`, string(j))
+
+	// only the latest HistorySize are kept
+	second, third := play(), play()
+	_, err = GetHistory(g.ID)
+	assert.Equal(t, ErrGameNotFound, err)
+	_, err = GetHistory(second.ID)
+	assert.NoError(t, err)
+
+	clock = clock.Add(time.Hour*2 + time.Second)
+	_, err = GetHistory(third.ID)
+	assert.Equal(t, ErrGameNotFound, err, "past the HistoryTTL")
+	ReapExpired()
+	assert.Empty(t, history)
+}
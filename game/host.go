@@ -0,0 +1,47 @@
+package game
+
+import "errors"
+
+var ErrKickSelf = errors.New("the host can't kick themselves, but can leave")
+
+// host is the game's host: Host, or for games that weren't made by NewGame,
+// the first player.
+func (g *Game) host() string {
+	if g.Host == "" && len(g.Players) > 0 {
+		return g.Players[0].Name
+	}
+	return g.Host
+}
+
+func (g *Game) isHost(playerName string) bool {
+	return playerName != "" && g.host() == playerName
+}
+
+// passHost makes the longest-standing player the host once the host has
+// left, or clears Host if nobody's left.
+func (g *Game) passHost(leaving string) {
+	if g.host() != leaving {
+		return
+	}
+	g.Host = ""
+	if len(g.Players) > 0 {
+		g.Host = g.Players[0].Name
+	}
+}
+
+// Kick removes target from the game at the host's request, as RemovePlayer
+// would if they'd left. Spectators can be kicked too.
+func (g *Game) Kick(host, target string) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.isHost(host) {
+		return ErrNotHost
+	}
+	if target == host {
+		return ErrKickSelf
+	}
+	if g.removeSpectator(target) {
+		return nil
+	}
+	return g.removePlayer(target)
+}
@@ -0,0 +1,50 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHost(t *testing.T) {
+	source := &staticSource{}
+	for i := 0; i < 40; i++ {
+		*source = append(*source, RatedCard{Text: Card(strconv.Itoa(i)), Rating: "G"})
+	}
+	g, err := NewGame(context.Background(), Player{Name: "al"}, 2, "R", WithCardSource(source), WithHandSize(3))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	assert.Equal(t, "al", g.Host)
+	for _, name := range []string{"bob", "carl", "dan"} {
+		assert.NoError(t, g.AddPlayer(Player{Name: name}, ""))
+	}
+	assert.NoError(t, g.Start("al", false))
+	assert.NoError(t, g.AddSpectator("sam", ""))
+	j, err := json.Marshal(g)
+	assert.NoError(t, err)
+	assert.Contains(t, string(j), `"host":"al"`)
+
+	assert.Equal(t, ErrNotHost, g.Kick("bob", "carl"))
+	assert.Equal(t, ErrKickSelf, g.Kick("al", "al"))
+	assert.Equal(t, ErrPlayerNotFound, g.Kick("al", "eve"))
+	bob := g.Players[1].Punchlines[0]
+	assert.NoError(t, g.Play("bob", bob))
+	assert.NoError(t, g.Kick("al", "bob"))
+	assert.NoError(t, g.Kick("al", "sam"))
+	assert.Len(t, g.Players, 3)
+	assert.Empty(t, g.Spectators)
+	assert.Empty(t, g.Rounds[1].Plays, "bob's play went with him")
+	assert.Contains(t, g.Punchlines, bob)
+
+	// the host passes to whoever joined next
+	assert.NoError(t, g.RenamePlayer("al", "alan"))
+	assert.Equal(t, "alan", g.Host)
+	assert.NoError(t, g.RemovePlayer("alan"))
+	assert.Equal(t, "carl", g.Host)
+	assert.Equal(t, "carl", g.ViewFor("dan", HandDealt).Host)
+	assert.Equal(t, ErrNotHost, g.Kick("alan", "dan"))
+	assert.NoError(t, g.Kick("carl", "dan"))
+}
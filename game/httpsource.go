@@ -0,0 +1,132 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	httpSourceTimeout      = time.Second * 10
+	httpSourceMaxRedirects = 3
+)
+
+var ErrDeckUnavailable = errors.New("the deck couldn't be fetched")
+
+// HTTPCardSource reads decks from plain URLs, e.g. a raw gist or a static
+// site. Responses carrying an ETag are cached and revalidated with
+// If-None-Match, so unchanged decks are not downloaded again. The URLs may
+// come from players, so NewHTTPCardSource's client only connects to public
+// addresses, see publicTransport, and why a fetch failed is logged rather
+// than returned.
+type HTTPCardSource struct {
+	SetupsURL     string
+	PunchlinesURL string
+	Client        *http.Client
+	MaxBytes      int64
+
+	cache deckCache
+}
+
+func NewHTTPCardSource(setupsURL, punchlinesURL string) *HTTPCardSource {
+	return &HTTPCardSource{
+		SetupsURL:     setupsURL,
+		PunchlinesURL: punchlinesURL,
+		Client: &http.Client{
+			Timeout:   httpSourceTimeout,
+			Transport: publicTransport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) > httpSourceMaxRedirects {
+					return ErrTooManyRedirects
+				}
+				return nil
+			},
+		},
+		MaxBytes: maxDeckSize,
+	}
+}
+
+func (h *HTTPCardSource) Cards(ctx context.Context, deck Deck) ([]RatedCard, error) {
+	var url string
+	switch deck {
+	case SetupDeck:
+		url = h.SetupsURL
+	case PunchlineDeck:
+		url = h.PunchlinesURL
+	default:
+		return nil, ErrUnknownDeck
+	}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	cached, hasCached := h.cache.get(url)
+	if hasCached {
+		req.Header.Set("If-None-Match", cached.version)
+	}
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		for _, known := range []error{ErrTooManyRedirects, ErrPrivateAddress} {
+			if errors.Is(err, known) {
+				return nil, known
+			}
+		}
+		log.Printf("fetching %s deck: %v", deck, err)
+		return nil, ErrDeckUnavailable
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && hasCached {
+		return cached.cards, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("fetching %s deck from %s: unexpected status %d", deck, url, resp.StatusCode)
+		return nil, ErrDeckUnavailable
+	}
+	if resp.ContentLength > h.MaxBytes {
+		return nil, ErrDeckTooLarge
+	}
+	body := &limitedReader{r: resp.Body, remaining: h.MaxBytes}
+	cards, err := parseDeck(body)
+	if body.exceeded {
+		return nil, ErrDeckTooLarge
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		h.cache.put(url, etag, cards)
+	}
+	return cards, nil
+}
+
+// limitedReader is io.LimitReader that remembers whether the limit was hit,
+// so a truncated body isn't mistaken for a short deck.
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		var probe [1]byte
+		if n, _ := l.r.Read(probe[:]); n > 0 {
+			l.exceeded = true
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
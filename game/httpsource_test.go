@@ -0,0 +1,173 @@
+package game
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// allowLocalFetches lets HTTPCardSource reach httptest servers for the rest
+// of the test.
+func allowLocalFetches(t *testing.T) {
+	old := config.Current()
+	cfg := *old
+	cfg.FetchAllowedHosts = []string{"127.0.0.1"}
+	config.Set(&cfg)
+	t.Cleanup(func() { config.Set(old) })
+}
+
+func TestHTTPCardSource(t *testing.T) {
+	allowLocalFetches(t)
+	var gzipped bytes.Buffer
+	zw := gzip.NewWriter(&gzipped)
+	zw.Write([]byte(`[{"text":"json1","rating":"G"},{"text":" json2 ","rating":"R"}]`))
+	zw.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/setups.csv", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("test,R\ntest2,G"))
+	})
+	mux.HandleFunc("/punchlines.json.gz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(gzipped.Bytes())
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/redirect", http.StatusFound)
+	})
+	mux.HandleFunc("/once", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/setups.csv", http.StatusFound)
+	})
+	mux.HandleFunc("/huge", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("card,G\n", 100)))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		url           string
+		maxBytes      int64
+		expectedCards []RatedCard
+		expectedError string
+	}{
+		{
+			url:           "/setups.csv",
+			expectedCards: []RatedCard{{Text: "test", Rating: "R"}, {Text: "test2", Rating: "G"}},
+		},
+		{
+			url:           "/punchlines.json.gz",
+			expectedCards: []RatedCard{{Text: "json1", Rating: "G"}, {Text: "json2", Rating: "R"}},
+		},
+		{
+			url:           "/missing",
+			expectedError: ErrDeckUnavailable.Error(),
+		},
+		{
+			url:           "/redirect",
+			expectedError: ErrTooManyRedirects.Error(),
+		},
+		{
+			url:           "/once",
+			expectedCards: []RatedCard{{Text: "test", Rating: "R"}, {Text: "test2", Rating: "G"}},
+		},
+		{
+			url:           "/huge",
+			maxBytes:      64,
+			expectedError: ErrDeckTooLarge.Error(),
+		},
+	}
+	for _, test := range tests {
+		source := NewHTTPCardSource(server.URL+test.url, "")
+		if test.maxBytes > 0 {
+			source.MaxBytes = test.maxBytes
+		}
+		cards, err := source.Cards(context.Background(), SetupDeck)
+		if test.expectedError != "" {
+			assert.EqualError(t, err, test.expectedError, test.url)
+		} else {
+			assert.NoError(t, err, test.url)
+		}
+		assert.Equal(t, test.expectedCards, cards, test.url)
+	}
+}
+
+func TestHTTPCardSourceETag(t *testing.T) {
+	allowLocalFetches(t)
+	var downloads, revalidations int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidations++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("test,R"))
+	}))
+	defer server.Close()
+
+	source := NewHTTPCardSource("", server.URL)
+	for i := 0; i < 3; i++ {
+		cards, err := source.Cards(context.Background(), PunchlineDeck)
+		assert.NoError(t, err)
+		assert.Equal(t, []RatedCard{{Text: "test", Rating: "R"}}, cards)
+	}
+	assert.Equal(t, 1, downloads)
+	assert.Equal(t, 2, revalidations)
+}
+
+func TestHTTPCardSourceContext(t *testing.T) {
+	allowLocalFetches(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewHTTPCardSource(server.URL, server.URL).Cards(ctx, SetupDeck)
+	assert.Error(t, err)
+}
+
+func TestHTTPCardSourcePublicOnly(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/away" {
+			// localhost isn't allowed even though 127.0.0.1 is
+			http.Redirect(w, r, strings.Replace(server.URL, "127.0.0.1", "localhost", 1)+"/setups.csv", http.StatusFound)
+			return
+		}
+		w.Write([]byte("test,R"))
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPCardSource(server.URL+"/setups.csv", "").Cards(context.Background(), SetupDeck)
+	assert.Equal(t, ErrPrivateAddress, err)
+	allowLocalFetches(t)
+	_, err = NewHTTPCardSource(server.URL+"/setups.csv", "").Cards(context.Background(), SetupDeck)
+	assert.NoError(t, err)
+	_, err = NewHTTPCardSource(server.URL+"/away", "").Cards(context.Background(), SetupDeck)
+	assert.Equal(t, ErrPrivateAddress, err, "checked after redirects")
+
+	for rawURL, expected := range map[string]error{
+		"https://example.com/setups.csv": nil,
+		"http://127.0.0.1:8080/deck":     nil, // allowed above
+		"ftp://example.com/setups.csv":   ErrInvalidDeckURL,
+		"/setups.csv":                    ErrInvalidDeckURL,
+		"http://localhost/deck":          ErrPrivateAddress,
+		"http://10.1.2.3/deck":           ErrPrivateAddress,
+		"http://169.254.169.254/latest":  ErrPrivateAddress,
+		"http://[::1]/deck":              ErrPrivateAddress,
+		"http://[fd00::1]/deck":          ErrPrivateAddress,
+	} {
+		assert.Equal(t, expected, ValidateDeckURL(rawURL), rawURL)
+	}
+}
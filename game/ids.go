@@ -0,0 +1,95 @@
+package game
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+)
+
+// gameIDs hands out ids from 0 to the configured GameIDSpace-1.
+var gameIDs = newIDPool(config.Current().GameIDSpace)
+
+// idPool hands out game ids from a shuffled list of the free ones, so taking
+// an id is O(1) and running out is known exactly rather than guessed. The
+// list is shuffled lazily, one pick at a time, and only positions that have
+// been moved are stored, so a large id space costs nothing up front.
+type idPool struct {
+	mu    sync.Mutex
+	free  int         // ids free, at positions 0 to free-1
+	moved map[int]int // position:id where it isn't id:id
+}
+
+func newIDPool(size int) *idPool {
+	return &idPool{
+		free:  size,
+		moved: make(map[int]int),
+	}
+}
+
+// at returns the id at position i of the free list.
+func (p *idPool) at(i int) int {
+	if id, ok := p.moved[i]; ok {
+		return id
+	}
+	return i
+}
+
+// set puts id at position i of the free list.
+func (p *idPool) set(i, id int) {
+	if i == id {
+		delete(p.moved, i)
+		return
+	}
+	p.moved[i] = id
+}
+
+// take returns a free id picked with rng, or ErrNoGamesAvailable if every
+// id is in use.
+func (p *idPool) take(rng *rand.Rand) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.free == 0 {
+		return 0, ErrNoGamesAvailable
+	}
+	i := rng.Intn(p.free)
+	last := p.free - 1
+	id := p.at(i)
+	p.set(i, p.at(last))
+	delete(p.moved, last)
+	p.free = last
+	return id, nil
+}
+
+// release makes id available again. It must currently be taken.
+func (p *idPool) release(id int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.set(p.free, id)
+	p.free++
+}
+
+// claim takes id itself, as when a game is restored, and reports whether it
+// was free.
+func (p *idPool) claim(id int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i := -1
+	if _, ok := p.moved[id]; !ok && id < p.free {
+		i = id
+	} else {
+		for position, moved := range p.moved {
+			if moved == id && position < p.free {
+				i = position
+			}
+		}
+	}
+	if i < 0 {
+		return false
+	}
+	last := p.free - 1
+	p.set(i, p.at(last))
+	delete(p.moved, last)
+	p.free = last
+	return true
+}
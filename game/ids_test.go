@@ -0,0 +1,159 @@
+package game
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRNG = rand.New(rand.NewSource(1))
+
+func TestIDPoolExhaustion(t *testing.T) {
+	p := newIDPool(5)
+	seen := make(map[int]bool)
+	for i := 0; i < 5; i++ {
+		id, err := p.take(testRNG)
+		assert.NoError(t, err)
+		assert.False(t, seen[id], "id %d taken twice", id)
+		assert.True(t, id >= 0 && id < 5)
+		seen[id] = true
+	}
+	_, err := p.take(testRNG)
+	assert.Equal(t, ErrNoGamesAvailable, err)
+
+	p.release(3)
+	id, err := p.take(testRNG)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, id)
+	_, err = p.take(testRNG)
+	assert.Equal(t, ErrNoGamesAvailable, err)
+}
+
+func TestIDPoolConcurrent(t *testing.T) {
+	p := newIDPool(100)
+	ids := make(chan int, 200)
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, err := p.take(testRNG)
+			if err == nil {
+				ids <- id
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+	seen := make(map[int]bool)
+	for id := range ids {
+		assert.False(t, seen[id], "id %d taken twice", id)
+		seen[id] = true
+	}
+	assert.Len(t, seen, 100)
+}
+
+func TestIDPoolHundredsOfGames(t *testing.T) {
+	p := newIDPool(config.Current().GameIDSpace)
+	seen := make(map[int]bool)
+	for i := 0; i < 800; i++ {
+		id, err := p.take(testRNG)
+		assert.NoError(t, err)
+		assert.False(t, seen[id], "id %d taken twice", id)
+		assert.True(t, id >= 0 && id < config.Current().GameIDSpace)
+		seen[id] = true
+	}
+
+	// nearly full: every free id is still found first time
+	p = newIDPool(500)
+	for i := 0; i < 500; i++ {
+		_, err := p.take(testRNG)
+		assert.NoError(t, err)
+	}
+	_, err := p.take(testRNG)
+	assert.Equal(t, ErrNoGamesAvailable, err)
+	p.release(42)
+	p.release(7)
+	freed := make(map[int]bool)
+	for i := 0; i < 2; i++ {
+		id, err := p.take(testRNG)
+		assert.NoError(t, err)
+		freed[id] = true
+	}
+	assert.Equal(t, map[int]bool{42: true, 7: true}, freed)
+	assert.Empty(t, p.moved, "nothing stored once the pool is empty")
+}
+
+func TestDeleteGameFreesID(t *testing.T) {
+	saved := gameIDs
+	defer func() { gameIDs = saved }()
+	gameIDs = newIDPool(1)
+
+	id, err := findID(testRNG)
+	assert.NoError(t, err)
+	games[id] = &Game{ID: id}
+	_, err = findID(testRNG)
+	assert.Equal(t, ErrNoGamesAvailable, err)
+
+	deleteGame(id)
+	_, err = GetGame(id)
+	assert.Equal(t, ErrGameNotFound, err)
+	reused, err := findID(testRNG)
+	assert.NoError(t, err)
+	assert.Equal(t, id, reused)
+}
+
+func TestGetGameAfterIDReuse(t *testing.T) {
+	defer config.Set(config.Current())
+	cfg := *config.Current()
+	cfg.GameTTL = time.Hour
+	config.Set(&cfg)
+	savedGames, savedIDs := games, gameIDs
+	defer func() { games, gameIDs = savedGames, savedIDs }()
+	games, gameIDs = make(map[int]*Game), newIDPool(1)
+
+	id, err := findID(testRNG)
+	assert.NoError(t, err)
+	games[id] = &Game{ID: id, Created: time.Now().Add(-time.Hour * 2)}
+	assert.Equal(t, 1, ReapExpired())
+	reused, err := findID(testRNG)
+	assert.NoError(t, err)
+	assert.Equal(t, id, reused)
+	g, err := GetGame(reused)
+	assert.Nil(t, g)
+	assert.Equal(t, ErrGameNotFound, err, "not yet created")
+
+	games[reused] = nil
+	g, err = GetGame(reused)
+	assert.Nil(t, g)
+	assert.Equal(t, ErrGameNotFound, err, "a nil entry isn't a game")
+}
+
+func TestIDPoolClaim(t *testing.T) {
+	p := newIDPool(5)
+	assert.True(t, p.claim(2))
+	assert.False(t, p.claim(2), "already taken")
+	assert.False(t, p.claim(5), "outside the pool")
+	seen := map[int]bool{2: true}
+	for i := 0; i < 3; i++ {
+		id, err := p.take(testRNG)
+		assert.NoError(t, err)
+		assert.False(t, seen[id], "id %d taken twice", id)
+		seen[id] = true
+	}
+	var left int
+	for id := 0; id < 5; id++ {
+		if !seen[id] {
+			left = id
+		}
+	}
+	assert.True(t, p.claim(left))
+	_, err := p.take(testRNG)
+	assert.Equal(t, ErrNoGamesAvailable, err)
+	p.release(2)
+	assert.True(t, p.claim(2))
+}
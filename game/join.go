@@ -0,0 +1,129 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	defaultMinPlayers = 3
+	defaultMaxPlayers = 12
+	minMaxPlayers     = 2
+	maxMaxPlayers     = 30
+)
+
+var (
+	ErrJoinedMidRound    = errors.New("you joined during this round, so you're in from the next one")
+	ErrInvalidMaxPlayers = fmt.Errorf("max players must be between %d and %d", minMaxPlayers, maxMaxPlayers)
+	ErrGameFull          = errors.New("game is full")
+	ErrInvalidMinPlayers = fmt.Errorf("min players must be between %d and max players", minMaxPlayers)
+	ErrNotEnoughPlayers  = errors.New("not enough players to start")
+)
+
+// WithMinPlayers has Start wait for min players instead of 3. NewGame
+// lowers it to MaxPlayers if that's fewer. Check min with
+// ValidateMinPlayers; 0 means the default.
+func WithMinPlayers(min int) Option {
+	return func(g *Game) {
+		if min == 0 {
+			min = defaultMinPlayers
+		}
+		g.MinPlayers = min
+	}
+}
+
+// ValidateMinPlayers checks min against max, the game's MaxPlayers or 0
+// for the default.
+func ValidateMinPlayers(min, max int) error {
+	if max == 0 {
+		max = defaultMaxPlayers
+	}
+	if min != 0 && (min < minMaxPlayers || min > max) {
+		return ErrInvalidMinPlayers
+	}
+	return nil
+}
+
+// WithMaxPlayers caps the game at max players instead of 12, or as many as
+// the deck can deal full hands to if that's fewer. NewGame fails with
+// ErrTooManyPlayers if the deck can't deal max full hands. Check max with
+// ValidateMaxPlayers; 0 means the default.
+func WithMaxPlayers(max int) Option {
+	return func(g *Game) {
+		g.MaxPlayers = max
+	}
+}
+
+func ValidateMaxPlayers(max int) error {
+	if max != 0 && (max < minMaxPlayers || max > maxMaxPlayers) {
+		return ErrInvalidMaxPlayers
+	}
+	return nil
+}
+
+// full reports whether another player can't join. Games that weren't made
+// by NewGame have no limit.
+func (g *Game) full() bool {
+	return g.MaxPlayers > 0 && len(g.Players) >= g.MaxPlayers
+}
+
+// enoughPlayers reports whether the game has the players it needs to
+// start. Games that weren't made by NewGame have no minimum.
+func (g *Game) enoughPlayers() bool {
+	return len(g.Players) >= g.MinPlayers
+}
+
+// roundNumber is the 1-based number of the round being played.
+func (g *Game) roundNumber() int {
+	return len(g.Rounds) - g.RoundsRemaining + 1
+}
+
+// joinRound is the number of the first round a player joining now takes
+// part in: this one if nobody has played in it yet, otherwise the next.
+// Players who join before the first round starts get 0, meaning they're
+// in from the start.
+func (g *Game) joinRound() int {
+	if g.CurrentAction == LOBBY || (g.RoundsRemaining == len(g.Rounds) && g.betweenRounds()) {
+		return 0
+	}
+	if g.betweenRounds() {
+		return g.roundNumber()
+	}
+	return g.roundNumber() + 1
+}
+
+// inRound reports whether the player takes part in the round being played.
+func (player Player) inRound(number int) bool {
+	return player.JoinedAtRound <= number
+}
+
+// participants counts the players taking part in the round being played,
+// which is how many plays and votes it waits for.
+func (g *Game) participants() int {
+	n := 0
+	for _, player := range g.Players {
+		if player.inRound(g.roundNumber()) {
+			n++
+		}
+	}
+	return n
+}
+
+// seatWaiting brings players who were waiting for the round just finished
+// into the next one.
+func (g *Game) seatWaiting() {
+	for i := range g.Players {
+		g.Players[i].Waiting = !g.Players[i].inRound(g.roundNumber())
+	}
+}
+
+// checkParticipant returns ErrJoinedMidRound if playerName joined after the
+// round being played started.
+func (g *Game) checkParticipant(playerName string) error {
+	for _, player := range g.Players {
+		if player.Name == playerName && !player.inRound(g.roundNumber()) {
+			return ErrJoinedMidRound
+		}
+	}
+	return nil
+}
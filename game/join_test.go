@@ -0,0 +1,151 @@
+package game
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinMidGame(t *testing.T) {
+	newGame := func() *Game {
+		g := &Game{
+			Players: []Player{
+				{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+				{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			},
+			Rounds:          make([]Round, 3),
+			RoundsRemaining: 3,
+			CurrentAction:   PLAY,
+		}
+		for i := 0; i < 30; i++ {
+			g.Punchlines = append(g.Punchlines, Card(rune('A'+i)))
+		}
+		return g
+	}
+	playRound := func(g *Game, players ...string) {
+		for _, name := range players {
+			for _, player := range g.Players {
+				if player.Name == name {
+					assert.NoError(t, g.Play(name, player.Punchlines[0]))
+				}
+			}
+		}
+		round := g.Rounds[g.RoundsRemaining-1]
+		for _, name := range players {
+			assert.NoError(t, g.Vote(name, round.Plays[players[0]]))
+		}
+	}
+
+	tests := []struct {
+		name    string
+		before  func(g *Game)
+		joined  int
+		waiting bool
+	}{
+		{"lobby", func(g *Game) { WithReadyCheck()(g) }, 0, false},
+		{"before any play", func(g *Game) {}, 0, false},
+		{"between rounds", func(g *Game) { playRound(g, "al", "bob") }, 2, false},
+		{"mid play", func(g *Game) { assert.NoError(t, g.Play("al", "a1")) }, 2, true},
+		{"mid vote", func(g *Game) {
+			assert.NoError(t, g.Play("al", "a1"))
+			assert.NoError(t, g.Play("bob", "b1"))
+		}, 2, true},
+	}
+	for _, test := range tests {
+		g := newGame()
+		test.before(g)
+		assert.NoError(t, g.AddPlayer(Player{Name: "carl"}, ""), test.name)
+		carl := g.Players[2]
+		assert.Equal(t, test.joined, carl.JoinedAtRound, test.name)
+		assert.Equal(t, test.waiting, carl.Waiting, test.name)
+		assert.Len(t, carl.Punchlines, defaultHandSize, test.name)
+	}
+
+	// carl joins mid vote: the round finishes without him, then he's in
+	g := newGame()
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.AddPlayer(Player{Name: "carl"}, ""))
+	assert.Equal(t, ErrJoinedMidRound, g.Play("carl", g.Players[2].Punchlines[0]))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.Equal(t, VOTE, g.CurrentAction, "not waiting on carl")
+	assert.Equal(t, ErrJoinedMidRound, g.Vote("carl", "a1"))
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("bob", "b1"))
+	assert.Equal(t, 2, g.RoundsRemaining)
+	assert.False(t, g.Players[2].Waiting, "in from this round")
+	playRound(g, "al", "bob", "carl")
+	assert.Equal(t, 1, g.RoundsRemaining)
+	assert.Len(t, g.Rounds[1].Plays, 3)
+
+	// auto-play skips him in the round he missed
+	g = newGame()
+	WithAutoPlay()(g)
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.AddPlayer(Player{Name: "carl"}, ""))
+	g.playTimeout()
+	assert.Len(t, g.Rounds[2].Plays, 2)
+	assert.Equal(t, VOTE, g.CurrentAction)
+
+	// the public history shows when he joined
+	standings := g.Standings()
+	assert.Equal(t, Standing{Player: "carl", JoinedAtRound: 2}, standings[2])
+	var buf bytes.Buffer
+	assert.NoError(t, g.WriteTranscript(&buf, FormatText))
+	assert.Contains(t, buf.String(), "  carl: 0 (joined round 2)\n")
+	buf.Reset()
+	assert.NoError(t, g.WriteTranscript(&buf, FormatMarkdown))
+	assert.Contains(t, buf.String(), "| carl (joined round 2) | 0 |\n")
+}
+
+func TestMaxPlayers(t *testing.T) {
+	assert.NoError(t, ValidateMaxPlayers(0))
+	assert.Equal(t, ErrInvalidMaxPlayers, ValidateMaxPlayers(1))
+	assert.Equal(t, ErrInvalidMaxPlayers, ValidateMaxPlayers(31))
+
+	source := &staticSource{}
+	for i := 0; i < 40; i++ {
+		*source = append(*source, RatedCard{Text: Card(strconv.Itoa(i)), Rating: "G"})
+	}
+	g, err := NewGame(context.Background(), Player{Name: "al"}, 1, "R", WithCardSource(source), WithMaxPlayers(3))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	assert.Equal(t, 3, g.MaxPlayers)
+	assert.NoError(t, g.AddPlayer(Player{Name: "bob"}, ""))
+	assert.NoError(t, g.AddPlayer(Player{Name: "carl"}, ""))
+	assert.Equal(t, ErrGameFull, g.AddPlayer(Player{Name: "dan"}, ""))
+	assert.Len(t, g.Players, 3)
+
+	// 40 punchlines only make 6 hands of 6
+	big, err := NewGame(context.Background(), Player{Name: "al"}, 1, "R", WithCardSource(source))
+	assert.NoError(t, err)
+	defer deleteGame(big.ID)
+	assert.Equal(t, 6, big.MaxPlayers)
+}
+
+func TestMinPlayers(t *testing.T) {
+	assert.NoError(t, ValidateMinPlayers(0, 0))
+	assert.NoError(t, ValidateMinPlayers(2, 0))
+	assert.NoError(t, ValidateMinPlayers(12, 0))
+	assert.NoError(t, ValidateMinPlayers(4, 4))
+	assert.Equal(t, ErrInvalidMinPlayers, ValidateMinPlayers(1, 0))
+	assert.Equal(t, ErrInvalidMinPlayers, ValidateMinPlayers(13, 0))
+	assert.Equal(t, ErrInvalidMinPlayers, ValidateMinPlayers(5, 4))
+
+	source := &staticSource{}
+	for i := 0; i < 20; i++ {
+		*source = append(*source, RatedCard{Text: Card(strconv.Itoa(i)), Rating: "G"})
+	}
+	// 20 punchlines only make 3 hands of 6
+	g, err := NewGame(context.Background(), Player{Name: "al"}, 1, "R", WithCardSource(source), WithMinPlayers(5))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	assert.Equal(t, 3, g.MinPlayers)
+	assert.NoError(t, g.AddPlayer(Player{Name: "bob"}, ""))
+	assert.Equal(t, ErrNotEnoughPlayers, g.Start("al", false))
+	assert.Equal(t, 3, g.ViewFor("al", HandDealt).MinPlayers)
+	assert.NoError(t, g.AddPlayer(Player{Name: "carl"}, ""))
+	assert.NoError(t, g.Start("al", false))
+}
@@ -0,0 +1,100 @@
+package game
+
+import "errors"
+
+var (
+	ErrJudge    = errors.New("the judge doesn't play this round")
+	ErrNotJudge = errors.New("only the judge picks this round's winner")
+)
+
+// WithJudge has one player judge each round instead of everyone voting,
+// taking turns. The judge doesn't play, and the round is settled by their
+// pick.
+func WithJudge() Option {
+	return func(g *Game) {
+		g.VotingMode = VotingJudge
+	}
+}
+
+func (g *Game) judging() bool {
+	return g.VotingMode == VotingJudge
+}
+
+// judge returns the current round's judge, if the game has one.
+func (g *Game) judge() string {
+	if !g.judging() || g.RoundsRemaining <= 0 {
+		return ""
+	}
+	return g.Rounds[g.RoundsRemaining-1].Judge
+}
+
+// playsNeeded is how many plays the round waits for: everyone taking part
+// but the judge.
+func (g *Game) playsNeeded() int {
+	if g.suddenDeath() {
+		return g.suddenDeathPlayers()
+	}
+	if judge := g.judge(); judge != "" {
+		return g.participants() - 1
+	}
+	return g.participants()
+}
+
+// votesNeeded is how many votes settle the round: the judge's, or everyone
+// taking part's.
+func (g *Game) votesNeeded() int {
+	if g.judge() != "" {
+		return 1
+	}
+	return g.participants()
+}
+
+// assignJudge makes the next player in turn the current round's judge: the
+// first, in the order they joined, who's taking part and hasn't judged since
+// everyone last had a turn. Joiners take a turn in the current cycle and
+// leavers are passed over, so nobody judges twice before everyone has
+// judged once.
+func (g *Game) assignJudge() {
+	if !g.judging() || g.RoundsRemaining <= 0 {
+		return
+	}
+	judge := g.nextJudge()
+	if judge == "" {
+		g.judged = nil
+		judge = g.nextJudge()
+	}
+	if judge != "" {
+		if g.judged == nil {
+			g.judged = make(map[string]bool)
+		}
+		g.judged[judge] = true
+	}
+	g.Rounds[g.RoundsRemaining-1].Judge = judge
+}
+
+func (g *Game) nextJudge() string {
+	for _, player := range g.Players {
+		if player.inRound(g.roundNumber()) && !g.judged[player.Name] {
+			return player.Name
+		}
+	}
+	return ""
+}
+
+// replaceJudge hands the round to the next judge when the judge leaves.
+// If they'd played, their card goes back to their hand.
+func (g *Game) replaceJudge() {
+	g.assignJudge()
+	judge := g.judge()
+	round := g.Rounds[g.RoundsRemaining-1]
+	card, ok := round.Plays[judge]
+	if _, pairing := round.Pairs[judge]; !ok && !pairing {
+		return
+	}
+	delete(round.Plays, judge)
+	delete(round.AutoPlayed, judge)
+	round.removeFromBallot(card)
+	delete(round.Reactions, card)
+	g.returnPlay(&round, judge, card)
+	g.Rounds[g.RoundsRemaining-1] = round
+}
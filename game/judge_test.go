@@ -0,0 +1,82 @@
+package game
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJudge(t *testing.T) {
+	newGame := func(rounds int) *Game {
+		g := &Game{
+			Players: []Player{
+				{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+				{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+				{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+			},
+			Rounds:          make([]Round, rounds),
+			RoundsRemaining: rounds,
+			CurrentAction:   PLAY,
+		}
+		for i := 0; i < 40; i++ {
+			g.Punchlines = append(g.Punchlines, Card(strconv.Itoa(i)))
+		}
+		WithJudge()(g)
+		g.assignJudge()
+		return g
+	}
+	play := func(g *Game, name string) Card {
+		hand, err := g.hand(name)
+		assert.NoError(t, err)
+		card := hand[0]
+		assert.NoError(t, g.Play(name, card))
+		return card
+	}
+	judge := func(g *Game) string {
+		return g.Rounds[g.RoundsRemaining-1].Judge
+	}
+
+	g := newGame(5)
+	assert.Equal(t, "al", judge(g))
+	assert.Equal(t, ErrJudge, g.Play("al", "a1"))
+	play(g, "bob")
+	assert.Equal(t, PLAY, g.CurrentAction)
+	carl := play(g, "carl")
+	assert.Equal(t, VOTE, g.CurrentAction, "not waiting on the judge")
+	assert.Equal(t, ErrNotJudge, g.Vote("bob", carl))
+	assert.NoError(t, g.Vote("al", carl))
+	assert.Equal(t, "carl", g.Rounds[4].Winner)
+	assert.Equal(t, 4, g.RoundsRemaining)
+	assert.Len(t, g.Players[0].Punchlines, defaultHandSize, "the judge kept their hand")
+
+	// dan joins mid-round and takes his turn once he's in
+	assert.Equal(t, "bob", judge(g))
+	play(g, "al")
+	assert.NoError(t, g.AddPlayer(Player{Name: "dan"}, ""))
+	card := play(g, "carl")
+	assert.NoError(t, g.Vote("bob", card))
+	var judges []string
+	for g.RoundsRemaining > 0 {
+		judges = append(judges, judge(g))
+		for _, player := range g.Players {
+			if player.Name != judge(g) {
+				card = play(g, player.Name)
+			}
+		}
+		assert.NoError(t, g.Vote(judge(g), card))
+	}
+	assert.Equal(t, []string{"carl", "dan", "al"}, judges)
+
+	// the judge leaving passes the round on, and the next judge's card back
+	g = newGame(2)
+	bob := play(g, "bob")
+	assert.NoError(t, g.RemovePlayer("al"))
+	assert.Equal(t, "bob", judge(g))
+	assert.Empty(t, g.Rounds[1].Plays)
+	assert.Contains(t, g.Players[0].Punchlines, bob)
+	carl = play(g, "carl")
+	assert.Equal(t, VOTE, g.CurrentAction)
+	assert.NoError(t, g.Vote("bob", carl))
+	assert.Equal(t, "carl", g.Rounds[1].Winner)
+}
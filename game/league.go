@@ -0,0 +1,88 @@
+package game
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+)
+
+const maxLeagueLength = 32
+
+var (
+	ErrInvalidLeague = errors.New("league must be up to 32 lowercase letters, digits and dashes")
+
+	leagueSlug = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+)
+
+// WithLeague groups the game with the league's others for league listings
+// and stats. Check slug with ValidateLeague.
+func WithLeague(slug string) Option {
+	return func(g *Game) {
+		g.League = slug
+	}
+}
+
+func ValidateLeague(slug string) error {
+	if len(slug) > maxLeagueLength || !leagueSlug.MatchString(slug) {
+		return ErrInvalidLeague
+	}
+	return nil
+}
+
+// LeagueStats aggregates a league's finished games.
+type LeagueStats struct {
+	League    string     `json:"league"`
+	Games     int        `json:"games"`
+	Rounds    int        `json:"rounds"`
+	Players   int        `json:"players"`   // distinct names
+	Standings []Standing `json:"standings"` // rounds won across the league
+}
+
+// leagueGames returns the league's finished games, lowest ids first.
+func leagueGames(slug string) []*Game {
+	var found []*Game
+	for _, g := range liveGames() {
+		if slug != "" && g.League == slug && g.State() == StateFinished {
+			found = append(found, g)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].ID < found[j].ID })
+	return found
+}
+
+// LeagueSummaries summarizes up to MaxSummaries of the league's finished
+// games, lowest ids first.
+func LeagueSummaries(slug string) []Summary {
+	summaries := []Summary{}
+	for _, g := range leagueGames(slug) {
+		summaries = append(summaries, g.Summary())
+		if len(summaries) == MaxSummaries {
+			break
+		}
+	}
+	return summaries
+}
+
+// GetLeagueStats aggregates the league's finished games.
+func GetLeagueStats(slug string) LeagueStats {
+	stats := LeagueStats{League: slug, Standings: []Standing{}}
+	wins := make(map[string]int)
+	for _, g := range leagueGames(slug) {
+		stats.Games++
+		stats.Rounds += len(g.Rounds)
+		for _, standing := range g.Standings() {
+			wins[standing.Player] += standing.RoundsWon
+		}
+	}
+	for player, won := range wins {
+		stats.Standings = append(stats.Standings, Standing{Player: player, RoundsWon: won})
+	}
+	sort.Slice(stats.Standings, func(i, j int) bool {
+		if stats.Standings[i].RoundsWon != stats.Standings[j].RoundsWon {
+			return stats.Standings[i].RoundsWon > stats.Standings[j].RoundsWon
+		}
+		return stats.Standings[i].Player < stats.Standings[j].Player
+	})
+	stats.Players = len(stats.Standings)
+	return stats
+}
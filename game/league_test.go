@@ -0,0 +1,61 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeague(t *testing.T) {
+	for slug, valid := range map[string]bool{
+		"thursday-club":                        true,
+		"a1":                                   true,
+		"":                                     false,
+		"Thursday":                             false,
+		"-club":                                false,
+		"club--night":                          false,
+		"club night":                           false,
+		"a-very-long-league-name-that-goes-on": false,
+	} {
+		assert.Equal(t, valid, ValidateLeague(slug) == nil, slug)
+	}
+
+	finished := func(id int, league string, winners ...string) *Game {
+		g := &Game{ID: id, Players: []Player{{Name: "al"}, {Name: "bob"}}}
+		WithLeague(league)(g)
+		for _, winner := range winners {
+			g.Rounds = append(g.Rounds, Round{Winner: winner})
+			if winner != "" {
+				g.player(winner).Score++
+			}
+		}
+		return g
+	}
+	league := []*Game{
+		finished(901, "club", "al", "bob", "al"),
+		finished(902, "club", "bob", ""),
+		finished(903, "other", "al"),
+		finished(904, "", "al"),
+		{ID: 905, League: "club", Players: []Player{{Name: "carl"}}, Rounds: make([]Round, 1), RoundsRemaining: 1},
+	}
+	for _, g := range league {
+		games[g.ID] = g
+		defer deleteGame(g.ID)
+	}
+
+	summaries := LeagueSummaries("club")
+	assert.Len(t, summaries, 2, "finished games only")
+	assert.Equal(t, 901, summaries[0].ID)
+	assert.Equal(t, "club", summaries[0].League)
+	assert.Equal(t, 902, summaries[1].ID)
+	assert.Empty(t, LeagueSummaries(""), "games without a league aren't a league")
+
+	assert.Equal(t, LeagueStats{
+		League:    "club",
+		Games:     2,
+		Rounds:    5,
+		Players:   2,
+		Standings: []Standing{{Player: "al", RoundsWon: 2}, {Player: "bob", RoundsWon: 2}},
+	}, GetLeagueStats("club"))
+	assert.Equal(t, LeagueStats{League: "none", Standings: []Standing{}}, GetLeagueStats("none"))
+}
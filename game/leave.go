@@ -0,0 +1,92 @@
+package game
+
+// RemovePlayer takes name out of the game. Their hand goes back to the
+// bottom of the deck along with any card they'd played this round, and
+// their vote is dropped, as are votes for their card so those voters can
+// vote again. If everyone left has now played or voted, the round moves on
+// as it would have on the last play or vote. If the host leaves, the player
+// who joined next becomes the host. When the last player leaves, the game
+// is left for ReapExpired to remove. Spectators can leave too.
+func (g *Game) RemovePlayer(name string) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if g.removeSpectator(name) {
+		return nil
+	}
+	return g.removePlayer(name)
+}
+
+func (g *Game) removePlayer(name string) error {
+	index := -1
+	for i, player := range g.Players {
+		if player.Name == name {
+			index = i
+		}
+	}
+	if index < 0 {
+		return ErrPlayerNotFound
+	}
+	returned := g.Players[index].Punchlines
+	g.Players = append(g.Players[:index], g.Players[index+1:]...)
+	g.revokeTokens(name)
+	g.passHost(name)
+	delete(g.actions, name)
+
+	if !g.Finished && g.RoundsRemaining > 0 {
+		round := g.Rounds[g.RoundsRemaining-1]
+		pair, paired := round.Pairs[name]
+		returned = append(returned, pair...)
+		delete(round.Pairs, name)
+		if card, ok := round.Plays[name]; ok {
+			if !round.WriteIns[name] && !paired {
+				returned = append(returned, card)
+			}
+			delete(round.Plays, name)
+			round.removeFromBallot(card)
+			round.dropAudienceVotes(card)
+			delete(round.Reactions, card)
+			for voter, vote := range round.Votes {
+				if vote == card || containsCard(round.Rankings[voter], card) {
+					delete(round.Votes, voter)
+					delete(round.Rankings, voter)
+					delete(round.AutoVoted, voter)
+				}
+			}
+		}
+		delete(round.Votes, name)
+		delete(round.Rankings, name)
+		delete(round.AutoPlayed, name)
+		delete(round.AutoVoted, name)
+		delete(round.WriteIns, name)
+		g.Rounds[g.RoundsRemaining-1] = round
+		if g.judging() && round.Judge == name {
+			g.replaceJudge()
+		}
+	}
+	g.Punchlines = append(append([]Card(nil), returned...), g.Punchlines...)
+
+	if len(g.Players) == 0 {
+		g.abandoned = true
+		return nil
+	}
+	g.advance()
+	return nil
+}
+
+// advance moves the round on if nobody it's waiting for is left.
+func (g *Game) advance() {
+	if g.Finished || g.RoundsRemaining <= 0 || g.participants() == 0 {
+		return
+	}
+	round := g.Rounds[g.RoundsRemaining-1]
+	switch g.CurrentAction {
+	case PLAY:
+		if len(round.Plays) > 0 && len(round.Plays) >= g.playsNeeded() {
+			g.startVoting()
+		}
+	case VOTE:
+		if len(round.Votes) >= g.votesNeeded() {
+			g.finishRound()
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemovePlayer(t *testing.T) {
+	newGame := func() *Game {
+		return &Game{
+			Players: []Player{
+				{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+				{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+				{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+			},
+			Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+			Rounds:          make([]Round, 2),
+			RoundsRemaining: 2,
+			CurrentAction:   PLAY,
+		}
+	}
+
+	g := newGame()
+	assert.Equal(t, ErrPlayerNotFound, g.RemovePlayer("dan"))
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.RemovePlayer("carl"))
+	assert.Equal(t, VOTE, g.CurrentAction, "nobody left to wait for")
+	assert.Len(t, g.Players, 2)
+	assert.Equal(t, []Card{"c1", "c2", "c3", "c4", "c5", "c6", "1", "2", "3", "4", "5", "6"}, g.Punchlines, "at the bottom of the deck")
+
+	// leaving mid-vote takes the card and the votes for it with it
+	g = newGame()
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Play("carl", "c1"))
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("carl", "a1"))
+	assert.NoError(t, g.RemovePlayer("bob"))
+	round := g.Rounds[1]
+	assert.Equal(t, map[string]Card{"al": "a1", "carl": "c1"}, round.Plays)
+	assert.Equal(t, map[string]Card{"carl": "a1"}, round.Votes)
+	assert.Equal(t, Card("b1"), g.Punchlines[defaultHandSize-1])
+	assert.Equal(t, VOTE, g.CurrentAction)
+	assert.NoError(t, g.Vote("al", "c1"))
+	assert.Equal(t, 1, g.RoundsRemaining)
+	assert.Equal(t, []string{"al", "carl"}, g.Rounds[1].Winners)
+
+	// the last voter leaving settles the round
+	g = newGame()
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Play("carl", "c1"))
+	assert.NoError(t, g.Vote("al", "c1"))
+	assert.NoError(t, g.Vote("carl", "a1"))
+	assert.NoError(t, g.RemovePlayer("bob"))
+	assert.Equal(t, 1, g.RoundsRemaining)
+	assert.Equal(t, PLAY, g.CurrentAction)
+}
+
+func TestRemoveLastPlayer(t *testing.T) {
+	savedGames, savedIDs := games, gameIDs
+	defer func() { games, gameIDs = savedGames, savedIDs }()
+	games, gameIDs = make(map[int]*Game), newIDPool(1)
+	id, err := findID(testRNG)
+	assert.NoError(t, err)
+	g := &Game{
+		ID:              id,
+		Players:         []Player{{Name: "al", Punchlines: []Card{"a1"}}},
+		Rounds:          make([]Round, 1),
+		RoundsRemaining: 1,
+		CurrentAction:   PLAY,
+	}
+	games[id] = g
+
+	assert.NoError(t, g.RemovePlayer("al"))
+	assert.Empty(t, g.Players)
+	found, err := GetGame(id)
+	assert.NoError(t, err, "until it's reaped")
+	assert.Equal(t, g, found)
+	assert.NotContains(t, tombstones, id, "not deleted like an admin deletion")
+
+	ReapExpired()
+	_, err = GetGame(id)
+	assert.Equal(t, ErrGameNotFound, err)
+}
@@ -0,0 +1,191 @@
+package game
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const MaxCardLength = 140 // runes
+
+// Kinds of DeckProblem.
+const (
+	ProblemMalformed     = "malformed"
+	ProblemRating        = "rating"
+	ProblemDuplicate     = "duplicate"
+	ProblemNearDuplicate = "near-duplicate"
+	ProblemLength        = "length"
+	ProblemCharacters    = "characters"
+)
+
+// DeckProblem is one issue found by LintDeck. Line is the 1-indexed line for
+// CSV decks and the 1-indexed card position for JSON decks.
+type DeckProblem struct {
+	Line    int    `json:"line"`
+	Kind    string `json:"kind"`
+	Card    Card   `json:"card,omitempty"`
+	Message string `json:"message"`
+}
+
+func (p DeckProblem) String() string {
+	return fmt.Sprintf("%d: %s: %s", p.Line, p.Kind, p.Message)
+}
+
+// DeckReport is the result of linting a deck.
+type DeckReport struct {
+	Cards    []RatedCard    `json:"-"` // every card that parsed, normalized
+	Problems []DeckProblem  `json:"problems"`
+	Ratings  map[string]int `json:"ratings"` // rating:count
+}
+
+// LintDeck parses a deck with the same code the server uses to load it, but
+// keeps going past errors and reports every problem it finds.
+func LintDeck(r io.Reader) (*DeckReport, error) {
+	data, err := readDeck(r)
+	if err != nil {
+		return nil, err
+	}
+	report := &DeckReport{Ratings: make(map[string]int)}
+	if isJSONDeck(data) {
+		cards, err := parseJSONDeck(data)
+		if err != nil {
+			report.Problems = append(report.Problems, DeckProblem{Line: 1, Kind: ProblemMalformed, Message: err.Error()})
+			return report, nil
+		}
+		for i, card := range cards {
+			report.add(i+1, card)
+		}
+		return report, nil
+	}
+
+	err = readCSVDeck(bytes.NewReader(data), func(line int, card RatedCard, err error) error {
+		if err != nil {
+			report.Problems = append(report.Problems, DeckProblem{Line: line, Kind: ProblemMalformed, Message: err.Error()})
+			return nil
+		}
+		report.add(line, card)
+		return nil
+	})
+	return report, err
+}
+
+func (d *DeckReport) add(line int, card RatedCard) {
+	problem := func(kind, format string, args ...interface{}) {
+		d.Problems = append(d.Problems, DeckProblem{Line: line, Kind: kind, Card: card.Text, Message: fmt.Sprintf(format, args...)})
+	}
+	if _, ok := ratingRanks[card.Rating]; ok {
+		d.Ratings[card.Rating]++
+	}
+	for _, p := range cardProblems(card) {
+		p.Line = line
+		d.Problems = append(d.Problems, p)
+	}
+	for _, prev := range d.Cards {
+		if prev.Text == card.Text {
+			problem(ProblemDuplicate, "duplicate of an earlier card")
+			break
+		}
+		if dedupKey(prev.Text) == dedupKey(card.Text) {
+			problem(ProblemNearDuplicate, "near-duplicate of %q", prev.Text)
+			break
+		}
+	}
+	d.Cards = append(d.Cards, card)
+}
+
+// cardProblems checks a card on its own, without regard to the rest of its
+// deck.
+func cardProblems(card RatedCard) []DeckProblem {
+	var problems []DeckProblem
+	problem := func(kind, format string, args ...interface{}) {
+		problems = append(problems, DeckProblem{Kind: kind, Card: card.Text, Message: fmt.Sprintf(format, args...)})
+	}
+	if _, ok := ratingRanks[card.Rating]; !ok {
+		problem(ProblemRating, "unknown rating %q", card.Rating)
+	}
+	if n := utf8.RuneCountInString(string(card.Text)); n > MaxCardLength {
+		problem(ProblemLength, "card is %d characters, limit is %d", n, MaxCardLength)
+	}
+	if r, ok := suspiciousRune(string(card.Text)); ok {
+		problem(ProblemCharacters, "suspicious character %U", r)
+	}
+	return problems
+}
+
+// Fixed returns the deck's cards normalized, with duplicates and
+// near-duplicates removed (the first occurrence wins) and cards with unknown
+// ratings dropped.
+func (d *DeckReport) Fixed() []RatedCard {
+	var fixed []RatedCard
+	seen := make(map[string]bool)
+	for _, card := range d.Cards {
+		if _, ok := ratingRanks[card.Rating]; !ok {
+			continue
+		}
+		key := dedupKey(card.Text)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		fixed = append(fixed, card)
+	}
+	return fixed
+}
+
+// Summary describes the rating distribution, e.g. "G: 3, PG: 10".
+func (d *DeckReport) Summary() string {
+	var ratings []string
+	for rating := range d.Ratings {
+		ratings = append(ratings, rating)
+	}
+	sort.Slice(ratings, func(i, j int) bool {
+		return ratingRanks[ratings[i]] < ratingRanks[ratings[j]]
+	})
+	var parts []string
+	for _, rating := range ratings {
+		parts = append(parts, fmt.Sprintf("%s: %d", rating, d.Ratings[rating]))
+	}
+	return fmt.Sprintf("%d cards (%s)", len(d.Cards), strings.Join(parts, ", "))
+}
+
+// WriteCSVDeck writes cards in the CSV deck format.
+func WriteCSVDeck(w io.Writer, cards []RatedCard) error {
+	writer := csv.NewWriter(w)
+	for _, card := range cards {
+		err := writer.Write([]string{string(card.Text), card.Rating})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// dedupKey reduces a card to lowercase letters and digits, so cards that
+// differ only in case, punctuation, or spacing compare equal.
+func dedupKey(card Card) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(string(card)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func suspiciousRune(text string) (rune, bool) {
+	for _, r := range text {
+		switch {
+		case r == utf8.RuneError,
+			unicode.IsControl(r),
+			unicode.Is(unicode.Cf, r): // zero-width and other invisible formatting
+			return r, true
+		}
+	}
+	return 0, false
+}
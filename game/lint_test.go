@@ -0,0 +1,90 @@
+package game
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintDeck(t *testing.T) {
+	deck := strings.Join([]string{
+		"Patience,G",
+		"Preparedness,PG",
+		"no rating here",
+		"Patience,G",
+		"patience!,PG",
+		"Likelihood of incarceration,NC-17",
+		"Zero\u200bwidth,R",
+		strings.Repeat("a", MaxCardLength+1) + ",R",
+		"  Spaced    out  ,R",
+	}, "\n")
+	report, err := LintDeck(strings.NewReader(deck))
+	assert.NoError(t, err)
+
+	var kinds []string
+	var lines []int
+	for _, problem := range report.Problems {
+		kinds = append(kinds, problem.Kind)
+		lines = append(lines, problem.Line)
+	}
+	assert.Equal(t, []string{
+		ProblemMalformed,
+		ProblemDuplicate,
+		ProblemNearDuplicate,
+		ProblemRating,
+		ProblemCharacters,
+		ProblemLength,
+	}, kinds)
+	assert.Equal(t, []int{3, 4, 5, 6, 7, 8}, lines)
+	assert.Equal(t, map[string]int{"G": 2, "PG": 2, "R": 3}, report.Ratings)
+	assert.Equal(t, "8 cards (G: 2, PG: 2, R: 3)", report.Summary())
+
+	var fixed bytes.Buffer
+	assert.NoError(t, WriteCSVDeck(&fixed, report.Fixed()))
+	cards, err := parseDeck(&fixed)
+	assert.NoError(t, err)
+	assert.Equal(t, []RatedCard{
+		{Text: "Patience", Rating: "G"},
+		{Text: "Preparedness", Rating: "PG"},
+		{Text: "Zero\u200bwidth", Rating: "R"},
+		{Text: Card(strings.Repeat("a", MaxCardLength+1)), Rating: "R"},
+		{Text: "Spaced out", Rating: "R"},
+	}, cards)
+}
+
+func TestLintDeckParsesLikeTheServer(t *testing.T) {
+	long := strings.Repeat("long ", 20000)
+	deck := "\"Spans\ntwo lines\",G\n" + long + ",R\nAfter,PG\n\"unterminated,G\nLost,G\n"
+	report, err := LintDeck(strings.NewReader(deck))
+	assert.NoError(t, err)
+	assert.Equal(t, []Card{"Spans two lines", NormalizeCard(long), "After"}, cardTexts(report.Cards))
+	if assert.Len(t, report.Problems, 2) {
+		assert.Equal(t, DeckProblem{Line: 3, Kind: ProblemLength, Card: NormalizeCard(long), Message: "card is 99999 characters, limit is 140"}, report.Problems[0])
+		assert.Equal(t, ProblemMalformed, report.Problems[1].Kind)
+		assert.Equal(t, 6, report.Problems[1].Line, "the quote runs to the end")
+	}
+	_, err = parseDeck(strings.NewReader(deck))
+	assert.Error(t, err, "lint's malformed card stops the server too")
+
+	report, err = LintDeck(strings.NewReader(strings.TrimSuffix(deck, "\"unterminated,G\nLost,G\n")))
+	assert.NoError(t, err)
+	assert.Len(t, report.Problems, 1)
+	cards, err := parseDeck(strings.NewReader(strings.TrimSuffix(deck, "\"unterminated,G\nLost,G\n")))
+	assert.NoError(t, err)
+	assert.Equal(t, report.Cards, cards)
+}
+
+func TestLintDeckJSON(t *testing.T) {
+	report, err := LintDeck(strings.NewReader(`[{"text":"one","rating":"G"},{"text":"One","rating":"Q"}]`))
+	assert.NoError(t, err)
+	assert.Len(t, report.Problems, 2)
+	assert.Equal(t, ProblemRating, report.Problems[0].Kind)
+	assert.Equal(t, 2, report.Problems[0].Line)
+	assert.Equal(t, ProblemNearDuplicate, report.Problems[1].Kind)
+
+	report, err = LintDeck(strings.NewReader(`[{"text":`))
+	assert.NoError(t, err)
+	assert.Equal(t, []DeckProblem{{Line: 1, Kind: ProblemMalformed, Message: ErrMalformedJSON.Error()}}, report.Problems)
+}
@@ -0,0 +1,85 @@
+package game
+
+import "errors"
+
+var (
+	ErrNotInLobby      = errors.New("game has already started")
+	ErrPlayersNotReady = errors.New("not every player is ready")
+)
+
+// WithReadyCheck has every player say they're ready before the host can
+// start the game, see SetReady and Start.
+func WithReadyCheck() Option {
+	return func(g *Game) {
+		g.readyCheck = true
+	}
+}
+
+// SetReady marks playerName ready, or not, to start.
+func (g *Game) SetReady(playerName string, ready bool) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.readyCheck || g.CurrentAction != LOBBY {
+		return ErrNotInLobby
+	}
+	for i := range g.Players {
+		if g.Players[i].Name == playerName {
+			g.Players[i].Ready = ready
+			return nil
+		}
+	}
+	return ErrPlayerNotFound
+}
+
+// resetReady has everyone confirm again when the lobby changes.
+func (g *Game) resetReady() {
+	if !g.readyCheck || g.CurrentAction != LOBBY {
+		return
+	}
+	for i := range g.Players {
+		g.Players[i].Ready = false
+	}
+}
+
+// Start takes the game out of the LOBBY at the host's request: everyone
+// who's joined is dealt a hand and takes part from the first round, which
+// starts now. There must be at least MinPlayers of them. In a ready check
+// game every player must be ready unless force
+// is set; if it is, the players who weren't are listed in Unready.
+func (g *Game) Start(playerName string, force bool) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.isHost(playerName) {
+		return ErrNotHost
+	}
+	if g.CurrentAction != LOBBY {
+		return ErrNotInLobby
+	}
+	if !g.enoughPlayers() {
+		return ErrNotEnoughPlayers
+	}
+	var unready []string
+	for _, player := range g.Players {
+		if g.readyCheck && !player.Ready {
+			unready = append(unready, player.Name)
+		}
+	}
+	if len(unready) > 0 && !force {
+		return ErrPlayersNotReady
+	}
+	return g.start(unready)
+}
+
+// start deals everyone in and begins the first round.
+func (g *Game) start(unready []string) error {
+	err := g.dealPunchlines()
+	if err != nil {
+		return err
+	}
+	g.Unready = unready
+	g.CurrentAction = PLAY
+	g.assignJudge()
+	g.startPlaying()
+	recordUsage(usageStarted, g)
+	return nil
+}
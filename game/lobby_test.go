@@ -0,0 +1,125 @@
+package game
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLobby(t *testing.T) {
+	source := &staticSource{}
+	for i := 0; i < 30; i++ {
+		*source = append(*source, RatedCard{Text: Card(strconv.Itoa(i)), Rating: "G"})
+	}
+	g, err := NewGame(context.Background(), Player{Name: "al"}, 2, "R", WithCardSource(source), WithHandSize(3), WithTimers(30, 0))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	assert.Equal(t, LOBBY, g.CurrentAction)
+	assert.Len(t, g.Rounds, 2, "the rounds are set up")
+	assert.Empty(t, g.Players[0].Punchlines)
+	assert.Nil(t, g.PhaseDeadline)
+	assert.Equal(t, ErrWrongPhase, g.Play("al", (*source)[0].Text))
+	assert.Equal(t, ErrWrongPhase, g.Vote("al", (*source)[0].Text))
+	assert.Equal(t, ErrWrongPhase, g.Mulligan("al"))
+
+	assert.NoError(t, g.AddPlayer(Player{Name: "bob"}, ""))
+	assert.Empty(t, g.Players[1].Punchlines)
+	assert.Equal(t, ErrNotHost, g.Start("bob", false))
+	assert.Equal(t, 3, g.MinPlayers)
+	assert.Equal(t, ErrNotEnoughPlayers, g.Start("al", false))
+	assert.NoError(t, g.AddPlayer(Player{Name: "carl"}, ""))
+	assert.NoError(t, g.Start("al", false))
+	assert.Equal(t, PLAY, g.CurrentAction)
+	assert.NotNil(t, g.PhaseDeadline)
+	assert.Empty(t, g.Unready, "no ready check")
+	for _, player := range g.Players {
+		assert.Len(t, player.Punchlines, 3, player.Name)
+		assert.Equal(t, 0, player.JoinedAtRound, player.Name)
+	}
+	assert.Equal(t, ErrNotInLobby, g.Start("al", false))
+
+	// al can't settle the first round alone any more
+	assert.NoError(t, g.Play("al", g.Players[0].Punchlines[0]))
+	assert.Equal(t, PLAY, g.CurrentAction)
+	assert.NoError(t, g.AddPlayer(Player{Name: "dee"}, ""))
+	assert.Len(t, g.Players[3].Punchlines, 3, "dealt in as they join")
+	assert.Equal(t, 2, g.Players[3].JoinedAtRound)
+}
+
+func TestReadyCheck(t *testing.T) {
+	g := &Game{
+		Players:         []Player{{Name: "al"}},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14", "15", "16", "17", "18"},
+		Rounds:          make([]Round, 1),
+		RoundsRemaining: 1,
+		CurrentAction:   LOBBY,
+		PlaySeconds:     30,
+	}
+	assert.Equal(t, ErrNotInLobby, g.SetReady("al", true), "not a ready check game")
+	WithReadyCheck()(g)
+	g.setDeadline()
+	assert.Nil(t, g.PhaseDeadline, "the lobby isn't timed")
+
+	assert.NoError(t, g.AddPlayer(Player{Name: "bob"}, ""))
+	assert.Empty(t, g.Players[1].Punchlines, "nothing's dealt in the lobby")
+	assert.NoError(t, g.SetReady("al", true))
+	assert.NoError(t, g.SetReady("bob", true))
+	assert.Equal(t, ErrPlayerNotFound, g.SetReady("carl", true))
+
+	// a new player means everyone confirms again
+	assert.NoError(t, g.AddPlayer(Player{Name: "carl"}, ""))
+	for _, player := range g.Players {
+		assert.False(t, player.Ready, player.Name)
+	}
+	assert.NoError(t, g.SetReady("al", true))
+	assert.NoError(t, g.SetReady("bob", true))
+	assert.Equal(t, ErrNotHost, g.Start("bob", true))
+	assert.Equal(t, ErrPlayersNotReady, g.Start("al", false))
+	assert.Equal(t, LOBBY, g.CurrentAction)
+
+	assert.NoError(t, g.Start("al", true))
+	assert.Equal(t, PLAY, g.CurrentAction)
+	assert.Equal(t, []string{"carl"}, g.Unready)
+	assert.NotNil(t, g.PhaseDeadline)
+	for _, player := range g.Players {
+		assert.Len(t, player.Punchlines, defaultHandSize, player.Name)
+	}
+	assert.Equal(t, ErrNotInLobby, g.Start("al", true))
+	assert.Equal(t, ErrNotInLobby, g.SetReady("carl", true))
+}
+
+func TestReadyCheckRace(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		g := &Game{
+			mu:              new(gameLock),
+			Players:         []Player{{Name: "al", Ready: true}, {Name: "bob"}},
+			Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"},
+			Rounds:          make([]Round, 1),
+			RoundsRemaining: 1,
+			CurrentAction:   LOBBY,
+		}
+		WithReadyCheck()(g)
+		var wg sync.WaitGroup
+		var readyErr, startErr error
+		wg.Add(2)
+		go func() { defer wg.Done(); readyErr = g.SetReady("bob", true) }()
+		go func() { defer wg.Done(); startErr = g.Start("al", false) }()
+		wg.Wait()
+		// either the start saw bob ready, or it failed and left bob's toggle in the lobby
+		if startErr == nil {
+			assert.Equal(t, PLAY, g.CurrentAction)
+			assert.Empty(t, g.Unready)
+			if readyErr != nil {
+				assert.Equal(t, ErrNotInLobby, readyErr)
+			}
+		} else {
+			assert.Equal(t, ErrPlayersNotReady, startErr)
+			assert.NoError(t, readyErr)
+			assert.Equal(t, LOBBY, g.CurrentAction)
+			assert.True(t, g.Players[1].Ready)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// DefaultLocale is used for games that don't set one, and for any string
+// missing from a game's locale.
+const DefaultLocale = "en"
+
+var ErrUnknownLocale = errors.New("unknown locale")
+
+// messages is the catalog of server-generated text, locale:key:format.
+// Formats take fmt verbs.
+var messages = map[string]map[string]string{
+	"en": {
+		"game":            "Game %d",
+		"player":          "Player",
+		"rounds_won":      "Rounds won",
+		"standings":       "Standings:",
+		"round_heading":   "Round %d: the difference between %s and %s",
+		"round_heading_3": "Round %d: the difference between %s, %s and %s",
+		"winner":          "winner",
+		"vote":            "%d vote",
+		"votes":           "%d votes",
+		"winner_quote":    `"%s" - the winner`,
+		"joined_round":    "joined round %d",
+	},
+	"es": {
+		"game":            "Partida %d",
+		"player":          "Jugador",
+		"rounds_won":      "Rondas ganadas",
+		"standings":       "Clasificación:",
+		"round_heading":   "Ronda %d: la diferencia entre %s y %s",
+		"round_heading_3": "Ronda %d: la diferencia entre %s, %s y %s",
+		"winner":          "ganador",
+		"vote":            "%d voto",
+		"votes":           "%d votos",
+		"winner_quote":    `"%s" - el ganador`,
+		"joined_round":    "se unió en la ronda %d",
+	},
+}
+
+// missingMessages remembers which missing translations have been logged, so
+// each is only logged once.
+var missingMessages sync.Map
+
+// WithLocale has the game's generated text, such as its transcript, use
+// locale. Check it with ValidateLocale.
+func WithLocale(locale string) Option {
+	return func(g *Game) {
+		g.Locale = locale
+	}
+}
+
+func ValidateLocale(locale string) error {
+	if _, ok := messages[locale]; !ok {
+		return ErrUnknownLocale
+	}
+	return nil
+}
+
+// translate formats the message key in locale, falling back to
+// DefaultLocale if locale doesn't have it.
+func translate(locale, key string, args ...interface{}) string {
+	format, ok := messages[locale][key]
+	if !ok {
+		if locale != "" && locale != DefaultLocale {
+			if _, logged := missingMessages.LoadOrStore(locale+"/"+key, true); !logged {
+				log.Printf("locale %s: no translation for %q, using %s", locale, key, DefaultLocale)
+			}
+		}
+		format = messages[DefaultLocale][key]
+	}
+	return fmt.Sprintf(format, args...)
+}
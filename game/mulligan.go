@@ -0,0 +1,53 @@
+package game
+
+import "errors"
+
+var (
+	ErrMulliganUsed   = errors.New("you've already used your mulligan")
+	ErrMulliganPlayed = errors.New("can't mulligan with a card played this round")
+)
+
+// Mulligan swaps playerName's whole hand for a fresh one, once a game. The
+// old hand goes to the bottom of the deck. It can't be used while they have
+// a card in the current round.
+func (g *Game) Mulligan(playerName string) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if g.isSpectator(playerName) {
+		return ErrSpectator
+	}
+	if g.over() {
+		return ErrGameFinished
+	}
+	if g.CurrentAction == LOBBY {
+		return ErrWrongPhase
+	}
+	var player *Player
+	for i := range g.Players {
+		if g.Players[i].Name == playerName {
+			player = &g.Players[i]
+		}
+	}
+	if player == nil {
+		return ErrPlayerNotFound
+	}
+	if player.Mulliganed {
+		return ErrMulliganUsed
+	}
+	if g.RoundsRemaining > 0 {
+		if _, ok := g.Rounds[g.RoundsRemaining-1].Plays[playerName]; ok {
+			return ErrMulliganPlayed
+		}
+	}
+
+	old := player.Punchlines
+	player.Punchlines = nil
+	err := g.topUp(player)
+	if err != nil {
+		player.Punchlines = old
+		return err
+	}
+	g.Punchlines = append(append([]Card(nil), old...), g.Punchlines...)
+	player.Mulliganed = true
+	return nil
+}
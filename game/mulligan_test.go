@@ -0,0 +1,36 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMulligan(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	assert.Equal(t, ErrPlayerNotFound, g.Mulligan("carl"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.Equal(t, ErrMulliganPlayed, g.Mulligan("bob"))
+
+	assert.NoError(t, g.Mulligan("al"))
+	assert.Equal(t, []Card{"2", "3", "4", "5", "6", "7"}, g.Players[0].Punchlines)
+	assert.Equal(t, []Card{"a1", "a2", "a3", "a4", "a5", "a6", "1"}, g.Punchlines, "old hand at the bottom")
+	assert.True(t, g.Players[0].Mulliganed)
+	assert.Equal(t, ErrMulliganUsed, g.Mulligan("al"))
+
+	// once the round is settled bob can
+	assert.NoError(t, g.Play("al", "2"))
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("bob", "2"))
+	assert.NoError(t, g.Mulligan("bob"))
+	assert.Len(t, g.Players[1].Punchlines, defaultHandSize)
+}
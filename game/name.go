@@ -0,0 +1,36 @@
+package game
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const maxPlayerNameLength = 32 // runes
+
+var ErrInvalidPlayerName = errors.New("player name must be 1 to 32 printable characters")
+
+// NormalizePlayerName is the canonical form of a player's name: NFC, trimmed,
+// and with internal runs of whitespace collapsed, so names that only differ
+// in spacing are the same name. Names are normalized before they're checked
+// or compared.
+func NormalizePlayerName(name string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(name)), " ")
+}
+
+// ValidatePlayerName checks a normalized name isn't empty, too long or made
+// of anything unprintable.
+func ValidatePlayerName(name string) error {
+	if name == "" || utf8.RuneCountInString(name) > maxPlayerNameLength {
+		return ErrInvalidPlayerName
+	}
+	for _, r := range name {
+		if !unicode.IsPrint(r) {
+			return ErrInvalidPlayerName
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePlayerName(t *testing.T) {
+	assert.Equal(t, "al b", NormalizePlayerName("  al   b\t"))
+	for _, name := range []string{"al", "Zoë", "al b", strings.Repeat("é", maxPlayerNameLength)} {
+		assert.NoError(t, ValidatePlayerName(NormalizePlayerName(name)), name)
+	}
+	for _, name := range []string{"", " \t ", strings.Repeat("x", maxPlayerNameLength+1), "al\x00", "al\u200b"} {
+		assert.Equal(t, ErrInvalidPlayerName, ValidatePlayerName(NormalizePlayerName(name)), name)
+	}
+}
+
+func TestAddPlayerNormalizesName(t *testing.T) {
+	g := &Game{
+		Players:         []Player{{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}}},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+		Rounds:          make([]Round, 1),
+		RoundsRemaining: 1,
+		CurrentAction:   PLAY,
+		MaxPlayers:      defaultMaxPlayers,
+	}
+	assert.Equal(t, ErrInvalidPlayerName, g.AddPlayer(Player{Name: "  "}, ""))
+	assert.Equal(t, ErrNameTaken, g.AddPlayer(Player{Name: " al"}, ""))
+	assert.Equal(t, ErrNameTaken, g.AddSpectator("al ", ""))
+	assert.NoError(t, g.AddPlayer(Player{Name: " bob  smith "}, ""))
+	assert.Equal(t, "bob smith", g.Players[1].Name)
+	assert.Equal(t, ErrInvalidPlayerName, g.RenamePlayer("bob smith", ""))
+}
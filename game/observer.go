@@ -0,0 +1,144 @@
+package game
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"sort"
+	"time"
+)
+
+var ErrObserverNotFound = errors.New("observer key not found")
+
+// ObserverKey describes a read-only key the host issued for the game, e.g.
+// for a stream overlay. The key itself is only returned when it's created.
+type ObserverKey struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+
+	hash string
+}
+
+// Observation is the game as an observer sees it: only what's public to
+// the table. Hands are never included, and neither is who voted for what,
+// who played which card while a round is open, or which cards have been
+// played before voting starts.
+type Observation struct {
+	GameID          int             `json:"gameId"`
+	CurrentAction   string          `json:"currentAction"`
+	RoundsRemaining int             `json:"roundsRemaining"`
+	PhaseDeadline   *time.Time      `json:"phaseDeadline,omitempty"`
+	Setup           []Card          `json:"setup,omitempty"` // the current round's
+	Plays           []Card          `json:"plays"`           // the current round's, sorted, once voting starts
+	Played          int             `json:"played"`          // how many have played this round
+	Players         int             `json:"players"`         // how many are yet to play is Players-Played
+	Standings       []Standing      `json:"standings"`
+	Rounds          []ObservedRound `json:"rounds"` // finished, in the order they were played
+}
+
+type ObservedRound struct {
+	Setup       []Card `json:"setup"`
+	Winner      string `json:"winner,omitempty"`
+	WinningCard Card   `json:"winningCard,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+}
+
+// AddObserver issues a new observer key at the host's request.
+func (g *Game) AddObserver(playerName string) (string, ObserverKey, error) {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.isHost(playerName) {
+		return "", ObserverKey{}, ErrNotHost
+	}
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", ObserverKey{}, err
+	}
+	key := base64.RawURLEncoding.EncodeToString(b)
+	hash := hashToken(key)
+	observer := ObserverKey{ID: hash[:12], Created: now(), hash: hash}
+	g.observers = append(g.observers, observer)
+	return key, observer, nil
+}
+
+// Observers lists the game's observer keys for the host.
+func (g *Game) Observers(playerName string) ([]ObserverKey, error) {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.isHost(playerName) {
+		return nil, ErrNotHost
+	}
+	observers := make([]ObserverKey, len(g.observers))
+	copy(observers, g.observers)
+	return observers, nil
+}
+
+// RevokeObserver stops the observer key with the given id from working.
+func (g *Game) RevokeObserver(playerName, id string) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.isHost(playerName) {
+		return ErrNotHost
+	}
+	for i, observer := range g.observers {
+		if observer.ID == id {
+			g.observers = append(g.observers[:i], g.observers[i+1:]...)
+			return nil
+		}
+	}
+	return ErrObserverNotFound
+}
+
+// ObserverID returns the id of the observer key, if it's one of the game's.
+func (g *Game) ObserverID(key string) (string, bool) {
+	g.mutex().lockToRead()
+	defer g.mutex().Unlock()
+	hash := hashToken(key)
+	for _, observer := range g.observers {
+		if subtle.ConstantTimeCompare([]byte(observer.hash), []byte(hash)) == 1 {
+			return observer.ID, true
+		}
+	}
+	return "", false
+}
+
+// Observe returns the game as an observer sees it.
+func (g *Game) Observe() Observation {
+	g.mutex().lockToRead()
+	defer g.mutex().Unlock()
+	o := Observation{
+		GameID:          g.ID,
+		CurrentAction:   g.CurrentAction,
+		RoundsRemaining: g.RoundsRemaining,
+		PhaseDeadline:   g.PhaseDeadline,
+		Plays:           []Card{},
+		Players:         len(g.Players),
+		Standings:       g.standings(),
+		Rounds:          []ObservedRound{},
+	}
+	if g.RoundsRemaining > 0 && g.RoundsRemaining <= len(g.Rounds) {
+		round := g.Rounds[g.RoundsRemaining-1]
+		o.Setup = round.Setup
+		o.Played = len(round.Plays)
+		// cards played so far stay hidden until the ballot is out, or the
+		// order they come in would tell whose is whose
+		if g.CurrentAction == VOTE {
+			for _, card := range round.Plays {
+				o.Plays = append(o.Plays, card)
+			}
+			sort.Slice(o.Plays, func(i, j int) bool { return o.Plays[i] < o.Plays[j] })
+		}
+	}
+	for i := len(g.Rounds) - 1; i >= g.RoundsRemaining && i >= 0; i-- {
+		round := g.Rounds[i]
+		o.Rounds = append(o.Rounds, ObservedRound{
+			Setup:       round.Setup,
+			Winner:      round.Winner,
+			WinningCard: round.Plays[round.Winner],
+			Comment:     round.Comment,
+		})
+	}
+	return o
+}
@@ -0,0 +1,76 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObservers(t *testing.T) {
+	g := &Game{
+		ID: 997,
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+		Rounds:          []Round{{Setup: []Card{"s3", "t3"}}, {Setup: []Card{"s2", "t2"}}},
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+
+	_, _, err := g.AddObserver("bob")
+	assert.Equal(t, ErrNotHost, err)
+	key, observer, err := g.AddObserver("al")
+	assert.NoError(t, err)
+	id, ok := g.ObserverID(key)
+	assert.True(t, ok)
+	assert.Equal(t, observer.ID, id)
+	_, ok = g.ObserverID("nope")
+	assert.False(t, ok)
+	_, ok = g.TokenPlayer(key)
+	assert.False(t, ok, "not a player token")
+	observers, err := g.Observers("al")
+	assert.NoError(t, err)
+	assert.Equal(t, []ObserverKey{observer}, observers)
+	_, err = g.Observers("bob")
+	assert.Equal(t, ErrNotHost, err)
+
+	j, err := json.Marshal(g)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(j), observer.ID)
+	assert.NotContains(t, string(j), key)
+
+	assert.NoError(t, g.Play("bob", "b2"))
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Vote("al", "b2"))
+	assert.NoError(t, g.Vote("bob", "b2"))
+	assert.NoError(t, g.Play("bob", "b1"))
+
+	o := g.Observe()
+	assert.Equal(t, []Card{"s3", "t3"}, o.Setup)
+	assert.Empty(t, o.Plays, "hidden until voting starts")
+	assert.Equal(t, 1, o.Played)
+	assert.Equal(t, 2, o.Players)
+	assert.Equal(t, []Standing{{Player: "bob", RoundsWon: 1}, {Player: "al"}}, o.Standings)
+	assert.Equal(t, []ObservedRound{{Setup: []Card{"s2", "t2"}, Winner: "bob", WinningCard: "b2"}}, o.Rounds)
+	j, err = json.Marshal(o)
+	assert.NoError(t, err)
+	for _, card := range g.Players[0].Punchlines {
+		assert.NotContains(t, string(j), `"`+string(card)+`"`, "hands are private")
+	}
+	assert.NotContains(t, string(j), "votes")
+	assert.NotContains(t, string(j), `"b1"`, "played before voting")
+
+	assert.NoError(t, g.Play("al", "a2"))
+	o = g.Observe()
+	assert.Equal(t, []Card{"a2", "b1"}, o.Plays)
+	assert.Equal(t, 2, o.Played)
+
+	assert.Equal(t, ErrNotHost, g.RevokeObserver("bob", observer.ID))
+	assert.NoError(t, g.RevokeObserver("al", observer.ID))
+	assert.Equal(t, ErrObserverNotFound, g.RevokeObserver("al", observer.ID))
+	_, ok = g.ObserverID(key)
+	assert.False(t, ok)
+}
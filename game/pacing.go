@@ -0,0 +1,119 @@
+package game
+
+import (
+	"sort"
+	"time"
+)
+
+// roundTiming records when a round's phases started and when each player
+// acted in them. Auto-plays aren't recorded, as the player didn't act.
+type roundTiming struct {
+	PlayStarted time.Time
+	VoteStarted time.Time
+	Plays       map[string]time.Time
+	Votes       map[string]time.Time
+}
+
+// Pacing summarizes how long players have taken this game, for the host
+// tuning timers. It names the slowest player, so only the host sees it.
+type Pacing struct {
+	AveragePlaySeconds float64        `json:"averagePlaySeconds"`
+	AverageVoteSeconds float64        `json:"averageVoteSeconds"`
+	SlowestPlayer      string         `json:"slowestPlayer,omitempty"`
+	Players            []PlayerPacing `json:"players"` // slowest first
+}
+
+type PlayerPacing struct {
+	Player         string  `json:"player"`
+	AverageSeconds float64 `json:"averageSeconds"` // over their plays and votes
+}
+
+// timing returns the current round's timing, creating it if need be.
+func (g *Game) timing() *roundTiming {
+	if g.timings == nil {
+		g.timings = make(map[int]*roundTiming)
+	}
+	index := g.RoundsRemaining - 1
+	if g.timings[index] == nil {
+		g.timings[index] = &roundTiming{Plays: make(map[string]time.Time), Votes: make(map[string]time.Time)}
+	}
+	return g.timings[index]
+}
+
+// recordAction notes when playerName first played or voted in the current
+// round.
+func (g *Game) recordAction(playerName, action string) {
+	if g.RoundsRemaining <= 0 {
+		return
+	}
+	times := g.timing().Plays
+	if action == VOTE {
+		times = g.timing().Votes
+	}
+	if _, ok := times[playerName]; !ok {
+		times[playerName] = now()
+	}
+}
+
+// Pacing returns the game's pacing so far for its host.
+func (g *Game) Pacing(playerName string) (Pacing, error) {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.isHost(playerName) {
+		return Pacing{}, ErrNotHost
+	}
+	timings := make([]roundTiming, 0, len(g.timings))
+	for _, timing := range g.timings {
+		timings = append(timings, *timing)
+	}
+	return pacing(timings), nil
+}
+
+// pacing averages the time from each phase starting to each player acting
+// in it. Phases with no recorded start are skipped.
+func pacing(timings []roundTiming) Pacing {
+	var plays, votes time.Duration
+	var playCount, voteCount int
+	totals := make(map[string]time.Duration)
+	counts := make(map[string]int)
+	for _, timing := range timings {
+		if !timing.PlayStarted.IsZero() {
+			for player, t := range timing.Plays {
+				d := t.Sub(timing.PlayStarted)
+				plays += d
+				playCount++
+				totals[player] += d
+				counts[player]++
+			}
+		}
+		if !timing.VoteStarted.IsZero() {
+			for player, t := range timing.Votes {
+				d := t.Sub(timing.VoteStarted)
+				votes += d
+				voteCount++
+				totals[player] += d
+				counts[player]++
+			}
+		}
+	}
+	p := Pacing{Players: []PlayerPacing{}}
+	if playCount > 0 {
+		p.AveragePlaySeconds = plays.Seconds() / float64(playCount)
+	}
+	if voteCount > 0 {
+		p.AverageVoteSeconds = votes.Seconds() / float64(voteCount)
+	}
+	for player, total := range totals {
+		p.Players = append(p.Players, PlayerPacing{Player: player, AverageSeconds: total.Seconds() / float64(counts[player])})
+	}
+	sort.Slice(p.Players, func(i, j int) bool {
+		if p.Players[i].AverageSeconds != p.Players[j].AverageSeconds {
+			return p.Players[i].AverageSeconds > p.Players[j].AverageSeconds
+		}
+		return p.Players[i].Player < p.Players[j].Player
+	})
+	if len(p.Players) > 0 {
+		p.SlowestPlayer = p.Players[0].Player
+	}
+	return p
+}
@@ -0,0 +1,121 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPacing(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Second * time.Duration(seconds)) }
+
+	tests := []struct {
+		name     string
+		timings  []roundTiming
+		expected Pacing
+	}{
+		{
+			name:     "nothing recorded",
+			expected: Pacing{Players: []PlayerPacing{}},
+		},
+		{
+			name: "one round",
+			timings: []roundTiming{{
+				PlayStarted: at(0),
+				VoteStarted: at(30),
+				Plays:       map[string]time.Time{"al": at(10), "bob": at(30)},
+				Votes:       map[string]time.Time{"al": at(34), "bob": at(36)},
+			}},
+			expected: Pacing{
+				AveragePlaySeconds: 20,
+				AverageVoteSeconds: 5,
+				SlowestPlayer:      "bob",
+				Players:            []PlayerPacing{{"bob", 18}, {"al", 7}},
+			},
+		},
+		{
+			name: "across rounds, with voting still open",
+			timings: []roundTiming{
+				{
+					PlayStarted: at(0),
+					VoteStarted: at(20),
+					Plays:       map[string]time.Time{"al": at(20), "bob": at(10)},
+					Votes:       map[string]time.Time{"al": at(30), "bob": at(30)},
+				},
+				{
+					PlayStarted: at(30),
+					Plays:       map[string]time.Time{"al": at(60)},
+					Votes:       map[string]time.Time{},
+				},
+			},
+			expected: Pacing{
+				AveragePlaySeconds: 20,
+				AverageVoteSeconds: 10,
+				SlowestPlayer:      "al",
+				Players:            []PlayerPacing{{"al", 20}, {"bob", 10}},
+			},
+		},
+		{
+			name: "ties slowest by name",
+			timings: []roundTiming{{
+				PlayStarted: at(0),
+				Plays:       map[string]time.Time{"carl": at(5), "bob": at(5)},
+			}},
+			expected: Pacing{
+				AveragePlaySeconds: 5,
+				SlowestPlayer:      "bob",
+				Players:            []PlayerPacing{{"bob", 5}, {"carl", 5}},
+			},
+		},
+		{
+			name: "unknown start skipped",
+			timings: []roundTiming{{
+				Plays: map[string]time.Time{"al": at(5)},
+			}},
+			expected: Pacing{Players: []PlayerPacing{}},
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, pacing(test.timings), test.name)
+	}
+}
+
+func TestGamePacing(t *testing.T) {
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	g.startPlaying()
+	clock = clock.Add(time.Second * 4)
+	assert.NoError(t, g.Play("al", "a1"))
+	clock = clock.Add(time.Second * 8)
+	assert.NoError(t, g.Play("bob", "b1"))
+	clock = clock.Add(time.Second * 2)
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("al", "b1"), "a retried vote doesn't reset the time")
+	clock = clock.Add(time.Second * 4)
+	assert.NoError(t, g.Vote("bob", "a1"))
+
+	_, err := g.Pacing("bob")
+	assert.Equal(t, ErrNotHost, err)
+	p, err := g.Pacing("al")
+	assert.NoError(t, err)
+	assert.Equal(t, Pacing{
+		AveragePlaySeconds: 8,
+		AverageVoteSeconds: 4,
+		SlowestPlayer:      "bob",
+		Players:            []PlayerPacing{{"bob", 9}, {"al", 3}},
+	}, p)
+}
@@ -0,0 +1,68 @@
+package game
+
+import "errors"
+
+var ErrPairPlay = errors.New("write-ins can't be played in pair-play games")
+
+// WithPairPlay has each player answer every setup of the round rather than
+// the round as a whole: they play one card per setup, in the setups'
+// order, each with its own Play. Once all of a player's cards are in,
+// their first stands for the whole set in Plays, on the ballot and in
+// votes, and Round.Pairs has the lot; BallotPairs shows voters each set
+// without saying whose it is. Until then their play can be retracted like
+// any other, and if voting starts first the cards go back to their hand.
+// Write-ins aren't allowed.
+func WithPairPlay() Option {
+	return func(g *Game) {
+		g.PairPlay = true
+	}
+}
+
+// playsLeft is how many more cards playerName has to play in round: one,
+// or in pair-play games one for each setup they haven't answered.
+func (g *Game) playsLeft(round Round, playerName string) int {
+	if _, ok := round.Plays[playerName]; ok {
+		return 0
+	}
+	if !g.PairPlay {
+		return 1
+	}
+	return g.setupSize() - len(round.Pairs[playerName])
+}
+
+// playPair adds card to playerName's cards in round and reports whether
+// that completes their play, and if so which card stands for it.
+func (g *Game) playPair(round *Round, playerName string, card Card) (Card, bool) {
+	if round.Pairs == nil {
+		round.Pairs = make(map[string][]Card)
+	}
+	pair := append(round.Pairs[playerName], card)
+	round.Pairs[playerName] = pair
+	if len(pair) < g.setupSize() {
+		return "", false
+	}
+	return pair[0], true
+}
+
+// returnUnfinishedPairs gives back the cards of players who hadn't answered
+// every setup by the time voting starts.
+func (g *Game) returnUnfinishedPairs(round *Round) {
+	for name := range round.Pairs {
+		if _, ok := round.Plays[name]; !ok {
+			g.returnPlay(round, name, "")
+		}
+	}
+}
+
+// fillBallotPairs shows the set each card on round's ballot stands for.
+func (round *Round) fillBallotPairs() {
+	if len(round.Pairs) == 0 {
+		return
+	}
+	round.BallotPairs = make(map[Card][]Card, len(round.Pairs))
+	for name, card := range round.Plays {
+		if pair, ok := round.Pairs[name]; ok {
+			round.BallotPairs[card] = pair
+		}
+	}
+}
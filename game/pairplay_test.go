@@ -0,0 +1,67 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPairPlay(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "cy", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+		Rounds:          []Round{{Setup: []Card{"s3", "t3"}}, {Setup: []Card{"s2", "t2"}}, {Setup: []Card{"s1", "t1"}}},
+		RoundsRemaining: 3,
+		CurrentAction:   PLAY,
+		PairPlay:        true,
+	}
+	assert.Equal(t, ErrNotPlayed, g.RetractPlay("al"))
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("al", "a1"), "retried")
+	assert.Equal(t, []Card{"a1"}, g.Rounds[2].Pairs["al"])
+	assert.Empty(t, g.Rounds[2].Plays)
+	assert.Len(t, g.Players[0].Punchlines, 5)
+	assert.NoError(t, g.RetractPlay("al"), "half a pair")
+	assert.Empty(t, g.Rounds[2].Pairs)
+	assert.Len(t, g.Players[0].Punchlines, 6)
+
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("al", "a2"))
+	assert.Equal(t, map[string]Card{"al": "a1"}, g.Rounds[2].Plays)
+	assert.Equal(t, ErrAlreadyPlayed, g.Play("al", "a3"))
+	assert.Equal(t, ErrPairPlay, g.PlayWriteIn("bob", "my own"))
+	assert.NoError(t, g.Play("bob", "b2"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Play("cy", "c1"))
+	assert.Equal(t, PLAY, g.CurrentAction, "cy has a setup to answer")
+	view := g.Rounds[2].viewFor("bob")
+	assert.Equal(t, map[string][]Card{"bob": {"b2", "b1"}}, view.Pairs)
+	assert.NoError(t, g.Play("cy", "c2"))
+	assert.Equal(t, VOTE, g.CurrentAction)
+	assert.Equal(t, map[Card][]Card{"a1": {"a1", "a2"}, "b2": {"b2", "b1"}, "c1": {"c1", "c2"}}, g.Rounds[2].BallotPairs)
+
+	for _, name := range []string{"al", "bob", "cy"} {
+		assert.NoError(t, g.Vote(name, "b2"))
+	}
+	assert.Equal(t, "bob", g.Rounds[2].Winner)
+	assert.Equal(t, []Card{"b2", "b1"}, g.Rounds[2].Results[0].Pair)
+	assert.Len(t, g.discards, 6)
+	for _, player := range g.Players {
+		assert.Len(t, player.Punchlines, 6, "both cards replaced")
+	}
+
+	// voting starts before cy finishes, so cy's card comes back
+	assert.NoError(t, g.Play("al", g.Players[0].Punchlines[0]))
+	assert.NoError(t, g.Play("al", g.Players[0].Punchlines[0]))
+	assert.NoError(t, g.Play("bob", g.Players[1].Punchlines[0]))
+	assert.NoError(t, g.Play("bob", g.Players[1].Punchlines[0]))
+	assert.NoError(t, g.Play("cy", g.Players[2].Punchlines[0]))
+	g.startVoting()
+	assert.Len(t, g.Rounds[1].Ballot, 2)
+	assert.NotContains(t, g.Rounds[1].Pairs, "cy")
+	assert.Len(t, g.Players[2].Punchlines, 6)
+}
@@ -0,0 +1,63 @@
+package game
+
+import "errors"
+
+var (
+	ErrGamePaused = errors.New("game is paused")
+	ErrNotPaused  = errors.New("game isn't paused")
+)
+
+// Pause stops the game until Resume: nobody can play or vote, the phase
+// timer stops counting down, and neither inactive players nor the game
+// itself are reaped for a while. Only the host can pause, and only once the
+// game has started.
+func (g *Game) Pause(playerName string) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.isHost(playerName) {
+		return ErrNotHost
+	}
+	if g.over() {
+		return ErrGameFinished
+	}
+	if g.CurrentAction == LOBBY {
+		return ErrWrongPhase
+	}
+	if g.Paused {
+		return ErrGamePaused
+	}
+	t := now()
+	g.Paused = true
+	g.PausedBy = playerName
+	g.PausedAt = &t
+	return nil
+}
+
+// Resume restarts a paused game. The phase timer carries on with the time
+// it had left, the game's GameTTL is extended by the time spent paused, and
+// everyone counts as seen now so DropInactive doesn't remove them for
+// having been away.
+func (g *Game) Resume(playerName string) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.isHost(playerName) {
+		return ErrNotHost
+	}
+	if !g.Paused {
+		return ErrNotPaused
+	}
+	t := now()
+	paused := t.Sub(*g.PausedAt)
+	if g.PhaseDeadline != nil {
+		deadline := g.PhaseDeadline.Add(paused)
+		g.PhaseDeadline = &deadline
+	}
+	g.Created = g.Created.Add(paused)
+	for i := range g.Players {
+		g.Players[i].LastSeen = t
+	}
+	g.Paused = false
+	g.PausedBy = ""
+	g.PausedAt = nil
+	return nil
+}
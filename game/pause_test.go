@@ -0,0 +1,82 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPause(t *testing.T) {
+	start := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	clock := start
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+	defer config.Set(config.Current())
+	cfg := *config.Current()
+	cfg.GameTTL = time.Hour
+	cfg.InactiveTimeout = time.Minute
+	config.Set(&cfg)
+	savedGames, savedIDs := games, gameIDs
+	defer func() { games, gameIDs = savedGames, savedIDs }()
+	games, gameIDs = make(map[int]*Game), newIDPool(1)
+	id, err := findID(testRNG)
+	assert.NoError(t, err)
+
+	g := &Game{
+		ID:   id,
+		Host: "al",
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}, LastSeen: start},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}, LastSeen: start},
+			{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}, LastSeen: start},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   LOBBY,
+		PlaySeconds:     60,
+		Created:         start.Add(-time.Minute * 50),
+	}
+	games[id] = g
+	assert.Equal(t, ErrWrongPhase, g.Pause("al"), "not started")
+	g.CurrentAction = PLAY
+	g.setDeadline()
+	assert.Equal(t, ErrNotPaused, g.Resume("al"))
+	assert.Equal(t, ErrNotHost, g.Pause("bob"))
+	assert.NoError(t, g.Play("al", "a1"))
+
+	clock = start.Add(time.Second * 10)
+	assert.NoError(t, g.Pause("al"))
+	assert.Equal(t, ErrGamePaused, g.Pause("al"))
+	assert.True(t, g.Paused)
+	assert.Equal(t, "al", g.PausedBy)
+	assert.Equal(t, clock, *g.PausedAt)
+	assert.Equal(t, ErrGamePaused, g.Play("bob", "b1"))
+	assert.Equal(t, ErrGamePaused, g.Vote("bob", "a1"))
+	assert.Equal(t, ErrGamePaused, g.RetractPlay("al"))
+	assert.True(t, g.ViewFor("bob", HandDealt).Paused)
+
+	clock = start.Add(time.Minute * 30)
+	assert.Empty(t, ExpireDeadlines(), "the timer stopped")
+	assert.Empty(t, DropInactive(), "nobody's dropped")
+	assert.Equal(t, 0, ReapExpired(), "the game isn't reaped")
+	assert.Equal(t, ErrNotHost, g.Resume("bob"))
+	assert.NoError(t, g.Resume("al"))
+	assert.False(t, g.Paused)
+	assert.Empty(t, g.PausedBy)
+	assert.Nil(t, g.PausedAt)
+	assert.Equal(t, clock.Add(time.Second*50), *g.PhaseDeadline, "the 50 seconds left when paused")
+	assert.Equal(t, start.Add(-time.Minute*50).Add(clock.Sub(start.Add(time.Second*10))), g.Created)
+	assert.Empty(t, DropInactive(), "everyone counts as seen on resuming")
+	assert.Equal(t, 0, ReapExpired())
+
+	clock = clock.Add(time.Second * 50)
+	assert.Equal(t, []*Game{g}, ExpireDeadlines())
+	assert.Equal(t, VOTE, g.CurrentAction)
+
+	assert.NoError(t, g.Pause("al"))
+	clock = clock.Add(time.Hour + time.Second)
+	assert.Equal(t, 1, ReapExpired(), "paused for a whole GameTTL")
+}
@@ -0,0 +1,55 @@
+package game
+
+import (
+	"crypto/subtle"
+	"errors"
+	"unicode/utf8"
+)
+
+const (
+	minPINLength = 4
+	maxPINLength = 64
+)
+
+var (
+	ErrInvalidPIN = errors.New("PINs are 4 to 64 characters")
+	ErrWrongPIN   = errors.New("wrong or missing PIN")
+)
+
+// WithPIN makes joining or watching the game need pin, a short code or a
+// passphrase the host shares with the people they invite, since ids and
+// join codes are easy to guess. Only its hash is kept. Check pin with
+// ValidatePIN; "" means anyone can join.
+func WithPIN(pin string) Option {
+	return func(g *Game) {
+		g.pinHash = ""
+		if pin != "" {
+			g.pinHash = hashToken(pin)
+		}
+	}
+}
+
+// withPINHash gives a rematch the same PIN as the game before.
+func withPINHash(hash string) Option {
+	return func(g *Game) {
+		g.pinHash = hash
+	}
+}
+
+func ValidatePIN(pin string) error {
+	if pin != "" && (utf8.RuneCountInString(pin) < minPINLength || utf8.RuneCountInString(pin) > maxPINLength) {
+		return ErrInvalidPIN
+	}
+	return nil
+}
+
+// checkPIN returns ErrWrongPIN unless the game has no PIN or pin is it.
+func (g *Game) checkPIN(pin string) error {
+	if g.pinHash == "" {
+		return nil
+	}
+	if subtle.ConstantTimeCompare([]byte(g.pinHash), []byte(hashToken(pin))) != 1 {
+		return ErrWrongPIN
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package game
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPIN(t *testing.T) {
+	assert.NoError(t, ValidatePIN(""))
+	assert.NoError(t, ValidatePIN("1234"))
+	assert.NoError(t, ValidatePIN("correct horse battery staple"))
+	assert.Equal(t, ErrInvalidPIN, ValidatePIN("123"))
+	assert.Equal(t, ErrInvalidPIN, ValidatePIN(strings.Repeat("x", 65)))
+
+	source := &staticSource{}
+	for i := 0; i < 30; i++ {
+		*source = append(*source, RatedCard{Text: Card(strconv.Itoa(i)), Rating: "G"})
+	}
+	g, err := NewGame(context.Background(), Player{Name: "al"}, 1, "R", WithCardSource(source), WithHandSize(3), WithPIN("1234"), WithMinPlayers(2))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	assert.NotContains(t, g.pinHash, "1234", "only the hash is kept")
+	assert.True(t, g.Summary().PIN)
+
+	assert.Equal(t, ErrWrongPIN, g.AddPlayer(Player{Name: "bob"}, ""))
+	assert.Equal(t, ErrWrongPIN, g.AddPlayer(Player{Name: "bob"}, "4321"))
+	assert.Equal(t, ErrWrongPIN, g.AddSpectator("sam", ""))
+	assert.Len(t, g.Players, 1)
+	assert.Empty(t, g.Spectators)
+	assert.NoError(t, g.AddPlayer(Player{Name: "bob"}, "1234"))
+	assert.NoError(t, g.AddSpectator("sam", "1234"))
+	assert.NoError(t, g.Start("al", false))
+
+	// the rematch keeps the PIN, and its players
+	assert.NoError(t, g.Play("al", g.Players[0].Punchlines[0]))
+	assert.NoError(t, g.Play("bob", g.Players[1].Punchlines[0]))
+	assert.NoError(t, g.Vote("al", g.Rounds[0].Plays["bob"]))
+	assert.NoError(t, g.Vote("bob", g.Rounds[0].Plays["al"]))
+	next, err := g.Rematch(context.Background(), 1)
+	assert.NoError(t, err)
+	defer deleteGame(next.ID)
+	assert.Len(t, next.Players, 2)
+	assert.Equal(t, ErrWrongPIN, next.AddPlayer(Player{Name: "carl"}, ""))
+
+	// games without a PIN let anyone in, whatever they send
+	open, err := NewGame(context.Background(), Player{Name: "al"}, 1, "R", WithCardSource(source), WithHandSize(3))
+	assert.NoError(t, err)
+	defer deleteGame(open.ID)
+	assert.False(t, open.Summary().PIN)
+	assert.NoError(t, open.AddPlayer(Player{Name: "bob"}, ""))
+	assert.NoError(t, open.AddPlayer(Player{Name: "carl"}, "1234"))
+}
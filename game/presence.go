@@ -0,0 +1,77 @@
+package game
+
+import (
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+)
+
+// Ping records that playerName's client is connected and still there.
+// Spectators can ping, but aren't tracked.
+func (g *Game) Ping(playerName string) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if g.isSpectator(playerName) {
+		return nil
+	}
+	for i := range g.Players {
+		if g.Players[i].Name == playerName {
+			g.Players[i].LastSeen = now()
+			g.Players[i].Connected = true
+			return nil
+		}
+	}
+	return ErrPlayerNotFound
+}
+
+// Disconnect marks playerName as having no connected client. They stay in
+// the game until DropInactive removes them.
+func (g *Game) Disconnect(playerName string) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	for i := range g.Players {
+		if g.Players[i].Name == playerName {
+			g.Players[i].Connected = false
+			return nil
+		}
+	}
+	return ErrPlayerNotFound
+}
+
+// DropInactive removes players who haven't been seen for the configured
+// InactiveTimeout from every unfinished game, so rounds don't wait on them,
+// and returns the games that changed.
+func DropInactive() []*Game {
+	timeout := config.Current().InactiveTimeout
+	if timeout == 0 {
+		return nil
+	}
+	cutoff := now().Add(-timeout)
+	var changed []*Game
+	for _, g := range liveGames() {
+		if g.dropInactive(cutoff) {
+			changed = append(changed, g)
+		}
+	}
+	return changed
+}
+
+// dropInactive removes g's players last seen before cutoff and reports
+// whether there were any. Nobody is dropped from a paused game.
+func (g *Game) dropInactive(cutoff time.Time) bool {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if g.Finished || g.Paused {
+		return false
+	}
+	var inactive []string
+	for _, player := range g.Players {
+		if player.LastSeen.Before(cutoff) {
+			inactive = append(inactive, player.Name)
+		}
+	}
+	for _, name := range inactive {
+		g.removePlayer(name)
+	}
+	return len(inactive) > 0
+}
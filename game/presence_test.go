@@ -0,0 +1,64 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDropInactive(t *testing.T) {
+	start := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	clock := start
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+	defer config.Set(config.Current())
+	cfg := *config.Current()
+	cfg.InactiveTimeout = time.Minute
+	config.Set(&cfg)
+	savedIDs := gameIDs
+	defer func() { gameIDs = savedIDs }()
+	gameIDs = newIDPool(1)
+	id, err := findID(testRNG)
+	assert.NoError(t, err)
+
+	g := &Game{
+		ID: id,
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	games[id] = g
+	defer delete(games, id)
+	for _, name := range []string{"al", "bob", "carl"} {
+		assert.NoError(t, g.Ping(name))
+	}
+	assert.Equal(t, ErrPlayerNotFound, g.Ping("dan"))
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+
+	clock = start.Add(time.Second * 50)
+	assert.NoError(t, g.Ping("al"))
+	assert.NoError(t, g.Ping("bob"))
+	assert.NoError(t, g.Disconnect("bob"))
+	assert.True(t, g.Players[0].Connected)
+	assert.False(t, g.Players[1].Connected, "disconnected but not yet dropped")
+	assert.Empty(t, DropInactive())
+
+	clock = start.Add(time.Second * 70)
+	assert.Equal(t, []*Game{g}, DropInactive())
+	assert.Len(t, g.Players, 2)
+	assert.Equal(t, "bob", g.Players[1].Name)
+	assert.Equal(t, VOTE, g.CurrentAction, "carl was holding up the round")
+
+	cfg.InactiveTimeout = 0
+	clock = start.Add(time.Hour)
+	assert.Empty(t, DropInactive(), "turned off")
+}
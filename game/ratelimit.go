@@ -0,0 +1,39 @@
+package game
+
+import (
+	"errors"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+)
+
+var (
+	ErrTooManyActions = errors.New("too many actions, slow down")
+
+	now = time.Now
+)
+
+// actionLog holds the times of a player's last MaxActions plays and votes,
+// oldest first, so memory per player is bounded regardless of how fast a
+// client sends.
+type actionLog []time.Time
+
+// allowAction records an action by playerName, or returns ErrTooManyActions
+// if they've already made MaxActions within the configured ActionWindow.
+func (g *Game) allowAction(playerName string) error {
+	if g.actions == nil {
+		g.actions = make(map[string]actionLog)
+	}
+	cfg := config.Current()
+	t := now()
+	times := g.actions[playerName]
+	if len(times) >= cfg.MaxActions {
+		// the limit may have been lowered by a reload since times filled up
+		if t.Sub(times[len(times)-cfg.MaxActions]) < cfg.ActionWindow {
+			return ErrTooManyActions
+		}
+		times = append(times[:0], times[len(times)-cfg.MaxActions+1:]...)
+	}
+	g.actions[playerName] = append(times, t)
+	return nil
+}
@@ -0,0 +1,59 @@
+package game
+
+import "errors"
+
+// ReactionEmoji are the reactions React accepts.
+var ReactionEmoji = []string{"😂", "🔥", "💀", "😬", "👏", "🤮"}
+
+var ErrUnknownReaction = errors.New("that reaction isn't allowed")
+
+// React records playerName's emoji reaction to a card on the current
+// round's ballot, replacing any reaction they'd already given it. Reactions
+// can be made once the cards are revealed for voting, and don't count
+// towards scores.
+func (g *Game) React(playerName string, card Card, emoji string) error {
+	if !validReaction(emoji) {
+		return ErrUnknownReaction
+	}
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if g.isSpectator(playerName) {
+		return ErrSpectator
+	}
+	if g.over() {
+		return ErrGameFinished
+	}
+	if _, err := g.hand(playerName); err != nil {
+		return err
+	}
+	if g.CurrentAction != VOTE {
+		return ErrWrongPhase
+	}
+	card = NormalizeCard(string(card))
+	round := g.Rounds[g.RoundsRemaining-1]
+	if !round.onBallot(card) {
+		return ErrNotOnBallot
+	}
+	err := g.allowAction(playerName)
+	if err != nil {
+		return err
+	}
+	if round.Reactions == nil {
+		round.Reactions = make(map[Card]map[string]string)
+	}
+	if round.Reactions[card] == nil {
+		round.Reactions[card] = make(map[string]string)
+	}
+	round.Reactions[card][playerName] = emoji
+	g.Rounds[g.RoundsRemaining-1] = round
+	return nil
+}
+
+func validReaction(emoji string) bool {
+	for _, allowed := range ReactionEmoji {
+		if emoji == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReact(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+		},
+		Spectators:      []string{"sam"},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.Equal(t, ErrWrongPhase, g.React("bob", "a1", "🔥"), "not revealed yet")
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Play("carl", "c1"))
+
+	assert.Equal(t, ErrUnknownReaction, g.React("bob", "a1", "🍕"))
+	assert.Equal(t, ErrNotOnBallot, g.React("bob", "a2", "🔥"))
+	assert.Equal(t, ErrSpectator, g.React("sam", "a1", "🔥"))
+	assert.NoError(t, g.React("bob", "a1", "🔥"))
+	assert.NoError(t, g.React("carl", "a1", "🔥"))
+	assert.NoError(t, g.React("bob", "a1", "💀"), "replaces bob's reaction")
+	assert.NoError(t, g.React("bob", "c1", "😂"))
+	reactions := map[Card]map[string]string{"a1": {"bob": "💀", "carl": "🔥"}, "c1": {"bob": "😂"}}
+	assert.Equal(t, reactions, g.Rounds[1].Reactions)
+	j, err := json.Marshal(g.Rounds[1])
+	assert.NoError(t, err)
+	assert.Contains(t, string(j), `"reactions":{"a1":{"bob":"💀","carl":"🔥"}`)
+
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("bob", "a1"))
+	assert.NoError(t, g.Vote("carl", "a1"))
+	assert.Equal(t, reactions, g.Rounds[1].Reactions, "kept once the round is settled")
+	assert.Equal(t, ErrWrongPhase, g.React("bob", "a1", "🔥"))
+	assert.Equal(t, reactions, g.gameSummary().Rounds[0].Reactions)
+}
@@ -0,0 +1,48 @@
+package game
+
+import (
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+)
+
+// ReapExpired removes games created more than the configured GameTTL ago,
+// and games every player has left, freeing their ids and stopping their
+// phase timers, and returns how many it removed. Time spent paused doesn't
+// count, though a game left paused for a whole GameTTL is removed too. Sessions with no new game in that time are
+// forgotten too, as are finished games' summaries older than the HistoryTTL.
+func ReapExpired() int {
+	cutoff := now().Add(-config.Current().GameTTL)
+	reapSessions(cutoff)
+	reapHistory(now().Add(-config.Current().HistoryTTL))
+	n := 0
+	for _, g := range liveGames() {
+		if g.expiredBy(cutoff) {
+			deleteGame(g.ID)
+			g.stopTimer()
+			n++
+		}
+	}
+	return n
+}
+
+// Expires returns when the game will be removed unless it's paused.
+func (g *Game) Expires() time.Time {
+	g.mutex().lockToRead()
+	defer g.mutex().Unlock()
+	return g.Created.Add(config.Current().GameTTL)
+}
+
+// expiredBy reports whether g's time ran out before cutoff, or everyone
+// left it and nobody has joined since.
+func (g *Game) expiredBy(cutoff time.Time) bool {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if g.abandoned && len(g.Players) == 0 {
+		return true
+	}
+	if g.Paused {
+		return g.PausedAt.Before(cutoff)
+	}
+	return g.Created.Before(cutoff)
+}
@@ -0,0 +1,45 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReapExpired(t *testing.T) {
+	start := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	clock := start
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+	defer config.Set(config.Current())
+	cfg := *config.Current()
+	cfg.GameTTL = time.Hour
+	config.Set(&cfg)
+	savedGames, savedIDs := games, gameIDs
+	defer func() { games, gameIDs = savedGames, savedIDs }()
+	games, gameIDs = make(map[int]*Game), newIDPool(2)
+
+	old, err := findID(testRNG)
+	assert.NoError(t, err)
+	deadline := start.Add(time.Hour * 2)
+	games[old] = &Game{ID: old, Created: start, PhaseDeadline: &deadline}
+	young, err := findID(testRNG)
+	assert.NoError(t, err)
+	games[young] = &Game{ID: young, Created: start.Add(time.Minute * 30)}
+
+	savedOld := games[old]
+	clock = start.Add(time.Hour)
+	assert.Equal(t, 0, ReapExpired(), "not older than the TTL yet")
+	clock = start.Add(time.Hour + time.Second)
+	assert.Equal(t, 1, ReapExpired())
+	_, err = GetGame(old)
+	assert.Equal(t, ErrGameNotFound, err)
+	assert.Nil(t, savedOld.PhaseDeadline, "its timer is stopped")
+	_, err = GetGame(young)
+	assert.NoError(t, err)
+	reused, err := findID(testRNG)
+	assert.NoError(t, err, "the id is free again")
+	assert.Equal(t, old, reused)
+}
@@ -0,0 +1,91 @@
+package game
+
+// Recap is the highlights of a finished game, for a share screen, see
+// Recap.
+type Recap struct {
+	GameID       int         `json:"gameId"`
+	Winners      []string    `json:"winners"`
+	BestPlay     *RecapPlay  `json:"bestPlay"`     // nil if no play got a vote
+	BestRounds   []RecapPlay `json:"bestRounds"`   // each player's, in seating order
+	WinningPlays []RecapPlay `json:"winningPlays"` // every round's, in order
+}
+
+// RecapPlay is one play of the game and how it did, see CardResult.
+type RecapPlay struct {
+	Round    int     `json:"round"` // 1 for the first
+	Setup    []Card  `json:"setup"`
+	Player   string  `json:"player"`
+	Card     Card    `json:"card"`
+	Pair     []Card  `json:"pair,omitempty"`
+	Votes    int     `json:"votes"`
+	Points   int     `json:"points,omitempty"`
+	Audience float64 `json:"audience,omitempty"`
+
+	score float64
+}
+
+// Recap returns the game's highlights once it's finished: the play that
+// did best, each player's best round, and every round's winning plays,
+// ties included. Plays are ranked as roundResults ranks them, and a play
+// that does no better than another keeps the earlier round. Rounds nobody
+// won, e.g. because nobody voted, have no winning plays, and a player whose
+// plays never got a vote still has a best round.
+func (g *Game) Recap() (*Recap, error) {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.Finished {
+		return nil, ErrNotFinished
+	}
+	recap := &Recap{
+		GameID:       g.ID,
+		Winners:      g.FinalWinners,
+		BestRounds:   []RecapPlay{},
+		WinningPlays: []RecapPlay{},
+	}
+	best := make(map[string]RecapPlay)
+	for number := 1; number <= len(g.Rounds); number++ {
+		round := g.Rounds[len(g.Rounds)-number]
+		results := round.Results
+		if results == nil {
+			results = roundResults(round)
+		}
+		for _, result := range results {
+			play := RecapPlay{
+				Round:    number,
+				Setup:    round.Setup,
+				Player:   result.Player,
+				Card:     result.Card,
+				Pair:     result.Pair,
+				Votes:    result.Votes,
+				Points:   result.Points,
+				Audience: result.Audience,
+				score:    result.score(round.Tallies != nil),
+			}
+			if result.Won {
+				recap.WinningPlays = append(recap.WinningPlays, play)
+			}
+			if play.score > 0 && (recap.BestPlay == nil || play.beats(*recap.BestPlay)) {
+				bestPlay := play
+				recap.BestPlay = &bestPlay
+			}
+			if previous, ok := best[play.Player]; !ok || play.beats(previous) {
+				best[play.Player] = play
+			}
+		}
+	}
+	for _, player := range g.Players {
+		if play, ok := best[player.Name]; ok {
+			recap.BestRounds = append(recap.BestRounds, play)
+		}
+	}
+	return recap, nil
+}
+
+// beats reports whether play did better than other, by score and then by
+// first choice votes.
+func (play RecapPlay) beats(other RecapPlay) bool {
+	if difference := play.score - other.score; difference > tallyTolerance || difference < -tallyTolerance {
+		return difference > 0
+	}
+	return play.Votes > other.Votes
+}
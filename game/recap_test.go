@@ -0,0 +1,61 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecap(t *testing.T) {
+	g := &Game{
+		Players: []Player{{Name: "al"}, {Name: "bob"}, {Name: "cy"}},
+		Rounds: []Round{
+			{Setup: []Card{"s3", "t3"}, Results: []CardResult{
+				{Card: "b3", Player: "bob", Votes: 2, Won: true},
+				{Card: "c3", Player: "cy", Votes: 2, Won: true},
+				{Card: "a3", Player: "al"},
+			}},
+			{Setup: []Card{"s2", "t2"}, Results: []CardResult{
+				{Card: "a2", Player: "al"},
+				{Card: "b2", Player: "bob"},
+				{Card: "c2", Player: "cy"},
+			}},
+			{Setup: []Card{"s1", "t1"}, Results: []CardResult{
+				{Card: "a1", Player: "al", Votes: 2, Won: true},
+				{Card: "b1", Player: "bob", Votes: 1},
+				{Card: "c1", Player: "cy"},
+			}},
+		},
+		RoundsRemaining: 1,
+		CurrentAction:   PLAY,
+	}
+	_, err := g.Recap()
+	assert.Equal(t, ErrNotFinished, err)
+
+	g.RoundsRemaining, g.CurrentAction, g.Finished = 0, FINISHED, true
+	g.FinalWinners = []string{"bob"}
+	recap, err := g.Recap()
+	assert.NoError(t, err)
+	a1 := RecapPlay{Round: 1, Setup: []Card{"s1", "t1"}, Player: "al", Card: "a1", Votes: 2, score: 2}
+	b3 := RecapPlay{Round: 3, Setup: []Card{"s3", "t3"}, Player: "bob", Card: "b3", Votes: 2, score: 2}
+	c3 := RecapPlay{Round: 3, Setup: []Card{"s3", "t3"}, Player: "cy", Card: "c3", Votes: 2, score: 2}
+	assert.Equal(t, &Recap{
+		Winners:      []string{"bob"},
+		BestPlay:     &a1,
+		BestRounds:   []RecapPlay{a1, b3, c3},
+		WinningPlays: []RecapPlay{a1, b3, c3},
+	}, recap)
+
+	// nobody ever voted
+	for i := range g.Rounds {
+		for j := range g.Rounds[i].Results {
+			g.Rounds[i].Results[j].Votes, g.Rounds[i].Results[j].Won = 0, false
+		}
+	}
+	recap, err = g.Recap()
+	assert.NoError(t, err)
+	assert.Nil(t, recap.BestPlay)
+	assert.Empty(t, recap.WinningPlays)
+	assert.Len(t, recap.BestRounds, 3)
+	assert.Equal(t, 1, recap.BestRounds[0].Round)
+}
@@ -0,0 +1,47 @@
+package game
+
+import "errors"
+
+var ErrWrongReconnectToken = errors.New("wrong reconnect token")
+
+// IssueReconnectToken creates a random token that lets playerName back into
+// the game with Reconnect, e.g. from another device once theirs has died,
+// keeping only its hash. Issuing another replaces the last.
+func (g *Game) IssueReconnectToken(playerName string) (string, error) {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	for i := range g.Players {
+		if g.Players[i].Name != playerName {
+			continue
+		}
+		token, err := randomToken()
+		if err != nil {
+			return "", err
+		}
+		g.Players[i].ReconnectHash = hashToken(token)
+		return token, nil
+	}
+	return "", ErrPlayerNotFound
+}
+
+// Reconnect returns a copy of playerName's record, hand and score intact,
+// if token is their reconnect token, and counts them as seen so
+// DropInactive leaves them be.
+func (g *Game) Reconnect(playerName, token string) (*Player, error) {
+	playerName = NormalizePlayerName(playerName)
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	for i := range g.Players {
+		if g.Players[i].Name != playerName {
+			continue
+		}
+		if !matchesHash(token, g.Players[i].ReconnectHash) {
+			return nil, ErrWrongReconnectToken
+		}
+		g.Players[i].LastSeen = now()
+		player := g.Players[i]
+		player.Punchlines = append([]Card(nil), player.Punchlines...)
+		return &player, nil
+	}
+	return nil, ErrPlayerNotFound
+}
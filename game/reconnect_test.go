@@ -0,0 +1,45 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconnect(t *testing.T) {
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+	g := &Game{Players: []Player{{Name: "al", Punchlines: []Card{"1", "2"}, Score: 2, LastSeen: clock}, {Name: "bob"}}}
+
+	_, err := g.IssueReconnectToken("carl")
+	assert.Equal(t, ErrPlayerNotFound, err)
+	token, err := g.IssueReconnectToken("al")
+	assert.NoError(t, err)
+	assert.NotContains(t, g.Players[0].ReconnectHash, token, "only the hash is kept")
+
+	clock = clock.Add(time.Minute)
+	_, err = g.Reconnect("al", "nope")
+	assert.Equal(t, ErrWrongReconnectToken, err)
+	_, err = g.Reconnect("bob", token)
+	assert.Equal(t, ErrWrongReconnectToken, err, "bob has no token")
+	_, err = g.Reconnect("carl", token)
+	assert.Equal(t, ErrPlayerNotFound, err)
+	assert.True(t, g.Players[0].LastSeen.Before(clock))
+
+	player, err := g.Reconnect(" al ", token)
+	assert.NoError(t, err)
+	assert.Equal(t, []Card{"1", "2"}, player.Punchlines)
+	assert.Equal(t, 2, player.Score)
+	assert.Equal(t, clock, g.Players[0].LastSeen)
+	player.Punchlines[0] = "3"
+	assert.Equal(t, Card("1"), g.Players[0].Punchlines[0], "a copy")
+
+	again, err := g.IssueReconnectToken("al")
+	assert.NoError(t, err)
+	_, err = g.Reconnect("al", token)
+	assert.Equal(t, ErrWrongReconnectToken, err, "replaced")
+	_, err = g.Reconnect("al", again)
+	assert.NoError(t, err)
+}
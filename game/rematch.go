@@ -0,0 +1,150 @@
+package game
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNotFinished = errors.New("game has not finished")
+
+// Rematch starts a new game of rounds with the same players, in the same
+// order and with the same host, and the same settings, but fresh decks,
+// no scores and a new id and code. Setups from this game aren't dealt again
+// if the deck has enough others. Everyone's already there, so the new game
+// starts straight away unless it has a ready check or too few players. The finished game is
+// left as it was, except that RematchID points at the new one, and later
+// calls return that game rather than starting another.
+func (g *Game) Rematch(ctx context.Context, rounds int) (*Game, error) {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.Finished {
+		return nil, ErrNotFinished
+	}
+	if g.RematchID != nil {
+		if next, err := GetGame(*g.RematchID); err == nil {
+			return next, nil
+		}
+	}
+	host := g.host()
+	if host == "" {
+		return nil, ErrPlayerNotFound
+	}
+	next, err := NewGame(ctx, Player{Name: host, Team: g.team(host)}, rounds, g.Cleanliness, g.rematchOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	next.mutex().Lock()
+	defer next.mutex().Unlock()
+	for _, player := range g.Players {
+		if player.Name == host {
+			continue
+		}
+		err = next.addPlayer(Player{Name: player.Name, Team: player.Team})
+		if err != nil {
+			deleteGame(next.ID)
+			return nil, err
+		}
+	}
+	if !next.readyCheck && next.enoughPlayers() {
+		err = next.start(nil)
+		if err != nil {
+			deleteGame(next.ID)
+			return nil, err
+		}
+	}
+	g.RematchID = &next.ID
+	return next, nil
+}
+
+// rematchOptions recreates the options g was made with.
+func (g *Game) rematchOptions() []Option {
+	opts := []Option{
+		WithCardSource(g.source),
+		WithLocale(g.Locale),
+		WithTimers(g.PlaySeconds, g.VoteSeconds),
+		WithHandSize(g.HandSize),
+		WithMinPlayers(g.MinPlayers),
+		WithMaxPlayers(g.MaxPlayers),
+		WithTieBreak(g.TieBreak),
+		withoutSetups(g.Rounds),
+		withPINHash(g.pinHash),
+	}
+	switch g.VotingMode {
+	case VotingRanked:
+		opts = append(opts, WithRankedVoting(g.RankCount))
+	case VotingJudge:
+		opts = append(opts, WithJudge())
+	case VotingMulti:
+		opts = append(opts, WithVotesPerPlayer(g.VotesPerPlayer))
+	}
+	if g.League != "" {
+		opts = append(opts, WithLeague(g.League))
+	}
+	if g.Session != "" {
+		opts = append(opts, WithSession(g.Session))
+	}
+	if g.webhook != nil {
+		opts = append(opts, WithWebhook(g.webhook.url))
+	}
+	if g.AudienceWeight > 0 {
+		opts = append(opts, WithAudienceVoting(g.AudienceWeight, g.AudiencePooled))
+	}
+	if g.PairPlay {
+		opts = append(opts, WithPairPlay())
+	}
+	if g.SetupSize > 0 {
+		opts = append(opts, WithSetupSize(g.SetupSize))
+	}
+	if g.TargetScore > 0 {
+		opts = append(opts, WithTargetScore(g.TargetScore))
+	}
+	if g.WriteIns > 0 {
+		opts = append(opts, WithWriteIns(g.WriteIns))
+	}
+	if g.AutoPlay {
+		opts = append(opts, WithAutoPlay())
+	}
+	if g.AutoVote {
+		opts = append(opts, WithAutoVote())
+	}
+	if g.Replenish {
+		opts = append(opts, WithReplenish())
+	}
+	if g.Teams {
+		opts = append(opts, WithTeams())
+	}
+	if len(g.Features) > 0 {
+		opts = append(opts, WithFeatures(g.Features...))
+	}
+	return opts
+}
+
+// withoutSetups keeps the setups dealt in rounds out of a new game.
+func withoutSetups(rounds []Round) Option {
+	return func(g *Game) {
+		g.usedSetups = make(map[Card]bool)
+		for _, round := range rounds {
+			for _, setup := range round.Setup {
+				g.usedSetups[setup] = true
+			}
+		}
+	}
+}
+
+// unusedSetups returns setups without the ones in usedSetups, if that
+// leaves enough for the game, and otherwise all of them.
+func (g *Game) unusedSetups(setups []Card) []Card {
+	if len(g.usedSetups) == 0 {
+		return setups
+	}
+	var unused []Card
+	for _, setup := range setups {
+		if !g.usedSetups[setup] {
+			unused = append(unused, setup)
+		}
+	}
+	if maxRounds(len(unused), g.setupSize()) < g.RoundsRemaining {
+		return setups
+	}
+	return unused
+}
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRematch(t *testing.T) {
+	source := &staticSource{}
+	for i := 0; i < 20; i++ {
+		*source = append(*source, RatedCard{Text: Card(strconv.Itoa(i)), Rating: "G"})
+	}
+	g, err := NewGame(context.Background(), Player{Name: "al"}, 2, "R", WithCardSource(source), WithHandSize(4), WithMinPlayers(2))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	assert.NoError(t, g.AddPlayer(Player{Name: "bob"}, ""))
+	assert.NoError(t, g.Start("al", false))
+	_, err = g.Rematch(context.Background(), 2)
+	assert.Equal(t, ErrNotFinished, err)
+
+	for !g.Finished {
+		al, bob := g.Players[0].Punchlines[0], g.Players[1].Punchlines[0]
+		assert.NoError(t, g.Play("al", al))
+		assert.NoError(t, g.Play("bob", bob))
+		assert.NoError(t, g.Vote("al", bob))
+		assert.NoError(t, g.Vote("bob", al))
+	}
+	_, err = g.Rematch(context.Background(), 0)
+	assert.True(t, errors.Is(err, ErrInvalidRounds))
+
+	next, err := g.Rematch(context.Background(), 2)
+	assert.NoError(t, err)
+	defer deleteGame(next.ID)
+	assert.NotEqual(t, g.ID, next.ID)
+	assert.Equal(t, &next.ID, g.RematchID)
+	assert.Equal(t, 4, next.HandSize)
+	assert.Equal(t, PLAY, next.CurrentAction)
+	for i, player := range next.Players {
+		assert.Equal(t, g.Players[i].Name, player.Name)
+		assert.Equal(t, 0, player.Score)
+		assert.Len(t, player.Punchlines, 4)
+	}
+	for _, old := range g.Rounds {
+		for _, round := range next.Rounds {
+			assert.NotContains(t, round.Setup, old.Setup[0])
+			assert.NotContains(t, round.Setup, old.Setup[1])
+		}
+	}
+
+	again, err := g.Rematch(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, next, again, "everyone ends up in the same rematch")
+	found, err := GetGame(g.ID)
+	assert.NoError(t, err)
+	assert.True(t, found.Finished, "the old game is still there")
+}
@@ -0,0 +1,124 @@
+package game
+
+// RenamePlayer changes oldName's name to newName, keeping their hand and
+// score. Every round's plays, votes and winners are rewritten so history
+// stays with them. newName is normalized and checked as in AddPlayer, and
+// can't be another player's or a spectator's.
+func (g *Game) RenamePlayer(oldName, newName string) error {
+	newName = NormalizePlayerName(newName)
+	if err := ValidatePlayerName(newName); err != nil {
+		return err
+	}
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	index := -1
+	for i, player := range g.Players {
+		if player.Name == oldName {
+			index = i
+		}
+	}
+	if index < 0 {
+		return ErrPlayerNotFound
+	}
+	if g.nameTaken(newName) {
+		return ErrNameTaken
+	}
+	g.Players[index].Name = newName
+	g.revokeTokens(oldName)
+	if g.Host == oldName {
+		g.Host = newName
+	}
+	if g.PausedBy == oldName {
+		g.PausedBy = newName
+	}
+	for i := range g.Rounds {
+		g.Rounds[i].rename(oldName, newName)
+	}
+	renameIn(g.Unready, oldName, newName)
+	for i := range g.Messages {
+		if g.Messages[i].Author == oldName {
+			g.Messages[i].Author = newName
+		}
+	}
+	renameIn(g.FinalWinners, oldName, newName)
+	renameIn(g.SuddenDeath, oldName, newName)
+	if g.judged[oldName] {
+		delete(g.judged, oldName)
+		g.judged[newName] = true
+	}
+	if actions, ok := g.actions[oldName]; ok {
+		delete(g.actions, oldName)
+		g.actions[newName] = actions
+	}
+	for _, timing := range g.timings {
+		if t, ok := timing.Plays[oldName]; ok {
+			delete(timing.Plays, oldName)
+			timing.Plays[newName] = t
+		}
+		if t, ok := timing.Votes[oldName]; ok {
+			delete(timing.Votes, oldName)
+			timing.Votes[newName] = t
+		}
+	}
+	return nil
+}
+
+// rename moves everything the round has under oldName to newName.
+func (round *Round) rename(oldName, newName string) {
+	if card, ok := round.Plays[oldName]; ok {
+		delete(round.Plays, oldName)
+		round.Plays[newName] = card
+	}
+	if card, ok := round.Votes[oldName]; ok {
+		delete(round.Votes, oldName)
+		round.Votes[newName] = card
+	}
+	if cards, ok := round.Rankings[oldName]; ok {
+		delete(round.Rankings, oldName)
+		round.Rankings[newName] = cards
+	}
+	if round.AutoPlayed[oldName] {
+		delete(round.AutoPlayed, oldName)
+		round.AutoPlayed[newName] = true
+	}
+	if round.AutoVoted[oldName] {
+		delete(round.AutoVoted, oldName)
+		round.AutoVoted[newName] = true
+	}
+	if round.WriteIns[oldName] {
+		delete(round.WriteIns, oldName)
+		round.WriteIns[newName] = true
+	}
+	if pair, ok := round.Pairs[oldName]; ok {
+		delete(round.Pairs, oldName)
+		round.Pairs[newName] = pair
+	}
+	if round.Judge == oldName {
+		round.Judge = newName
+	}
+	if round.Winner == oldName {
+		round.Winner = newName
+	}
+	renameIn(round.Winners, oldName, newName)
+	for i := range round.Results {
+		if round.Results[i].Player == oldName {
+			round.Results[i].Player = newName
+		}
+	}
+	renameIn(round.SkipRequests, oldName, newName)
+	for _, reactions := range round.Reactions {
+		if emoji, ok := reactions[oldName]; ok {
+			delete(reactions, oldName)
+			reactions[newName] = emoji
+		}
+	}
+}
+
+// renameIn replaces oldName in names with newName.
+func renameIn(names []string, oldName, newName string) {
+	for i, name := range names {
+		if name == oldName {
+			names[i] = newName
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenamePlayer(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "asdf", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+		},
+		Spectators:      []string{"dan"},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("asdf", "b1"))
+	assert.NoError(t, g.Play("carl", "c1"))
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("asdf", "c1"))
+	assert.NoError(t, g.Vote("carl", "b1"))
+	assert.NoError(t, g.Play("asdf", "b2"))
+
+	assert.Equal(t, ErrPlayerNotFound, g.RenamePlayer("bob", "robert"))
+	assert.Equal(t, ErrNameTaken, g.RenamePlayer("asdf", "carl"))
+	assert.Equal(t, ErrNameTaken, g.RenamePlayer("asdf", "dan"), "spectators' names are taken too")
+	assert.NoError(t, g.RenamePlayer("asdf", "bob"))
+	assert.Equal(t, "bob", g.Players[1].Name)
+	assert.Equal(t, 1, g.Players[1].Score)
+	assert.Equal(t, []Card{"b3", "b4", "b5", "b6", "8"}, g.Players[1].Punchlines, "kept their hand")
+
+	settled := g.Rounds[1]
+	assert.Equal(t, map[string]Card{"al": "a1", "bob": "b1", "carl": "c1"}, settled.Plays)
+	assert.Equal(t, map[string]Card{"al": "b1", "bob": "c1", "carl": "b1"}, settled.Votes)
+	assert.Equal(t, "bob", settled.Winner)
+	assert.Equal(t, []string{"bob"}, settled.Winners)
+	assert.Equal(t, map[string]Card{"bob": "b2"}, g.Rounds[0].Plays)
+
+	// and carries on under the new name
+	assert.NoError(t, g.Play("al", "a2"))
+	assert.NoError(t, g.Play("carl", "c2"))
+	assert.NoError(t, g.Vote("bob", "c2"))
+	assert.Equal(t, map[string]Card{"bob": "c2"}, g.Rounds[0].Votes)
+}
@@ -0,0 +1,102 @@
+package game
+
+import "errors"
+
+var (
+	ErrAlreadyPlayed = errors.New("you've already played a different card this round, retract it first")
+	ErrNotPlayed     = errors.New("you haven't played this round")
+)
+
+// repeatedPlay reports whether playerName has already played this round.
+// Clients retry, so playing the same card again is accepted without
+// changing anything; a different card is ErrAlreadyPlayed, since changing
+// a play means taking it back with RetractPlay. In pair-play games that's
+// once all their cards are in; until then a different card is their next.
+func (g *Game) repeatedPlay(playerName string, card Card) (bool, error) {
+	round := g.Rounds[g.RoundsRemaining-1]
+	if pair, ok := round.Pairs[playerName]; ok {
+		if containsCard(pair, card) {
+			return true, nil
+		}
+		if g.playsLeft(round, playerName) > 0 {
+			return false, nil
+		}
+		return true, ErrAlreadyPlayed
+	}
+	played, ok := round.Plays[playerName]
+	if !ok {
+		return false, nil
+	}
+	if played != card {
+		return true, ErrAlreadyPlayed
+	}
+	return true, nil
+}
+
+// repeatedVote reports whether playerName has already cast exactly this
+// vote, so a retry changes nothing. In ranked games the whole ranking must
+// match. A different vote replaces theirs, see Vote.
+func (g *Game) repeatedVote(playerName string, cards []Card) bool {
+	round := g.Rounds[g.RoundsRemaining-1]
+	vote, ok := round.Votes[playerName]
+	if !ok {
+		return false
+	}
+	cast := []Card{vote}
+	if g.multipleVotes() {
+		cast = round.Rankings[playerName]
+	}
+	if len(cards) != len(cast) {
+		return false
+	}
+	for i := range cards {
+		if cards[i] != cast[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// RetractPlay takes back playerName's play this round and returns the card
+// to their hand, so they can play another. In pair-play games all their
+// cards come back, whether or not they'd played one for every setup. Once
+// everyone has played the vote starts and plays can't be taken back.
+func (g *Game) RetractPlay(playerName string) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if g.isSpectator(playerName) {
+		return ErrSpectator
+	}
+	if g.over() {
+		return ErrGameFinished
+	}
+	if g.Paused {
+		return ErrGamePaused
+	}
+	if g.CurrentAction != PLAY {
+		return ErrWrongPhase
+	}
+	index := -1
+	for i := range g.Players {
+		if g.Players[i].Name == playerName {
+			index = i
+		}
+	}
+	if index < 0 {
+		return ErrPlayerNotFound
+	}
+	round := g.Rounds[g.RoundsRemaining-1]
+	card, ok := round.Plays[playerName]
+	if _, pairing := round.Pairs[playerName]; !ok && !pairing {
+		return ErrNotPlayed
+	}
+	err := g.allowAction(playerName)
+	if err != nil {
+		return err
+	}
+	delete(round.Plays, playerName)
+	delete(round.AutoPlayed, playerName)
+	g.returnPlay(&round, playerName, card)
+	g.Rounds[g.RoundsRemaining-1] = round
+	return nil
+}
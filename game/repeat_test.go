@@ -0,0 +1,107 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepeatedSubmissions(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("al", "a1"), "retry")
+	assert.Equal(t, ErrAlreadyPlayed, g.Play("al", "a2"))
+	assert.Len(t, g.Players[0].Punchlines, defaultHandSize-1)
+	assert.Contains(t, g.Players[0].Punchlines, Card("a2"))
+	assert.NotContains(t, g.Players[0].Punchlines, Card("a1"))
+	assert.Equal(t, map[string]Card{"al": "a1"}, g.Rounds[1].Plays)
+	assert.Equal(t, PLAY, g.CurrentAction)
+
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Play("carl", "c1"))
+	assert.Equal(t, VOTE, g.CurrentAction)
+	assert.NoError(t, g.Play("carl", "c1"), "retry after voting started")
+
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("al", "b1"), "retry")
+	assert.NoError(t, g.Vote("al", "c1"), "changed")
+	assert.NoError(t, g.Vote("bob", "c1"))
+	assert.Equal(t, map[string]Card{"al": "c1", "bob": "c1"}, g.Rounds[1].Votes)
+	assert.Equal(t, VOTE, g.CurrentAction)
+	assert.NoError(t, g.Vote("bob", "a1"), "changed")
+	assert.Equal(t, VOTE, g.CurrentAction, "changing a vote doesn't count it twice")
+	assert.NoError(t, g.Vote("carl", "a1"))
+	assert.Equal(t, PLAY, g.CurrentAction)
+	assert.Equal(t, []string{"al"}, g.Rounds[1].Winners)
+	assert.Equal(t, ErrWrongPhase, g.Vote("carl", "b1"), "too late to change")
+}
+
+func TestRetractPlay(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3"}},
+		},
+		Spectators:      []string{"sam"},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+		HandSize:        3,
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	assert.Equal(t, ErrNotPlayed, g.RetractPlay("al"))
+	assert.Equal(t, ErrSpectator, g.RetractPlay("sam"))
+	assert.Equal(t, ErrPlayerNotFound, g.RetractPlay("carl"))
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.Equal(t, ErrAlreadyPlayed, g.Play("al", "a2"))
+	assert.NoError(t, g.RetractPlay("al"))
+	assert.Empty(t, g.Rounds[1].Plays)
+	assert.ElementsMatch(t, []Card{"a1", "a2", "a3"}, g.Players[0].Punchlines)
+	assert.Equal(t, ErrNotPlayed, g.RetractPlay("al"))
+
+	assert.NoError(t, g.Play("al", "a2"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.Equal(t, VOTE, g.CurrentAction)
+	assert.Equal(t, ErrWrongPhase, g.RetractPlay("al"))
+	assert.ElementsMatch(t, []Card{"a1", "a3"}, g.Players[0].Punchlines)
+
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("bob", "a2"))
+	for _, player := range g.Players {
+		assert.Len(t, player.Punchlines, 3, "topped up once, not twice")
+	}
+}
+
+func TestRepeatedRankedVote(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+		Rounds:          make([]Round, 1),
+		RoundsRemaining: 1,
+		CurrentAction:   PLAY,
+	}
+	WithRankedVoting(2)(g)
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Play("carl", "c1"))
+	assert.NoError(t, g.Vote("al", "b1", "c1"))
+	assert.NoError(t, g.Vote("al", "b1", "c1"), "retry")
+	assert.NoError(t, g.Vote("al", "c1", "b1"), "changed")
+	assert.Equal(t, []Card{"c1", "b1"}, g.Rounds[0].Rankings["al"])
+	assert.Equal(t, Card("c1"), g.Rounds[0].Votes["al"])
+	assert.Equal(t, VOTE, g.CurrentAction)
+}
@@ -0,0 +1,96 @@
+package game
+
+import (
+	"context"
+	"log"
+
+	"github.com/stinkyfingers/differencebetween/api/metrics"
+)
+
+// replenishFetchTimeout bounds a background fetch for more punchlines.
+const replenishFetchTimeout = deckFetchTimeout
+
+// WithReplenish makes the game fetch more punchlines from its card source
+// when the deck runs low, instead of running out partway through.
+func WithReplenish() Option {
+	return func(g *Game) {
+		g.Replenish = true
+	}
+}
+
+// replenisher tracks a game's punchline top-ups.
+type replenisher struct {
+	running bool
+	seen    map[Card]bool // every punchline the game has had
+}
+
+// replenishState returns the game's replenisher, creating it on first use.
+// That happens in NewGame, before the game is shared.
+func (g *Game) replenishState() *replenisher {
+	if g.replenisher == nil {
+		g.replenisher = &replenisher{seen: make(map[Card]bool)}
+	}
+	return g.replenisher
+}
+
+// lowOnPunchlines reports whether the deck can't refill every hand once more.
+func (g *Game) lowOnPunchlines() bool {
+	return len(g.Punchlines) < g.handSize()*len(g.Players)
+}
+
+// markSeen records cards as having been in the game.
+func (g *Game) markSeen(cards []Card) {
+	seen := g.replenishState().seen
+	for _, card := range cards {
+		seen[card] = true
+	}
+}
+
+// maybeReplenish starts a background fetch for more punchlines if the game
+// asked for them, is running low and isn't already fetching.
+func (g *Game) maybeReplenish() {
+	if !g.Replenish || g.replenisher.running || !g.lowOnPunchlines() {
+		return
+	}
+	g.replenisher.running = true
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), replenishFetchTimeout)
+		defer cancel()
+		g.replenish(ctx)
+	}()
+}
+
+// replenish adds the source's punchlines that the game hasn't had yet to the
+// deck, shuffled. On failure the deck is left as is, and dealing fails with
+// ErrTooFewPunchlines once it runs out as it would without replenishing.
+// The fetch is made without the game's lock, so play carries on meanwhile.
+func (g *Game) replenish(ctx context.Context) {
+	g.mutex().Lock()
+	source, cleanliness := g.source, g.Cleanliness
+	g.mutex().Unlock()
+	rated, err := getRatedCards(ctx, source, PunchlineDeck, cleanliness)
+
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	r := g.replenishState()
+	r.running = false
+	if err != nil {
+		metrics.CountError("replenish")
+		log.Printf("game %d: replenishing punchlines: %v", g.ID, err)
+		return
+	}
+	cards := g.rate(rated)
+	var fresh []Card
+	for _, card := range cards {
+		if !r.seen[card] {
+			fresh = append(fresh, card)
+		}
+	}
+	g.markSeen(fresh)
+	g.random().Shuffle(len(fresh), func(i, j int) {
+		fresh[i], fresh[j] = fresh[j], fresh[i]
+	})
+	// dealing takes from the end, so the cards already in the deck go first
+	g.Punchlines = append(fresh, g.Punchlines...)
+	log.Printf("game %d: replenished %d punchlines, deck now %d", g.ID, len(fresh), len(g.Punchlines))
+}
@@ -0,0 +1,89 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type staticSource []RatedCard
+
+func (s *staticSource) Cards(ctx context.Context, deck Deck) ([]RatedCard, error) {
+	return *s, nil
+}
+
+func TestReplenish(t *testing.T) {
+	source := &staticSource{}
+	for _, text := range []Card{"1", "2", "3", "4", "5", "6", "7", "8"} {
+		*source = append(*source, RatedCard{Text: text, Rating: "G"})
+	}
+	g := &Game{
+		Players:     []Player{{Name: "al", Punchlines: []Card{"1", "2", "3", "4", "5"}}},
+		Punchlines:  []Card{"6"},
+		Cleanliness: "G",
+		Replenish:   true,
+		source:      source,
+	}
+	g.markSeen([]Card{"1", "2", "3", "4", "5", "6"})
+
+	g.mutex().Lock()
+	g.deal(Round{Plays: map[string]Card{"al": "0"}})
+	g.mutex().Unlock()
+	assert.Len(t, g.Players[0].Punchlines, defaultHandSize)
+	assert.Eventually(t, func() bool {
+		g.mutex().Lock()
+		defer g.mutex().Unlock()
+		return len(g.Punchlines) == 2
+	}, time.Second, time.Millisecond)
+	assert.ElementsMatch(t, []Card{"7", "8"}, g.Punchlines)
+
+	// nothing new left in the source
+	g.replenish(context.Background())
+	assert.Len(t, g.Punchlines, 2)
+}
+
+func TestReplenishOnlyWhenAsked(t *testing.T) {
+	g := &Game{
+		Players:    []Player{{Name: "al"}},
+		Punchlines: []Card{"1", "2", "3", "4", "5", "6"},
+		source:     &staticSource{{Text: "7", Rating: "G"}},
+	}
+	g.deal(Round{Plays: map[string]Card{"al": "0"}})
+	assert.False(t, g.replenishState().running)
+}
+
+// blockingSource serves its cards once release is closed.
+type blockingSource struct {
+	staticSource
+	release chan struct{}
+}
+
+func (s *blockingSource) Cards(ctx context.Context, deck Deck) ([]RatedCard, error) {
+	<-s.release
+	return s.staticSource, nil
+}
+
+func TestReplenishFetchesUnlocked(t *testing.T) {
+	source := &blockingSource{staticSource: staticSource{{Text: "7", Rating: "G"}}, release: make(chan struct{})}
+	g := &Game{
+		Players:     []Player{{Name: "al", Punchlines: []Card{"1", "2", "3", "4", "5"}}},
+		Punchlines:  []Card{"6"},
+		Cleanliness: "G",
+		Replenish:   true,
+		source:      source,
+	}
+	g.markSeen([]Card{"1", "2", "3", "4", "5", "6"})
+	g.mutex().Lock()
+	g.deal(Round{Plays: map[string]Card{"al": "0"}})
+	g.mutex().Unlock()
+
+	// the game can be played and viewed while the fetch is outstanding
+	view := g.ViewFor("al", HandDealt)
+	assert.Equal(t, 0, view.DeckSize)
+	close(source.release)
+	assert.Eventually(t, func() bool {
+		return g.ViewFor("al", HandDealt).DeckSize == 1
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,62 @@
+package game
+
+import "sort"
+
+// CardResult is how one play did in a settled round, see Round.Results.
+type CardResult struct {
+	Card     Card    `json:"card"`
+	Pair     []Card  `json:"pair,omitempty"` // every card Card stands for, in pair-play rounds
+	Player   string  `json:"player"`
+	Votes    int     `json:"votes"`              // first choices in ranked rounds
+	Points   int     `json:"points,omitempty"`   // in ranked and multi rounds, see Tallies
+	Audience float64 `json:"audience,omitempty"` // weighted audience votes, see WithAudienceVoting
+	Won      bool    `json:"won"`
+}
+
+// score is what the result was ranked on: points in ranked and multi
+// rounds, otherwise votes, plus the audience's.
+func (result CardResult) score(tallied bool) float64 {
+	if tallied {
+		return float64(result.Points) + result.Audience
+	}
+	return float64(result.Votes) + result.Audience
+}
+
+// roundResults lists every play in round with how it did, the best first,
+// as roundWinners ranks them. Plays that did as well as each other are
+// ordered by first choice votes, then by player, so the order is the same
+// every time.
+func roundResults(round Round) []CardResult {
+	if len(round.Plays) == 0 {
+		return nil
+	}
+	votes := voteCounts(round)
+	winners := map[string]bool{round.Winner: round.Winner != ""}
+	for _, winner := range round.Winners {
+		winners[winner] = true
+	}
+	results := make([]CardResult, 0, len(round.Plays))
+	for player, card := range round.Plays {
+		results = append(results, CardResult{
+			Card:     card,
+			Pair:     round.Pairs[player],
+			Player:   player,
+			Votes:    votes[card],
+			Points:   round.Tallies[card],
+			Audience: round.AudienceTally[card],
+			Won:      winners[player],
+		})
+	}
+	tallied := round.Tallies != nil
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if scoreA, scoreB := a.score(tallied), b.score(tallied); scoreA-scoreB > tallyTolerance || scoreB-scoreA > tallyTolerance {
+			return scoreA > scoreB
+		}
+		if a.Votes != b.Votes {
+			return a.Votes > b.Votes
+		}
+		return a.Player < b.Player
+	})
+	return results
+}
@@ -0,0 +1,50 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundResults(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "cy", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+			{Name: "dee", Punchlines: []Card{"d1", "d2", "d3", "d4", "d5", "d6"}},
+		},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	for _, name := range []string{"al", "bob", "cy", "dee"} {
+		assert.NoError(t, g.Play(name, Card(name[:1]+"1")))
+	}
+	assert.NoError(t, g.Vote("al", "c1"))
+	assert.NoError(t, g.Vote("bob", "a1"))
+	assert.NoError(t, g.Vote("cy", "a1"))
+	assert.NoError(t, g.Vote("dee", "c1"))
+	assert.Equal(t, []CardResult{
+		{Card: "a1", Player: "al", Votes: 2, Won: true},
+		{Card: "c1", Player: "cy", Votes: 2, Won: true},
+		{Card: "b1", Player: "bob"},
+		{Card: "d1", Player: "dee"},
+	}, g.Rounds[1].Results, "ties by player")
+
+	// ranked, points come before first choices
+	g.VotingMode, g.RankCount = VotingRanked, 2
+	for _, name := range []string{"al", "bob", "cy", "dee"} {
+		assert.NoError(t, g.Play(name, Card(name[:1]+"2")))
+	}
+	assert.NoError(t, g.Vote("al", "b2", "c2"))
+	assert.NoError(t, g.Vote("bob", "d2", "c2"))
+	assert.NoError(t, g.Vote("cy", "b2", "d2"))
+	assert.NoError(t, g.Vote("dee", "c2", "b2"))
+	assert.Equal(t, []CardResult{
+		{Card: "b2", Player: "bob", Votes: 2, Points: 5, Won: true},
+		{Card: "c2", Player: "cy", Votes: 1, Points: 4},
+		{Card: "d2", Player: "dee", Votes: 1, Points: 3},
+		{Card: "a2", Player: "al"},
+	}, g.Rounds[0].Results)
+}
@@ -0,0 +1,88 @@
+package game
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// S3CardSource reads decks from objects in an S3 bucket. Decks are cached
+// by ETag, so an unchanged deck costs a single HEAD request.
+type S3CardSource struct {
+	Client        s3iface.S3API
+	Bucket        string
+	SetupsKey     string
+	PunchlinesKey string
+
+	cache deckCache
+}
+
+func (s *S3CardSource) Cards(ctx context.Context, deck Deck) ([]RatedCard, error) {
+	key, err := s.key(deck)
+	if err != nil {
+		return nil, err
+	}
+	head, err := s.Client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	etag := aws.StringValue(head.ETag)
+	if cached, ok := s.cache.get(key); ok && etag != "" && cached.version == etag {
+		return cached.cards, nil
+	}
+	resp, err := s.Client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	cards, err := parseDeck(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	// the ETag of what was downloaded, in case it changed since the HEAD
+	if etag := aws.StringValue(resp.ETag); etag != "" {
+		s.cache.put(key, etag, cards)
+	}
+	return cards, nil
+}
+
+func (s *S3CardSource) key(deck Deck) (string, error) {
+	switch deck {
+	case SetupDeck:
+		return s.SetupsKey, nil
+	case PunchlineDeck:
+		return s.PunchlinesKey, nil
+	}
+	return "", ErrUnknownDeck
+}
+
+// S3Client is the client the card bucket is read with, for anything else
+// kept in S3.
+func S3Client() s3iface.S3API {
+	return s3Client
+}
+
+// PutDeck writes cards as a CSV deck to key in the card bucket.
+func PutDeck(ctx context.Context, key string, cards []RatedCard) error {
+	var buf bytes.Buffer
+	err := WriteCSVDeck(&buf, cards)
+	if err != nil {
+		return err
+	}
+	_, err = s3Client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(differenceBetweenCardsBucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(buf.Bytes()),
+		ContentType: aws.String("text/csv"),
+	})
+	return err
+}
@@ -0,0 +1,48 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stinkyfingers/differencebetween/api/testingsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestS3CardSource(t *testing.T) {
+	object := func(etag, body string) *s3.GetObjectOutput {
+		return &s3.GetObjectOutput{
+			ETag: aws.String(etag),
+			Body: ioutil.NopCloser(strings.NewReader(body)),
+		}
+	}
+	client := &testingsupport.S3{GetObjectOutput: object(`"1"`, "test,R\ntest2,PG")}
+	source := &S3CardSource{Client: client, Bucket: "decks", SetupsKey: "setups.csv", PunchlinesKey: "punchlines.csv"}
+
+	setups, err := getSetups(context.Background(), source, "PG")
+	assert.NoError(t, err)
+	assert.Equal(t, []Card{"test2"}, setups)
+	assert.EqualValues(t, 1, client.Gets)
+
+	// unchanged ETag is served from cache
+	setups, err = getSetups(context.Background(), source, "R")
+	assert.NoError(t, err)
+	assert.Equal(t, []Card{"test", "test2"}, setups)
+	assert.EqualValues(t, 1, client.Gets)
+	assert.EqualValues(t, 2, client.Heads)
+
+	// a new ETag is downloaded again
+	client.GetObjectOutput = object(`"2"`, "test3,R")
+	setups, err = getSetups(context.Background(), source, "R")
+	assert.NoError(t, err)
+	assert.Equal(t, []Card{"test3"}, setups)
+	assert.EqualValues(t, 2, client.Gets)
+
+	client.Err = errors.New("oh no")
+	_, err = getSetups(context.Background(), source, "R")
+	assert.EqualError(t, err, "oh no")
+}
@@ -0,0 +1,89 @@
+package game
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// PlayerScore is a player's line on the Scoreboard.
+type PlayerScore struct {
+	Name      string `json:"name"`
+	Score     int    `json:"score"`     // rounds won, including ties
+	RoundsWon int    `json:"roundsWon"` // outright, for breaking ties
+	Votes     int    `json:"votes"`     // received, first choices in ranked rounds
+	Left      bool   `json:"left,omitempty"`
+}
+
+// Scoreboard ranks everyone who's played in the game by score, then rounds
+// won outright, then votes received, counting only settled rounds. Players
+// who've left are still listed, marked Left.
+func (g *Game) Scoreboard() []PlayerScore {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	return g.scoreboard()
+}
+
+func (g *Game) scoreboard() []PlayerScore {
+	scores := make(map[string]*PlayerScore)
+	for _, player := range g.Players {
+		scores[player.Name] = &PlayerScore{Name: player.Name, Score: player.Score}
+	}
+	for _, round := range g.Rounds {
+		if !round.resolved {
+			continue
+		}
+		for name, card := range round.Plays {
+			score, ok := scores[name]
+			if !ok {
+				score = &PlayerScore{Name: name, Left: true}
+				scores[name] = score
+			}
+			score.Votes += round.VoteCounts[card]
+		}
+		for _, name := range round.Winners {
+			score, ok := scores[name]
+			if !ok {
+				continue
+			}
+			if score.Left {
+				score.Score++
+			}
+			if name == round.Winner {
+				score.RoundsWon++
+			}
+		}
+	}
+	board := make([]PlayerScore, 0, len(scores))
+	for _, score := range scores {
+		board = append(board, *score)
+	}
+	sort.Slice(board, func(i, j int) bool {
+		a, b := board[i], board[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.RoundsWon != b.RoundsWon {
+			return a.RoundsWon > b.RoundsWon
+		}
+		if a.Votes != b.Votes {
+			return a.Votes > b.Votes
+		}
+		return a.Name < b.Name
+	})
+	return board
+}
+
+// MarshalJSON adds the Scoreboard, and the team scoreboard in games played
+// in teams, so clients don't each work scores out from the rounds. It has
+// every hand and the deck, so only admin endpoints send it; players and
+// spectators get ViewFor.
+func (g *Game) MarshalJSON() ([]byte, error) {
+	type plain Game
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	return json.Marshal(struct {
+		plain
+		Scoreboard     []PlayerScore `json:"scoreboard"`
+		TeamScoreboard []TeamScore   `json:"teamScoreboard,omitempty"`
+	}{plain(*g), g.scoreboard(), g.teamScoreboard()})
+}
@@ -0,0 +1,61 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreboard(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+		Rounds:          make([]Round, 3),
+		RoundsRemaining: 3,
+		CurrentAction:   PLAY,
+	}
+	assert.Equal(t, []PlayerScore{{Name: "al"}, {Name: "bob"}, {Name: "carl"}}, g.Scoreboard())
+
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Play("carl", "c1"))
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("bob", "a1"))
+	assert.NoError(t, g.Vote("carl", "a1"))
+	assert.Equal(t, []PlayerScore{
+		{Name: "al", Score: 1, RoundsWon: 1, Votes: 2},
+		{Name: "bob", Score: 0, Votes: 1},
+		{Name: "carl"},
+	}, g.Scoreboard())
+
+	// bob wins too, then leaves while the next round is under way, and
+	// has more votes than al to break their tie
+	assert.NoError(t, g.Play("al", "a2"))
+	assert.NoError(t, g.Play("bob", "b2"))
+	assert.NoError(t, g.Play("carl", "c2"))
+	assert.NoError(t, g.Vote("al", "b2"))
+	assert.NoError(t, g.Vote("bob", "c2"))
+	assert.NoError(t, g.Vote("carl", "b2"))
+	assert.NoError(t, g.Play("bob", "b3"))
+	assert.NoError(t, g.RemovePlayer("bob"))
+	board := []PlayerScore{
+		{Name: "bob", Score: 1, RoundsWon: 1, Votes: 3, Left: true},
+		{Name: "al", Score: 1, RoundsWon: 1, Votes: 2},
+		{Name: "carl", Votes: 1},
+	}
+	assert.Equal(t, board, g.Scoreboard())
+
+	// and it's part of the game's JSON
+	j, err := json.Marshal(g)
+	assert.NoError(t, err)
+	var state struct {
+		Scoreboard []PlayerScore `json:"scoreboard"`
+	}
+	assert.NoError(t, json.Unmarshal(j, &state))
+	assert.Equal(t, board, state.Scoreboard)
+}
@@ -0,0 +1,130 @@
+package game
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+)
+
+const (
+	DefaultSearchLimit = 50
+	MaxSearchLimit     = 100
+)
+
+var (
+	ErrDecksNotLoaded = errors.New("no decks have been loaded yet")
+	ErrUnknownRating  = errors.New("unknown rating")
+)
+
+// loadedDecks keeps the server-wide decks as last fetched for a game, so
+// they can be searched without fetching them again.
+var loadedDecks struct {
+	mu    sync.Mutex
+	decks map[Deck][]RatedCard
+}
+
+func recordLoadedDeck(deck Deck, cards []RatedCard) {
+	loadedDecks.mu.Lock()
+	defer loadedDecks.mu.Unlock()
+	if loadedDecks.decks == nil {
+		loadedDecks.decks = make(map[Deck][]RatedCard)
+	}
+	loadedDecks.decks[deck] = cards
+}
+
+// CardSearch finds cards in the loaded decks whose text contains Query,
+// ignoring case and normalizing both like NormalizeCard.
+type CardSearch struct {
+	Query  string
+	Deck   Deck   // "" for both
+	Rating string // the cleanliness cards must fit, as for a game; "" for any
+	Offset int
+	Limit  int // up to MaxSearchLimit, 0 for DefaultSearchLimit
+
+	// Admin adds each card's problems, as found by LintDeck on its own.
+	Admin bool
+}
+
+type CardHit struct {
+	ID       string   `json:"id"` // stable across reloads while the text is unchanged
+	Deck     Deck     `json:"deck"`
+	Text     Card     `json:"text"`
+	Rating   string   `json:"rating"`
+	Problems []string `json:"problems,omitempty"` // kinds, admin only
+}
+
+type CardSearchResult struct {
+	Total  int       `json:"total"`
+	Offset int       `json:"offset"`
+	Limit  int       `json:"limit"`
+	Cards  []CardHit `json:"cards"`
+}
+
+// CardID identifies a card by its deck and text.
+func CardID(deck Deck, text Card) string {
+	sum := sha256.Sum256([]byte(string(deck) + "\x00" + string(text)))
+	return hex.EncodeToString(sum[:6])
+}
+
+// SearchCards runs s over the loaded decks. It never fetches a deck.
+func SearchCards(s CardSearch) (*CardSearchResult, error) {
+	if s.Deck != "" && s.Deck != SetupDeck && s.Deck != PunchlineDeck {
+		return nil, ErrUnknownDeck
+	}
+	if s.Rating != "" {
+		if _, ok := ratingRanks[s.Rating]; !ok {
+			return nil, ErrUnknownRating
+		}
+	}
+	if s.Limit <= 0 {
+		s.Limit = DefaultSearchLimit
+	}
+	if s.Limit > MaxSearchLimit {
+		s.Limit = MaxSearchLimit
+	}
+	if s.Offset < 0 {
+		s.Offset = 0
+	}
+
+	loadedDecks.mu.Lock()
+	decks := make(map[Deck][]RatedCard)
+	for deck, cards := range loadedDecks.decks {
+		decks[deck] = cards
+	}
+	loadedDecks.mu.Unlock()
+	if len(decks) == 0 {
+		return nil, ErrDecksNotLoaded
+	}
+
+	query := strings.ToLower(string(NormalizeCard(s.Query)))
+	result := &CardSearchResult{Offset: s.Offset, Limit: s.Limit, Cards: []CardHit{}}
+	for _, deck := range []Deck{SetupDeck, PunchlineDeck} {
+		if s.Deck != "" && deck != s.Deck {
+			continue
+		}
+		for _, card := range decks[deck] {
+			if !strings.Contains(strings.ToLower(string(card.Text)), query) {
+				continue
+			}
+			if s.Rating != "" {
+				if clean, err := isCleanEnough(card.Rating, s.Rating); err != nil || !clean {
+					continue
+				}
+			}
+			result.Total++
+			if result.Total <= s.Offset || len(result.Cards) >= s.Limit {
+				continue
+			}
+			hit := CardHit{ID: CardID(deck, card.Text), Deck: deck, Text: card.Text, Rating: card.Rating}
+			if s.Admin {
+				for _, problem := range cardProblems(card) {
+					hit.Problems = append(hit.Problems, problem.Kind)
+				}
+			}
+			result.Cards = append(result.Cards, hit)
+		}
+	}
+	return result, nil
+}
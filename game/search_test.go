@@ -0,0 +1,74 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchCards(t *testing.T) {
+	saved := loadedDecks.decks
+	defer func() { loadedDecks.decks = saved }()
+	loadedDecks.decks = nil
+
+	_, err := SearchCards(CardSearch{Query: "taxes"})
+	assert.Equal(t, ErrDecksNotLoaded, err)
+
+	savedSource := cardSource
+	defer func() { cardSource = savedSource }()
+	source := &staticSource{
+		{Text: "Doing my taxes", Rating: "G"},
+		{Text: "TAXES, but sexier", Rating: "R"},
+		{Text: "Tax\u200bes", Rating: "G"},
+		{Text: "Death", Rating: "PG"},
+	}
+	cardSource = source
+	_, err = getCards(context.Background(), source, PunchlineDeck, "R")
+	assert.NoError(t, err)
+	_, err = getCards(context.Background(), &staticSource{{Text: "The difference between taxes and theft", Rating: "PG"}}, SetupDeck, "R")
+	assert.NoError(t, err)
+	assert.Nil(t, loadedDecks.decks[SetupDeck], "only the server-wide source is recorded")
+	recordLoadedDeck(SetupDeck, []RatedCard{{Text: "Paying taxes", Rating: "G"}, {Text: "Doing nothing", Rating: "G"}})
+
+	tests := []struct {
+		search   CardSearch
+		expected []Card
+		total    int
+	}{
+		{CardSearch{Query: "taxes"}, []Card{"Paying taxes", "Doing my taxes", "TAXES, but sexier"}, 3},
+		{CardSearch{Query: "  TAXES "}, []Card{"Paying taxes", "Doing my taxes", "TAXES, but sexier"}, 3},
+		{CardSearch{Query: "taxes", Deck: PunchlineDeck}, []Card{"Doing my taxes", "TAXES, but sexier"}, 2},
+		{CardSearch{Query: "taxes", Rating: "PG-13"}, []Card{"Paying taxes", "Doing my taxes"}, 2},
+		{CardSearch{Query: "taxes", Offset: 1, Limit: 1}, []Card{"Doing my taxes"}, 3},
+		{CardSearch{Query: "taxes", Offset: 5}, []Card{}, 3},
+		{CardSearch{Query: "doing", Deck: SetupDeck}, []Card{"Doing nothing"}, 1},
+	}
+	for _, test := range tests {
+		result, err := SearchCards(test.search)
+		assert.NoError(t, err)
+		var texts []Card
+		for _, hit := range result.Cards {
+			texts = append(texts, hit.Text)
+			assert.Nil(t, hit.Problems)
+		}
+		if texts == nil {
+			texts = []Card{}
+		}
+		assert.Equal(t, test.expected, texts, test.search.Query)
+		assert.Equal(t, test.total, result.Total, test.search.Query)
+	}
+
+	result, err := SearchCards(CardSearch{Query: "tax", Deck: PunchlineDeck, Admin: true, Limit: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, MaxSearchLimit, result.Limit)
+	assert.Len(t, result.Cards, 3)
+	assert.Equal(t, []string{ProblemCharacters}, result.Cards[2].Problems)
+	assert.Equal(t, CardID(PunchlineDeck, "Doing my taxes"), result.Cards[0].ID)
+	assert.NotEqual(t, CardID(SetupDeck, "Doing my taxes"), result.Cards[0].ID)
+
+	_, err = SearchCards(CardSearch{Query: "taxes", Rating: "NC-17"})
+	assert.Equal(t, ErrUnknownRating, err)
+	_, err = SearchCards(CardSearch{Query: "taxes", Deck: "jokers"})
+	assert.Equal(t, ErrUnknownDeck, err)
+}
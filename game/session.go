@@ -0,0 +1,112 @@
+package game
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const maxSessionLength = 64
+
+var ErrInvalidSession = errors.New("session must be up to 64 lowercase letters, digits and dashes")
+
+// gameSession remembers the setups dealt in a run of games played by the
+// same group, so the next game can deal others.
+type gameSession struct {
+	used     map[Card]bool
+	lastUsed time.Time
+}
+
+// usedAny reports whether any of setups has been used in the session.
+func (s *gameSession) usedAny(setups []Card) bool {
+	for _, setup := range setups {
+		if s.used[setup] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	sessions   = make(map[string]*gameSession)
+	sessionsMu sync.Mutex
+)
+
+// WithSession adds the game to a session: setups dealt in the session's
+// earlier games aren't dealt again until there aren't enough others. Check
+// id with ValidateSession.
+func WithSession(id string) Option {
+	return func(g *Game) {
+		g.Session = id
+	}
+}
+
+func ValidateSession(id string) error {
+	if len(id) > maxSessionLength || !leagueSlug.MatchString(id) {
+		return ErrInvalidSession
+	}
+	return nil
+}
+
+// ResetSession forgets the setups dealt in the session's games.
+func ResetSession(id string) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	delete(sessions, id)
+}
+
+// excludeSessionSetups keeps the setups dealt so far in the game's session
+// out of its rounds.
+func (g *Game) excludeSessionSetups() {
+	if g.Session == "" {
+		return
+	}
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	s, ok := sessions[g.Session]
+	if !ok {
+		return
+	}
+	if g.usedSetups == nil {
+		g.usedSetups = make(map[Card]bool, len(s.used))
+	}
+	for card := range s.used {
+		g.usedSetups[card] = true
+	}
+}
+
+// recordSession adds the game's setups to its session. If the game had to
+// deal setups the session had already seen, the deck has come round, so
+// the session starts over from this game's.
+func (g *Game) recordSession() {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	s, ok := sessions[g.Session]
+	if !ok {
+		s = &gameSession{used: make(map[Card]bool)}
+		sessions[g.Session] = s
+	}
+	for _, round := range g.Rounds {
+		if s.usedAny(round.Setup) {
+			s.used = make(map[Card]bool)
+			break
+		}
+	}
+	for _, round := range g.Rounds {
+		for _, setup := range round.Setup {
+			s.used[setup] = true
+		}
+	}
+	s.lastUsed = now()
+}
+
+// reapSessions forgets sessions with no games since cutoff.
+func reapSessions(cutoff time.Time) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	for id, s := range sessions {
+		if s.lastUsed.Before(cutoff) {
+			delete(sessions, id)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package game
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSession(t *testing.T) {
+	assert.NoError(t, ValidateSession("friday-night"))
+	assert.Equal(t, ErrInvalidSession, ValidateSession("Friday Night"))
+
+	source := &staticSource{}
+	for i := 0; i < 20; i++ {
+		*source = append(*source, RatedCard{Text: Card(strconv.Itoa(i)), Rating: "G"})
+	}
+	newGame := func(session string) *Game {
+		g, err := NewGame(context.Background(), Player{Name: "al"}, 4, "R", WithCardSource(source), WithSession(session))
+		assert.NoError(t, err)
+		deleteGame(g.ID)
+		return g
+	}
+	setups := func(g *Game) map[Card]bool {
+		dealt := make(map[Card]bool)
+		for _, round := range g.Rounds {
+			dealt[round.Setup[0]], dealt[round.Setup[1]] = true, true
+		}
+		return dealt
+	}
+	overlap := func(a, b map[Card]bool) bool {
+		for card := range a {
+			if b[card] {
+				return true
+			}
+		}
+		return false
+	}
+	defer ResetSession("test")
+
+	first, second := setups(newGame("test")), setups(newGame("test"))
+	assert.False(t, overlap(first, second), "20 setups is enough for two games of 8")
+	third := setups(newGame("test"))
+	assert.True(t, overlap(third, first) || overlap(third, second), "only 4 left, so any may come round again")
+	fourth := setups(newGame("test"))
+	assert.False(t, overlap(fourth, third), "the session started over from the third game")
+
+	ResetSession("test")
+	assert.NotContains(t, sessions, "test")
+
+	defer func() { now = time.Now }()
+	newGame("test")
+	now = func() time.Time { return time.Now().Add(time.Hour * 24) }
+	ReapExpired()
+	assert.NotContains(t, sessions, "test", "forgotten with the games")
+}
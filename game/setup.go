@@ -0,0 +1,60 @@
+package game
+
+import "errors"
+
+const (
+	defaultSetupSize = 2
+	maxSetupSize     = 3
+)
+
+var ErrInvalidSetupSize = errors.New("setup size must be 2 or 3")
+
+// WithSetupSize deals each round n setups rather than two, so 3 plays "the
+// difference between X, Y and Z". Check n with ValidateSetupSize.
+func WithSetupSize(n int) Option {
+	return func(g *Game) {
+		g.SetupSize = n
+	}
+}
+
+func ValidateSetupSize(n int) error {
+	if n < defaultSetupSize || n > maxSetupSize {
+		return ErrInvalidSetupSize
+	}
+	return nil
+}
+
+// setupSize is how many setups each of the game's rounds has. Games that
+// never set SetupSize have the default, so their JSON is as it always was.
+func (g *Game) setupSize() int {
+	if g.SetupSize == 0 {
+		return defaultSetupSize
+	}
+	return g.SetupSize
+}
+
+// drawSetup takes a round's setups from the end of the pool, or returns
+// nil if there aren't enough left.
+func (g *Game) drawSetup() []Card {
+	n := g.setupSize()
+	if len(g.setupPool) < n {
+		return nil
+	}
+	rest := len(g.setupPool) - n
+	setup := append([]Card(nil), g.setupPool[rest:]...)
+	g.setupPool = g.setupPool[:rest]
+	return setup
+}
+
+// uniqueCards returns cards without repeats, keeping the first of each.
+func uniqueCards(cards []Card) []Card {
+	seen := make(map[Card]bool, len(cards))
+	unique := make([]Card, 0, len(cards))
+	for _, card := range cards {
+		if !seen[card] {
+			seen[card] = true
+			unique = append(unique, card)
+		}
+	}
+	return unique
+}
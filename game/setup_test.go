@@ -0,0 +1,57 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupSize(t *testing.T) {
+	source := &staticSource{}
+	for i := 0; i < 12; i++ {
+		*source = append(*source, RatedCard{Text: Card(fmt.Sprintf("card %d", i)), Rating: "G"})
+	}
+	ctx := context.Background()
+	g, err := NewGame(ctx, Player{Name: "al"}, 2, "R", WithCardSource(source), WithHandSize(3))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	j, err := json.Marshal(g)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(j), "setupSize")
+	var shape struct {
+		Rounds []struct {
+			Setup []Card `json:"setup"`
+		} `json:"rounds"`
+	}
+	assert.NoError(t, json.Unmarshal(j, &shape))
+	assert.Len(t, shape.Rounds[0].Setup, 2)
+
+	// 12 setups only make 4 rounds of 3
+	_, err = NewGame(ctx, Player{Name: "al"}, 5, "R", WithCardSource(source), WithHandSize(3), WithSetupSize(3))
+	assert.Equal(t, ErrTooFewSetups, err)
+	g, err = NewGame(ctx, Player{Name: "al"}, 3, "R", WithCardSource(source), WithHandSize(3), WithSetupSize(3))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	seen := make(map[Card]bool)
+	for _, round := range g.Rounds {
+		assert.Len(t, round.Setup, 3)
+		for _, setup := range round.Setup {
+			assert.False(t, seen[setup], "setups are dealt once")
+			seen[setup] = true
+		}
+	}
+	assert.Len(t, g.setupPool, 3)
+	assert.NoError(t, g.ExtendRounds(ctx, "al", 1))
+	assert.Len(t, g.Rounds[0].Setup, 3)
+	assert.Empty(t, g.setupPool)
+
+	var b strings.Builder
+	g.Rounds[len(g.Rounds)-1].Setup = []Card{"cats", "dogs", "fish"}
+	g.Rounds[len(g.Rounds)-1].Plays = map[string]Card{"al": "a1"}
+	assert.NoError(t, g.WriteTranscript(&b, FormatText))
+	assert.Contains(t, b.String(), "Round 1: the difference between cats, dogs and fish")
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"errors"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+)
+
+var ErrNoSkipsLeft = errors.New("this game has used all its setup skips")
+
+// SkipSetup asks for the round's setups to be redrawn. Once a majority of
+// the round's players have asked, the round gets new setups from the unused
+// ones, any cards already played go back to their players' hands and the
+// play phase starts again. Each game gets the configured SetupSkips.
+func (g *Game) SkipSetup(playerName string) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if g.isSpectator(playerName) {
+		return ErrSpectator
+	}
+	if g.over() {
+		return ErrGameFinished
+	}
+	if g.CurrentAction != PLAY {
+		return ErrWrongPhase
+	}
+	if _, err := g.hand(playerName); err != nil {
+		return err
+	}
+	if err := g.checkParticipant(playerName); err != nil {
+		return err
+	}
+	if g.SkipsUsed >= config.Current().SetupSkips {
+		return ErrNoSkipsLeft
+	}
+	if len(g.setupPool) < g.setupSize() {
+		return ErrTooFewSetups
+	}
+	round := g.Rounds[g.RoundsRemaining-1]
+	for _, name := range round.SkipRequests {
+		if name == playerName {
+			return nil
+		}
+	}
+	round.SkipRequests = append(round.SkipRequests, playerName)
+	if len(round.SkipRequests)*2 > g.participants() {
+		g.redrawSetup(&round)
+	}
+	g.Rounds[g.RoundsRemaining-1] = round
+	return nil
+}
+
+// redrawSetup gives round new setups and undoes its plays.
+func (g *Game) redrawSetup(round *Round) {
+	round.Setup = g.drawSetup()
+	for name, card := range round.Plays {
+		g.returnPlay(round, name, card)
+	}
+	for name := range round.Pairs {
+		g.returnPlay(round, name, "")
+	}
+	round.Plays = nil
+	round.AutoPlayed = nil
+	round.SkipRequests = nil
+	g.SkipsUsed++
+	g.startPlaying()
+}
@@ -0,0 +1,50 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipSetup(t *testing.T) {
+	defer config.Set(config.Current())
+	cfg := *config.Current()
+	cfg.SetupSkips = 1
+	config.Set(&cfg)
+
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+		Rounds:          []Round{{Setup: []Card{"s1", "s2"}}},
+		RoundsRemaining: 1,
+		CurrentAction:   PLAY,
+		setupPool:       []Card{"s3", "s4", "s5", "s6"},
+	}
+	assert.Equal(t, ErrPlayerNotFound, g.SkipSetup("dan"))
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.SkipSetup("al"))
+	assert.NoError(t, g.SkipSetup("al"), "asking twice counts once")
+	assert.Equal(t, []string{"al"}, g.Rounds[0].SkipRequests)
+	assert.Equal(t, []Card{"s1", "s2"}, g.Rounds[0].Setup, "not a majority yet")
+
+	assert.NoError(t, g.SkipSetup("bob"))
+	round := g.Rounds[0]
+	assert.Equal(t, []Card{"s5", "s6"}, round.Setup)
+	assert.Empty(t, round.Plays)
+	assert.Empty(t, round.SkipRequests)
+	assert.Equal(t, []Card{"a2", "a3", "a4", "a5", "a6", "a1"}, g.Players[0].Punchlines, "back in al's hand")
+	assert.Equal(t, 1, g.SkipsUsed)
+	assert.Equal(t, []Card{"s3", "s4"}, g.setupPool)
+
+	assert.Equal(t, ErrNoSkipsLeft, g.SkipSetup("carl"))
+	cfg.SetupSkips = 2
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Play("carl", "c1"))
+	assert.Equal(t, ErrWrongPhase, g.SkipSetup("carl"))
+}
@@ -0,0 +1,326 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+)
+
+const snapshotVersion = 1
+
+var (
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+	ErrGameExists      = errors.New("a game with that id already exists")
+)
+
+// gameSnapshot is everything needed to bring a game back, including what
+// the game's own JSON leaves out or hides: when it was created, players'
+// token hashes, plays in a round still open and the game's bookkeeping.
+// The random source isn't kept, so a restored game shuffles differently,
+// and neither are rate limits.
+type gameSnapshot struct {
+	Version int `json:"version"`
+	plainGame
+	Created    time.Time           `json:"created"`
+	Players    []snapshotPlayer    `json:"players"`
+	Rounds     []snapshotRound     `json:"rounds"`
+	Deck       *snapshotDeck       `json:"deck,omitempty"` // a custom deck, see WithCardSource
+	SetupPool  []Card              `json:"setupPool"`
+	Discards   []Card              `json:"discards"`
+	Seen       []Card              `json:"seen"` // every punchline the game has had, see replenish
+	Ratings    map[Card]string     `json:"ratings,omitempty"`
+	UsedSetups []Card              `json:"usedSetups,omitempty"`
+	Judged     []string            `json:"judged,omitempty"`
+	ReadyCheck bool                `json:"readyCheck,omitempty"`
+	Observers  []snapshotObserver  `json:"observers,omitempty"`
+	Timings    map[int]roundTiming `json:"timings,omitempty"`
+	Webhook    string              `json:"webhook,omitempty"`
+	PINHash    string              `json:"pinHash,omitempty"`
+	TokenGens  map[string]int      `json:"tokenGenerations,omitempty"`
+	SpecTokens map[string]string   `json:"spectatorTokenHashes,omitempty"`
+}
+
+// plainGame and plainRound are Game and Round without their MarshalJSON.
+type (
+	plainGame  Game
+	plainRound Round
+)
+
+type snapshotPlayer struct {
+	Player
+	TokenHash     string `json:"tokenHash,omitempty"`
+	ReconnectHash string `json:"reconnectHash,omitempty"`
+}
+
+type snapshotRound struct {
+	plainRound
+	Resolved bool `json:"resolved"`
+}
+
+type snapshotDeck struct {
+	SetupsURL     string `json:"setupsUrl"`
+	PunchlinesURL string `json:"punchlinesUrl"`
+}
+
+type snapshotObserver struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+	Hash    string    `json:"hash"`
+}
+
+// Snapshot serializes the whole game, for RestoreGame to bring back later,
+// e.g. after a restart, or for debugging. It includes the hashes of tokens,
+// observer keys and the PIN, so it should be kept as safe as tokens are.
+func (g *Game) Snapshot() ([]byte, error) {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	s := gameSnapshot{
+		Version:    snapshotVersion,
+		plainGame:  plainGame(*g),
+		Created:    g.Created,
+		Players:    make([]snapshotPlayer, len(g.Players)),
+		Rounds:     make([]snapshotRound, len(g.Rounds)),
+		SetupPool:  g.setupPool,
+		Discards:   g.discards,
+		UsedSetups: sortedCards(g.usedSetups),
+		ReadyCheck: g.readyCheck,
+		PINHash:    g.pinHash,
+	}
+	for i, player := range g.Players {
+		s.Players[i] = snapshotPlayer{Player: player, TokenHash: player.TokenHash, ReconnectHash: player.ReconnectHash}
+	}
+	for i, round := range g.Rounds {
+		s.Rounds[i] = snapshotRound{plainRound: plainRound(round), Resolved: round.resolved}
+	}
+	if source, ok := g.source.(*HTTPCardSource); ok {
+		s.Deck = &snapshotDeck{SetupsURL: source.SetupsURL, PunchlinesURL: source.PunchlinesURL}
+	}
+	s.Seen = sortedCards(g.replenishState().seen)
+	if len(g.ratings) > 0 {
+		s.Ratings = make(map[Card]string, len(g.ratings))
+		for card, rating := range g.ratings {
+			s.Ratings[card] = rating
+		}
+	}
+	if len(g.tokenGens) > 0 {
+		s.TokenGens = make(map[string]int, len(g.tokenGens))
+		for name, generation := range g.tokenGens {
+			s.TokenGens[name] = generation
+		}
+	}
+	if len(g.specTokens) > 0 {
+		s.SpecTokens = make(map[string]string, len(g.specTokens))
+		for name, hash := range g.specTokens {
+			s.SpecTokens[name] = hash
+		}
+	}
+	for name := range g.judged {
+		s.Judged = append(s.Judged, name)
+	}
+	sort.Strings(s.Judged)
+	for _, observer := range g.observers {
+		s.Observers = append(s.Observers, snapshotObserver{ID: observer.ID, Created: observer.Created, Hash: observer.hash})
+	}
+	if len(g.timings) > 0 {
+		s.Timings = make(map[int]roundTiming, len(g.timings))
+		for index, timing := range g.timings {
+			s.Timings[index] = *timing
+		}
+	}
+	if g.webhook != nil {
+		s.Webhook = g.webhook.url
+	}
+	return json.Marshal(s)
+}
+
+// RestoreGame brings back a game from its Snapshot under the same id, and
+// returns ErrGameExists if a game in memory already has it. The join code
+// is kept unless another game has taken it since, in which case the game
+// gets a new one. A snapshot that can't be read, or describes a game that
+// couldn't have been played, returns an error wrapping ErrInvalidSnapshot.
+func RestoreGame(data []byte) (*Game, error) {
+	var s gameSnapshot
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if s.Version != snapshotVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, s.Version)
+	}
+	g := s.game()
+	err = g.checkConsistency()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+
+	gamesMu.Lock()
+	defer gamesMu.Unlock()
+	if _, err := getGame(g.ID); err != ErrGameNotFound || !gameIDs.claim(g.ID) {
+		return nil, ErrGameExists
+	}
+	if _, taken := codes[g.Code]; taken || !validCode(g.Code) {
+		err = g.takeCode()
+		if err != nil {
+			gameIDs.release(g.ID)
+			return nil, err
+		}
+	} else {
+		codes[g.Code] = g.ID
+	}
+	games[g.ID] = g
+	return g, nil
+}
+
+// game builds the game the snapshot describes.
+func (s *gameSnapshot) game() *Game {
+	g := (*Game)(&s.plainGame)
+	g.mu = nil
+	g.Created = s.Created
+	g.Players = make([]Player, len(s.Players))
+	for i, player := range s.Players {
+		g.Players[i] = player.Player
+		g.Players[i].TokenHash = player.TokenHash
+		g.Players[i].ReconnectHash = player.ReconnectHash
+	}
+	g.Rounds = make([]Round, len(s.Rounds))
+	for i, round := range s.Rounds {
+		g.Rounds[i] = Round(round.plainRound)
+		g.Rounds[i].resolved = round.Resolved
+	}
+	if g.Spectators == nil {
+		g.Spectators = []string{}
+	}
+	g.source = cardSource
+	if s.Deck != nil {
+		g.source = NewHTTPCardSource(s.Deck.SetupsURL, s.Deck.PunchlinesURL)
+	}
+	g.setupPool = s.SetupPool
+	g.discards = s.Discards
+	g.markSeen(s.Seen)
+	g.ratings = s.Ratings
+	for _, setup := range s.UsedSetups {
+		if g.usedSetups == nil {
+			g.usedSetups = make(map[Card]bool)
+		}
+		g.usedSetups[setup] = true
+	}
+	for _, name := range s.Judged {
+		if g.judged == nil {
+			g.judged = make(map[string]bool)
+		}
+		g.judged[name] = true
+	}
+	g.readyCheck = s.ReadyCheck
+	g.pinHash = s.PINHash
+	g.tokenGens = s.TokenGens
+	g.specTokens = s.SpecTokens
+	for _, observer := range s.Observers {
+		g.observers = append(g.observers, ObserverKey{ID: observer.ID, Created: observer.Created, hash: observer.Hash})
+	}
+	for index, timing := range s.Timings {
+		if g.timings == nil {
+			g.timings = make(map[int]*roundTiming)
+		}
+		timing := timing
+		g.timings[index] = &timing
+	}
+	if s.Webhook != "" {
+		g.webhook = &webhook{url: s.Webhook}
+	}
+	return g
+}
+
+// checkConsistency checks a restored game is one that could have been
+// played, so nothing that relies on its shape can panic.
+func (g *Game) checkConsistency() error {
+	if g.ID < 0 || g.ID >= config.Current().GameIDSpace {
+		return fmt.Errorf("id %d is outside the game id space", g.ID)
+	}
+	if len(g.Players) == 0 {
+		return errors.New("no players")
+	}
+	names := make(map[string]bool)
+	for _, name := range append(g.playerNames(), g.Spectators...) {
+		if ValidatePlayerName(name) != nil || NormalizePlayerName(name) != name {
+			return fmt.Errorf("invalid player name %q", name)
+		}
+		if names[name] {
+			return fmt.Errorf("player name %q is used twice", name)
+		}
+		names[name] = true
+	}
+	if _, err := g.hand(g.host()); err != nil {
+		return fmt.Errorf("host %q isn't a player", g.Host)
+	}
+	if err := ValidateHandSize(g.HandSize); err != nil {
+		return err
+	}
+	for _, player := range g.Players {
+		if len(player.Punchlines) > g.handSize() {
+			return fmt.Errorf("%s holds %d cards, more than the hand size of %d", player.Name, len(player.Punchlines), g.handSize())
+		}
+	}
+	switch g.CurrentAction {
+	case PLAY, VOTE, LOBBY, FINISHED:
+	default:
+		return fmt.Errorf("unknown current action %q", g.CurrentAction)
+	}
+	switch g.VotingMode {
+	case VotingSingle, VotingRanked, VotingJudge, VotingMulti:
+	default:
+		return fmt.Errorf("unknown voting mode %q", g.VotingMode)
+	}
+	if g.RoundsRemaining < 0 || g.RoundsRemaining > len(g.Rounds) {
+		return fmt.Errorf("%d rounds remaining of %d", g.RoundsRemaining, len(g.Rounds))
+	}
+	if g.Finished && g.RoundsRemaining != 0 {
+		return fmt.Errorf("finished with %d rounds remaining", g.RoundsRemaining)
+	}
+	if g.Paused && g.PausedAt == nil {
+		return errors.New("paused with no pausedAt")
+	}
+	for i, round := range g.Rounds {
+		number := len(g.Rounds) - i
+		if len(round.Setup) != g.setupSize() {
+			return fmt.Errorf("round %d has %d setups, not %d", number, len(round.Setup), g.setupSize())
+		}
+		switch {
+		case i >= g.RoundsRemaining && !round.resolved:
+			return fmt.Errorf("round %d was played but never settled", number)
+		case i < g.RoundsRemaining && round.resolved:
+			return fmt.Errorf("round %d is settled but still to come", number)
+		case i < g.RoundsRemaining-1 && (len(round.Plays) > 0 || len(round.Votes) > 0):
+			return fmt.Errorf("round %d has plays but hasn't started", number)
+		}
+	}
+	return nil
+}
+
+// validCode reports whether code is a join code as takeCode makes them.
+func validCode(code string) bool {
+	normalized, err := NormalizeCode(code)
+	return err == nil && normalized == code
+}
+
+// playerNames lists the players' names in order.
+func (g *Game) playerNames() []string {
+	names := make([]string, len(g.Players))
+	for i, player := range g.Players {
+		names[i] = player.Name
+	}
+	return names
+}
+
+// sortedCards lists the cards in set, sorted.
+func sortedCards(set map[Card]bool) []Card {
+	var cards []Card
+	for card := range set {
+		cards = append(cards, card)
+	}
+	sort.Slice(cards, func(i, j int) bool { return cards[i] < cards[j] })
+	return cards
+}
@@ -0,0 +1,93 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	source := &staticSource{}
+	for i := 0; i < 20; i++ {
+		*source = append(*source, RatedCard{Text: Card(strconv.Itoa(i)), Rating: "G"})
+	}
+	g, err := NewGame(context.Background(), Player{Name: "al"}, 2, "R", WithCardSource(source), WithHandSize(4))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	assert.NoError(t, g.AddPlayer(Player{Name: "bob"}, ""))
+	assert.NoError(t, g.AddPlayer(Player{Name: "carl"}, ""))
+	assert.NoError(t, g.Start("al", false))
+	token, err := g.IssueToken("bob")
+	assert.NoError(t, err)
+	assert.NoError(t, g.Play("al", g.Players[0].Punchlines[0]))
+	assert.NoError(t, g.AddPlayer(Player{Name: "dee"}, ""))
+	assert.NoError(t, g.RemovePlayer("dee"))
+
+	data, err := g.Snapshot()
+	assert.NoError(t, err)
+	_, err = RestoreGame(data)
+	assert.Equal(t, ErrGameExists, err)
+
+	deleteGame(g.ID)
+	restored, err := RestoreGame(data)
+	assert.NoError(t, err)
+	found, err := GetGame(g.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, restored, found)
+	again, err := restored.Snapshot()
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(data), string(again))
+	assert.True(t, g.Created.Equal(restored.Created))
+	assert.Equal(t, g.Rounds[1].Plays, restored.Rounds[1].Plays, "plays are kept while hidden")
+	name, ok := restored.TokenPlayer(token)
+	assert.True(t, ok)
+	assert.Equal(t, "bob", name, "tokens still work")
+	assert.Equal(t, 1, restored.TokenGeneration("dee"), "revoked tokens stay revoked")
+
+	// and play goes on
+	assert.NoError(t, restored.Play("bob", restored.Players[1].Punchlines[0]))
+	assert.NoError(t, restored.Play("carl", restored.Players[2].Punchlines[0]))
+	assert.Equal(t, VOTE, restored.CurrentAction)
+}
+
+func TestRestoreInvalidSnapshot(t *testing.T) {
+	g := &Game{
+		ID:              1,
+		Code:            "ABCDE",
+		Players:         []Player{{Name: "al"}, {Name: "bob"}},
+		Spectators:      []string{},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+		VotingMode:      VotingSingle,
+	}
+	data, err := g.Snapshot()
+	assert.NoError(t, err)
+	valid := string(data)
+
+	for _, data := range []string{
+		"not json",
+		`{"version":1,"players":"al"}`,
+		strings.Replace(valid, `"version":1`, `"version":2`, 1),
+		strings.Replace(valid, `"roundsRemaining":2`, `"roundsRemaining":-1`, 1),
+		strings.Replace(valid, `"roundsRemaining":2`, `"roundsRemaining":3`, 1),
+		strings.Replace(valid, `"finished":false`, `"finished":true`, 1),
+		strings.Replace(valid, `"name":"bob","punchlines":null`, `"name":"bob","punchlines":["1","2","3","4","5","6","7","8"]`, 1),
+		strings.Replace(valid, `"name":"bob"`, `"name":"al"`, 1),
+		strings.Replace(valid, `"currentAction":"play"`, `"currentAction":"dance"`, 1),
+		strings.Replace(valid, `"votingMode":"single"`, `"votingMode":"loudest"`, 1),
+		strings.Replace(valid, `"players":[{"name":"al","punchlines":null`, `"players":[{"name":"","punchlines":null`, 1),
+		strings.Replace(valid, `"resolved":false`, `"resolved":true`, 1),
+		strings.Replace(valid, `"id":1,`, `"id":-1,`, 1),
+	} {
+		assert.NotEqual(t, valid, data, "the test should change something")
+		_, err := RestoreGame([]byte(data))
+		assert.True(t, errors.Is(err, ErrInvalidSnapshot), "%s: %v", data, err)
+	}
+	_, err = GetGame(1)
+	assert.Equal(t, ErrGameNotFound, err)
+}
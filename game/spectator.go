@@ -0,0 +1,71 @@
+package game
+
+import "errors"
+
+var (
+	ErrNameTaken = errors.New("player name already exists")
+	ErrSpectator = errors.New("spectators can't play or vote")
+)
+
+// AddSpectator lets name watch the game without playing. Spectators aren't
+// dealt cards and rounds don't wait for them. Names are unique across
+// players and spectators. Games with a PIN need it to watch too.
+func (g *Game) AddSpectator(name, pin string) error {
+	name = NormalizePlayerName(name)
+	if err := ValidatePlayerName(name); err != nil {
+		return err
+	}
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if err := g.checkPIN(pin); err != nil {
+		return err
+	}
+	if g.nameTaken(name) {
+		return ErrNameTaken
+	}
+	g.Spectators = append(g.Spectators, name)
+	return nil
+}
+
+// SpectatorNames returns who's watching, in the order they arrived.
+func (g *Game) SpectatorNames() []string {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	return append([]string(nil), g.Spectators...)
+}
+
+func (g *Game) isSpectator(name string) bool {
+	for _, spectator := range g.Spectators {
+		if spectator == name {
+			return true
+		}
+	}
+	return false
+}
+
+// nameTaken reports whether a player or spectator is already called name.
+func (g *Game) nameTaken(name string) bool {
+	for _, player := range g.Players {
+		if player.Name == name {
+			return true
+		}
+	}
+	return g.isSpectator(name)
+}
+
+// removeSpectator takes name off the spectators, along with their vote in
+// the current round, and reports whether they were one.
+func (g *Game) removeSpectator(name string) bool {
+	for i, spectator := range g.Spectators {
+		if spectator == name {
+			g.Spectators = append(g.Spectators[:i], g.Spectators[i+1:]...)
+			g.revokeTokens(name)
+			delete(g.specTokens, name)
+			if !g.over() {
+				delete(g.Rounds[g.RoundsRemaining-1].AudienceVotes, name)
+			}
+			return true
+		}
+	}
+	return false
+}
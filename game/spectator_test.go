@@ -0,0 +1,40 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpectators(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	assert.NoError(t, g.AddSpectator("carl", ""))
+	assert.NoError(t, g.AddSpectator("dan", ""))
+	assert.Equal(t, ErrNameTaken, g.AddSpectator("al", ""))
+	assert.Equal(t, ErrNameTaken, g.AddSpectator("carl", ""))
+	assert.Equal(t, ErrNameTaken, g.AddPlayer(Player{Name: "dan"}, ""))
+	assert.Equal(t, []string{"carl", "dan"}, g.SpectatorNames())
+	assert.NoError(t, g.Ping("carl"))
+
+	assert.Equal(t, ErrSpectator, g.Play("carl", "a1"))
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.Equal(t, VOTE, g.CurrentAction, "not waiting on spectators")
+	assert.Equal(t, ErrSpectator, g.Vote("carl", "a1"))
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("bob", "a1"))
+	assert.Equal(t, 1, g.RoundsRemaining)
+
+	assert.NoError(t, g.RemovePlayer("dan"))
+	assert.Equal(t, []string{"carl"}, g.SpectatorNames())
+	assert.Len(t, g.Players, 2)
+}
@@ -0,0 +1,112 @@
+package game
+
+import (
+	"errors"
+	"sort"
+)
+
+const (
+	StateOpen     = "open"     // nothing played yet
+	StateActive   = "active"   // under way
+	StateFinished = "finished" // no rounds remaining
+
+	// MaxSummaries caps how many summaries one call returns.
+	MaxSummaries = 100
+)
+
+var ErrTooManySummaries = errors.New("too many games requested")
+
+// Summary is a lightweight view of a game for listings: no hands or decks.
+type Summary struct {
+	ID              int      `json:"id"`
+	Players         []string `json:"players"`
+	Rounds          int      `json:"rounds"`
+	RoundsRemaining int      `json:"roundsRemaining"`
+	CurrentAction   string   `json:"currentAction"`
+	State           string   `json:"state"`
+	League          string   `json:"league,omitempty"`
+	PIN             bool     `json:"pin,omitempty"` // joining needs one, see WithPIN
+}
+
+// State is StateOpen, StateActive or StateFinished.
+func (g *Game) State() string {
+	g.mutex().lockToRead()
+	defer g.mutex().Unlock()
+	return g.state()
+}
+
+func (g *Game) state() string {
+	if g.RoundsRemaining <= 0 {
+		return StateFinished
+	}
+	for _, round := range g.Rounds {
+		if len(round.Plays) > 0 || len(round.Votes) > 0 {
+			return StateActive
+		}
+	}
+	return StateOpen
+}
+
+func (g *Game) Summary() Summary {
+	g.mutex().lockToRead()
+	defer g.mutex().Unlock()
+	players := make([]string, len(g.Players))
+	for i, player := range g.Players {
+		players[i] = player.Name
+	}
+	return Summary{
+		ID:              g.ID,
+		Players:         players,
+		Rounds:          len(g.Rounds),
+		RoundsRemaining: g.RoundsRemaining,
+		CurrentAction:   g.CurrentAction,
+		State:           g.state(),
+		League:          g.League,
+		PIN:             g.pinHash != "",
+	}
+}
+
+// GamesSummary summarizes the games with the given ids, or every game if ids
+// is empty, keeping those in state unless it's "". Unknown ids are skipped.
+// Asking for more than MaxSummaries ids is an error; listing every game
+// returns at most MaxSummaries, lowest ids first.
+func GamesSummary(ids []int, state string) ([]Summary, error) {
+	if len(ids) > MaxSummaries {
+		return nil, ErrTooManySummaries
+	}
+	summaries := []Summary{}
+	for _, g := range gamesByID(ids) {
+		summary := g.Summary()
+		if state != "" && summary.State != state {
+			continue
+		}
+		summaries = append(summaries, summary)
+		if len(summaries) == MaxSummaries {
+			break
+		}
+	}
+	return summaries, nil
+}
+
+// gamesByID returns the games with the given ids, or every game lowest ids
+// first if ids is empty. It doesn't hold gamesMu while the games are read,
+// as Rematch takes gamesMu holding a game's lock.
+func gamesByID(ids []int) []*Game {
+	gamesMu.RLock()
+	defer gamesMu.RUnlock()
+	if len(ids) == 0 {
+		for id, g := range games {
+			if g != nil {
+				ids = append(ids, id)
+			}
+		}
+		sort.Ints(ids)
+	}
+	found := make([]*Game, 0, len(ids))
+	for _, id := range ids {
+		if g := games[id]; g != nil {
+			found = append(found, g)
+		}
+	}
+	return found
+}
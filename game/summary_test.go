@@ -0,0 +1,38 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGamesSummary(t *testing.T) {
+	saved := games
+	defer func() { games = saved }()
+	games = map[int]*Game{
+		1: {ID: 1, Players: []Player{{Name: "al", Punchlines: []Card{"secret"}}}, Rounds: make([]Round, 3), RoundsRemaining: 3, CurrentAction: PLAY},
+		2: {ID: 2, Players: []Player{{Name: "bob"}}, Rounds: []Round{{Plays: map[string]Card{"bob": "x"}}}, RoundsRemaining: 1, CurrentAction: PLAY},
+		3: {ID: 3, Rounds: make([]Round, 2), CurrentAction: PLAY},
+	}
+
+	summaries, err := GamesSummary(nil, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []Summary{
+		{ID: 1, Players: []string{"al"}, Rounds: 3, RoundsRemaining: 3, CurrentAction: PLAY, State: StateOpen},
+		{ID: 2, Players: []string{"bob"}, Rounds: 1, RoundsRemaining: 1, CurrentAction: PLAY, State: StateActive},
+		{ID: 3, Players: []string{}, Rounds: 2, RoundsRemaining: 0, CurrentAction: PLAY, State: StateFinished},
+	}, summaries)
+
+	summaries, err = GamesSummary([]int{3, 2, 42}, "")
+	assert.NoError(t, err)
+	assert.Len(t, summaries, 2)
+	assert.Equal(t, 3, summaries[0].ID)
+
+	summaries, err = GamesSummary(nil, StateActive)
+	assert.NoError(t, err)
+	assert.Len(t, summaries, 1)
+	assert.Equal(t, 2, summaries[0].ID)
+
+	_, err = GamesSummary(make([]int, MaxSummaries+1), "")
+	assert.Equal(t, ErrTooManySummaries, err)
+}
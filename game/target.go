@@ -0,0 +1,81 @@
+package game
+
+import "errors"
+
+const maxTargetScore = 50
+
+var (
+	ErrInvalidTargetScore = errors.New("target score must be between 1 and 50")
+	ErrTargetScore        = errors.New("rounds are added as needed in games played to a target score")
+)
+
+// WithTargetScore plays the game until a player has won n rounds, rather
+// than for a fixed number of them. NewGame's rounds then caps how long the
+// game can go on, with 0 leaving only the configured MaxRounds, and just
+// the first round is set up; each settled round that leaves everyone short
+// of n adds another with setups drawn from those left over, so
+// RoundsRemaining never counts more than the round being played. If no
+// setups are left, or the cap is reached, the game ends with the leader
+// winning, ties settled as WithTieBreak says. Check n with
+// ValidateTargetScore.
+func WithTargetScore(n int) Option {
+	return func(g *Game) {
+		g.TargetScore = n
+	}
+}
+
+func ValidateTargetScore(n int) error {
+	if n < 1 || n > maxTargetScore {
+		return ErrInvalidTargetScore
+	}
+	return nil
+}
+
+// setRounds checks a new game's rounds and sets up how many it deals: all
+// of them, or in games played to a target score just the first, with
+// rounds, if not 0, kept as RoundLimit.
+func (g *Game) setRounds(rounds int) error {
+	if g.TargetScore == 0 {
+		if err := ValidateRounds(rounds); err != nil {
+			return err
+		}
+		g.RoundsRemaining = rounds
+		return nil
+	}
+	if rounds != 0 {
+		if err := ValidateRounds(rounds); err != nil {
+			return err
+		}
+	}
+	g.RoundLimit = rounds
+	g.RoundsRemaining = 1
+	return nil
+}
+
+// targetReached reports whether a player has reached the game's target
+// score.
+func (g *Game) targetReached() bool {
+	for _, player := range g.Players {
+		if player.Score >= g.TargetScore {
+			return true
+		}
+	}
+	return false
+}
+
+// addTargetRound adds the next round of a game played to a target score
+// once the last one is settled, unless someone has reached the target or
+// the game can't go on: it's at RoundLimit or the configured MaxRounds, or
+// has no setups left. The caller deals and starts it as any other round.
+func (g *Game) addTargetRound() {
+	if g.TargetScore == 0 || g.targetReached() || len(g.setupPool) < g.setupSize() {
+		return
+	}
+	if g.RoundLimit > 0 && len(g.Rounds) >= g.RoundLimit {
+		return
+	}
+	if ValidateRounds(len(g.Rounds)+1) != nil {
+		return
+	}
+	g.prependRounds([]Round{{Setup: g.drawSetup()}})
+}
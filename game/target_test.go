@@ -0,0 +1,88 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// winRound plays a round of g in which everyone votes for winner's card.
+func winRound(t *testing.T, g *Game, winner string) {
+	for _, player := range g.Players {
+		assert.NoError(t, g.Play(player.Name, player.Punchlines[0]))
+	}
+	card := g.Rounds[g.RoundsRemaining-1].Plays[winner]
+	for _, player := range g.Players {
+		assert.NoError(t, g.Vote(player.Name, card))
+	}
+}
+
+func TestTargetScore(t *testing.T) {
+	source := &staticSource{}
+	for i := 0; i < 20; i++ {
+		*source = append(*source, RatedCard{Text: Card(fmt.Sprintf("card %d", i)), Rating: "G"})
+	}
+	ctx := context.Background()
+	_, err := NewGame(ctx, Player{Name: "al"}, 0, "R", WithCardSource(source))
+	assert.True(t, errors.Is(err, ErrInvalidRounds), "rounds are needed without a target")
+
+	g, err := NewGame(ctx, Player{Name: "al"}, 0, "R", WithCardSource(source), WithHandSize(3), WithMinPlayers(2), WithTargetScore(2))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	assert.Len(t, g.Rounds, 1)
+	assert.Equal(t, 1, g.RoundsRemaining)
+	assert.Equal(t, 0, g.RoundLimit)
+	assert.Equal(t, ErrTargetScore, g.ExtendRounds(ctx, "al", 1))
+	assert.NoError(t, g.AddPlayer(Player{Name: "bob"}, ""))
+	assert.NoError(t, g.Start("al", false))
+
+	winRound(t, g, "al")
+	assert.False(t, g.Finished)
+	assert.Len(t, g.Rounds, 2)
+	assert.Equal(t, 1, g.RoundsRemaining)
+	assert.NotEqual(t, g.Rounds[0].Setup, g.Rounds[1].Setup)
+	winRound(t, g, "bob")
+	assert.False(t, g.Finished)
+	winRound(t, g, "al")
+	assert.True(t, g.Finished)
+	assert.Len(t, g.Rounds, 3)
+	assert.Equal(t, []string{"al"}, g.FinalWinners)
+}
+
+func TestTargetScoreRunsOut(t *testing.T) {
+	newGame := func(limit int, setups ...Card) *Game {
+		return &Game{
+			Players: []Player{
+				{Name: "al", Punchlines: []Card{"a1", "a2", "a3"}},
+				{Name: "bob", Punchlines: []Card{"b1", "b2", "b3"}},
+			},
+			Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+			Rounds:          make([]Round, 1),
+			RoundsRemaining: 1,
+			CurrentAction:   PLAY,
+			TargetScore:     5,
+			RoundLimit:      limit,
+			setupPool:       setups,
+		}
+	}
+
+	// out of setups, the leader wins
+	g := newGame(0, "s1", "s2", "s3")
+	winRound(t, g, "bob")
+	assert.Len(t, g.Rounds, 2)
+	winRound(t, g, "bob")
+	assert.True(t, g.Finished)
+	assert.Equal(t, []string{"bob"}, g.FinalWinners)
+
+	// at the round limit
+	g = newGame(2, "s1", "s2", "s3", "s4", "s5", "s6")
+	winRound(t, g, "al")
+	winRound(t, g, "bob")
+	assert.True(t, g.Finished)
+	assert.Len(t, g.Rounds, 2)
+	assert.Equal(t, []string{"al", "bob"}, g.FinalWinners)
+	assert.Equal(t, TieBreakShared, g.TieBrokenBy)
+}
@@ -0,0 +1,132 @@
+package game
+
+import (
+	"errors"
+	"sort"
+)
+
+var (
+	ErrInvalidTeam  = errors.New("team name must be 1 to 32 printable characters")
+	ErrNoTeams      = errors.New("this game isn't played in teams")
+	ErrTeammateCard = errors.New("can't vote for a teammate's card")
+)
+
+// TeamScore is a team's line on the team scoreboard.
+type TeamScore struct {
+	Team    string   `json:"team"`
+	Score   int      `json:"score"`   // rounds won by any of its players
+	Players []string `json:"players"` // still in the game
+}
+
+// WithTeams plays the game in teams: players join with a Team, can't vote
+// for their teammates' cards, and each round a team's player wins scores a
+// point for the team as well. Players without a team play for themselves.
+func WithTeams() Option {
+	return func(g *Game) {
+		g.Teams = true
+	}
+}
+
+// checkTeam normalizes a joining player's team with NormalizePlayerName, and
+// checks it if they gave one, and that the game has teams.
+func (g *Game) checkTeam(team string) (string, error) {
+	team = NormalizePlayerName(team)
+	if team == "" {
+		return "", nil
+	}
+	if !g.Teams {
+		return "", ErrNoTeams
+	}
+	return team, ValidateTeam(team)
+}
+
+// ValidateTeam checks a normalized team name as ValidatePlayerName does.
+func ValidateTeam(team string) error {
+	if ValidatePlayerName(team) != nil {
+		return ErrInvalidTeam
+	}
+	return nil
+}
+
+// team returns playerName's team, "" if they have none or have left.
+func (g *Game) team(playerName string) string {
+	for _, player := range g.Players {
+		if player.Name == playerName {
+			return player.Team
+		}
+	}
+	return ""
+}
+
+// teammates reports whether a and b are different players on the same team.
+func (g *Game) teammates(a, b string) bool {
+	if !g.Teams || a == b {
+		return false
+	}
+	team := g.team(a)
+	return team != "" && team == g.team(b)
+}
+
+// winningTeams lists the teams of round's winners, each once.
+func (g *Game) winningTeams(round Round) []string {
+	var teams []string
+	seen := make(map[string]bool)
+	for _, winner := range round.Winners {
+		if team := g.team(winner); team != "" && !seen[team] {
+			seen[team] = true
+			teams = append(teams, team)
+		}
+	}
+	sort.Strings(teams)
+	return teams
+}
+
+// teamScoreboard ranks the teams by rounds won, counting only settled
+// rounds, then by name. It's nil unless the game has teams.
+func (g *Game) teamScoreboard() []TeamScore {
+	if !g.Teams {
+		return nil
+	}
+	scores := make(map[string]*TeamScore)
+	score := func(team string) *TeamScore {
+		if scores[team] == nil {
+			scores[team] = &TeamScore{Team: team, Players: []string{}}
+		}
+		return scores[team]
+	}
+	for _, player := range g.Players {
+		if player.Team != "" {
+			score(player.Team).Players = append(score(player.Team).Players, player.Name)
+		}
+	}
+	for _, round := range g.Rounds {
+		if !round.resolved {
+			continue
+		}
+		for _, team := range round.WinningTeams {
+			score(team).Score++
+		}
+	}
+	board := make([]TeamScore, 0, len(scores))
+	for _, score := range scores {
+		board = append(board, *score)
+	}
+	sort.Slice(board, func(i, j int) bool {
+		if board[i].Score != board[j].Score {
+			return board[i].Score > board[j].Score
+		}
+		return board[i].Team < board[j].Team
+	})
+	return board
+}
+
+// checkTeammate returns ErrTeammateCard if card was played by a teammate of
+// playerName.
+func (g *Game) checkTeammate(round Round, playerName string, card Card) error {
+	for name, played := range round.Plays {
+		if played == card && g.teammates(playerName, name) {
+			return ErrTeammateCard
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeams(t *testing.T) {
+	source := &staticSource{}
+	for i := 0; i < 40; i++ {
+		*source = append(*source, RatedCard{Text: Card(strconv.Itoa(i)), Rating: "G"})
+	}
+	ctx := context.Background()
+	_, err := NewGame(ctx, Player{Name: "al", Team: "red"}, 2, "R", WithCardSource(source))
+	assert.Equal(t, ErrNoTeams, err)
+	solo, err := NewGame(ctx, Player{Name: "al"}, 2, "R", WithCardSource(source))
+	assert.NoError(t, err)
+	defer deleteGame(solo.ID)
+	assert.Equal(t, ErrNoTeams, solo.AddPlayer(Player{Name: "bob", Team: "red"}, ""))
+	assert.Nil(t, solo.teamScoreboard())
+
+	g, err := NewGame(ctx, Player{Name: "al", Team: " red "}, 2, "R", WithCardSource(source), WithHandSize(3), WithTeams())
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	assert.Equal(t, "red", g.Players[0].Team)
+	assert.Equal(t, ErrInvalidTeam, g.AddPlayer(Player{Name: "bob", Team: strings.Repeat("x", 33)}, ""))
+	assert.NoError(t, g.AddPlayer(Player{Name: "bob", Team: "red"}, ""))
+	assert.NoError(t, g.AddPlayer(Player{Name: "carl", Team: "blue"}, ""))
+	assert.NoError(t, g.AddPlayer(Player{Name: "dan"}, ""), "no team")
+	assert.NoError(t, g.Start("al", false))
+
+	play := func() map[string]Card {
+		plays := make(map[string]Card)
+		for _, player := range g.Players {
+			plays[player.Name] = player.Punchlines[0]
+			assert.NoError(t, g.Play(player.Name, player.Punchlines[0]))
+		}
+		return plays
+	}
+	plays := play()
+	assert.Equal(t, ErrTeammateCard, g.Vote("al", plays["bob"]))
+	assert.NoError(t, g.Vote("al", plays["al"]), "your own card is still fine in single votes")
+	assert.NoError(t, g.Vote("bob", plays["carl"]))
+	assert.NoError(t, g.Vote("carl", plays["al"]))
+	assert.NoError(t, g.Vote("dan", plays["al"]))
+	round := g.Rounds[1]
+	assert.Equal(t, []string{"al"}, round.Winners)
+	assert.Equal(t, []string{"red"}, round.WinningTeams)
+	assert.Equal(t, []TeamScore{
+		{Team: "red", Score: 1, Players: []string{"al", "bob"}},
+		{Team: "blue", Score: 0, Players: []string{"carl"}},
+	}, g.teamScoreboard())
+
+	// a tie between teammates scores the team once
+	plays = play()
+	assert.NoError(t, g.Vote("al", plays["carl"]))
+	assert.NoError(t, g.Vote("bob", plays["dan"]))
+	assert.NoError(t, g.Vote("carl", plays["dan"]))
+	assert.NoError(t, g.Vote("dan", plays["carl"]))
+	assert.Equal(t, []string{"carl", "dan"}, g.Rounds[0].Winners)
+	assert.Equal(t, []string{"blue"}, g.Rounds[0].WinningTeams, "dan has no team")
+	assert.Equal(t, 1, g.teamScoreboard()[1].Score)
+
+	j, err := json.Marshal(g)
+	assert.NoError(t, err)
+	assert.Contains(t, string(j), `"teamScoreboard":[{"team":"blue","score":1,"players":["carl"]},{"team":"red","score":1,"players":["al","bob"]}]`)
+	assert.Equal(t, g.teamScoreboard(), g.ViewFor("dan", HandDealt).TeamScoreboard)
+}
+
+func TestTeammateCardInMultipleVotes(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Team: "red", Punchlines: []Card{"a1"}},
+			{Name: "bob", Team: "red", Punchlines: []Card{"b1"}},
+			{Name: "carl", Team: "blue", Punchlines: []Card{"c1"}},
+		},
+		Rounds:          make([]Round, 1),
+		RoundsRemaining: 1,
+		CurrentAction:   PLAY,
+		VotingMode:      VotingMulti,
+		VotesPerPlayer:  2,
+		Teams:           true,
+	}
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Play("carl", "c1"))
+	assert.Equal(t, ErrTeammateCard, g.Vote("al", "c1", "b1"))
+	assert.NoError(t, g.Vote("al", "c1"))
+}
@@ -0,0 +1,122 @@
+package game
+
+import "errors"
+
+// Tie-break rules, for a tie for the top score once the last round is
+// settled, see WithTieBreak.
+const (
+	TieBreakShared      = "shared"       // the tied players all win
+	TieBreakVotes       = "votes"        // the tied player who received the most votes wins
+	TieBreakSuddenDeath = "sudden_death" // the tied players play extra rounds until one wins
+)
+
+var (
+	ErrInvalidTieBreak  = errors.New("tie break must be shared, votes or sudden_death")
+	ErrNotInSuddenDeath = errors.New("only the tied players play in sudden death")
+	ErrSuddenDeath      = errors.New("the game is in sudden death")
+)
+
+// WithTieBreak sets how a tie for the top score is settled once the last
+// round is: TieBreakShared, the default, TieBreakVotes or
+// TieBreakSuddenDeath. Sudden death adds a round with a setup left over
+// from the deck that only the tied players play in and everyone votes in,
+// and repeats until the tie is broken. Judge games, or games with no
+// setups left, fall back to TieBreakVotes. Check rule with
+// ValidateTieBreak.
+func WithTieBreak(rule string) Option {
+	return func(g *Game) {
+		g.TieBreak = rule
+	}
+}
+
+func ValidateTieBreak(rule string) error {
+	switch rule {
+	case TieBreakShared, TieBreakVotes, TieBreakSuddenDeath:
+		return nil
+	}
+	return ErrInvalidTieBreak
+}
+
+// suddenDeath reports whether the round being played is a sudden-death one.
+func (g *Game) suddenDeath() bool {
+	return len(g.SuddenDeath) > 0 && !g.over()
+}
+
+// checkSuddenDeath returns ErrNotInSuddenDeath if the round is a
+// sudden-death one and playerName isn't one of the tied players.
+func (g *Game) checkSuddenDeath(playerName string) error {
+	if g.suddenDeath() && !contains(g.SuddenDeath, playerName) {
+		return ErrNotInSuddenDeath
+	}
+	return nil
+}
+
+// suddenDeathPlayers counts the tied players still in the game, which is
+// how many plays a sudden-death round waits for.
+func (g *Game) suddenDeathPlayers() int {
+	n := 0
+	for _, player := range g.Players {
+		if contains(g.SuddenDeath, player.Name) {
+			n++
+		}
+	}
+	return n
+}
+
+// addSuddenDeathRound adds a round for the tied players if the last round
+// has just been settled with a tie for the top score and the game breaks
+// ties by sudden death. The caller deals and starts it as any other round.
+func (g *Game) addSuddenDeathRound() {
+	if g.TieBreak != TieBreakSuddenDeath || g.judging() || len(g.setupPool) < g.setupSize() {
+		return
+	}
+	tied := g.winnerNames()
+	if len(tied) < 2 {
+		return
+	}
+	g.prependRounds([]Round{{Setup: g.drawSetup()}})
+	g.SuddenDeath = tied
+}
+
+// finalWinners returns the game's winners once the last round is settled,
+// along with how a tie for the top score was broken, if there was one:
+// TieBreakSuddenDeath if the sudden-death rounds found a winner,
+// TieBreakVotes if votes received did, otherwise TieBreakShared.
+func (g *Game) finalWinners() ([]string, string) {
+	winners := g.winnerNames()
+	if len(winners) < 2 {
+		if len(g.SuddenDeath) > 0 {
+			return winners, TieBreakSuddenDeath
+		}
+		return winners, ""
+	}
+	if g.TieBreak == TieBreakShared || g.TieBreak == "" {
+		return winners, TieBreakShared
+	}
+	votes := make(map[string]int)
+	for _, score := range g.scoreboard() {
+		votes[score.Name] = score.Votes
+	}
+	var best []string
+	for _, name := range winners {
+		switch {
+		case len(best) == 0 || votes[name] > votes[best[0]]:
+			best = []string{name}
+		case votes[name] == votes[best[0]]:
+			best = append(best, name)
+		}
+	}
+	if len(best) == 1 {
+		return best, TieBreakVotes
+	}
+	return winners, TieBreakShared
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
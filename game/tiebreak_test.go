@@ -0,0 +1,85 @@
+package game
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tiedGame plays a two round game that al and bob finish level on a round
+// each, with bob having received more votes. setups are left for sudden
+// death.
+func tiedGame(t *testing.T, rule string, setups ...Card) *Game {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "cy", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+		TieBreak:        rule,
+		setupPool:       setups,
+	}
+	votes := [][]Card{{"b1", "a1", "a1"}, {"b2", "c2", "b2"}}
+	for round := range votes {
+		for _, player := range g.Players {
+			assert.NoError(t, g.Play(player.Name, Card(player.Name[:1]+strconv.Itoa(round+1))))
+		}
+		for i, player := range g.Players {
+			assert.NoError(t, g.Vote(player.Name, votes[round][i]))
+		}
+	}
+	return g
+}
+
+func TestTieBreak(t *testing.T) {
+	g := tiedGame(t, TieBreakShared)
+	assert.True(t, g.Finished)
+	assert.Equal(t, []string{"al", "bob"}, g.FinalWinners)
+	assert.Equal(t, TieBreakShared, g.TieBrokenBy)
+
+	g = tiedGame(t, TieBreakVotes)
+	assert.True(t, g.Finished)
+	assert.Equal(t, []string{"bob"}, g.FinalWinners)
+	assert.Equal(t, TieBreakVotes, g.TieBrokenBy)
+	winners, err := g.Winners()
+	assert.NoError(t, err)
+	assert.Len(t, winners, 1)
+	assert.Equal(t, "bob", winners[0].Name)
+
+	// with no setups left for sudden death, votes received decide
+	g = tiedGame(t, TieBreakSuddenDeath)
+	assert.True(t, g.Finished)
+	assert.Equal(t, []string{"bob"}, g.FinalWinners)
+	assert.Equal(t, TieBreakVotes, g.TieBrokenBy)
+}
+
+func TestSuddenDeath(t *testing.T) {
+	g := tiedGame(t, TieBreakSuddenDeath, "Sudden", "Death")
+	assert.False(t, g.Finished)
+	assert.Equal(t, PLAY, g.CurrentAction)
+	assert.Equal(t, []string{"al", "bob"}, g.SuddenDeath)
+	assert.Len(t, g.Rounds, 3)
+	assert.Equal(t, 1, g.RoundsRemaining)
+	assert.Equal(t, []Card{"Sudden", "Death"}, g.Rounds[0].Setup)
+	assert.Equal(t, ErrSuddenDeath, g.ExtendRounds(context.Background(), "al", 1))
+
+	assert.Equal(t, ErrNotInSuddenDeath, g.Play("cy", g.Players[2].Punchlines[0]))
+	al, bob := g.Players[0].Punchlines[0], g.Players[1].Punchlines[0]
+	assert.NoError(t, g.Play("al", al))
+	assert.NoError(t, g.Play("bob", bob))
+	assert.Equal(t, VOTE, g.CurrentAction, "only the tied players play")
+	assert.NoError(t, g.Vote("al", bob))
+	assert.NoError(t, g.Vote("bob", al))
+	assert.False(t, g.Finished, "everyone votes")
+	assert.NoError(t, g.Vote("cy", al))
+
+	assert.True(t, g.Finished)
+	assert.Equal(t, []string{"al"}, g.FinalWinners)
+	assert.Equal(t, TieBreakSuddenDeath, g.TieBrokenBy)
+}
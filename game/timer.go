@@ -0,0 +1,152 @@
+package game
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	minTimerSeconds = 15
+	maxTimerSeconds = 60 * 10
+)
+
+var (
+	ErrInvalidTimer = errors.New("timers must be 0 (off) or between 15 and 600 seconds")
+	ErrNotHost      = errors.New("only the host can do that")
+	ErrMidRound     = errors.New("can only be changed between rounds")
+)
+
+// WithTimers limits the play and vote phases to the given number of seconds
+// each, where 0 leaves a phase untimed. Check them with ValidateTimers.
+func WithTimers(playSeconds, voteSeconds int) Option {
+	return func(g *Game) {
+		g.PlaySeconds, g.VoteSeconds = playSeconds, voteSeconds
+	}
+}
+
+func ValidateTimers(playSeconds, voteSeconds int) error {
+	for _, seconds := range []int{playSeconds, voteSeconds} {
+		if seconds != 0 && (seconds < minTimerSeconds || seconds > maxTimerSeconds) {
+			return ErrInvalidTimer
+		}
+	}
+	return nil
+}
+
+// SetTimers changes the game's timers. Only the host can, and only between
+// rounds, i.e. before anyone has played in this one.
+// The new play timer starts now.
+func (g *Game) SetTimers(playerName string, playSeconds, voteSeconds int) error {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.isHost(playerName) {
+		return ErrNotHost
+	}
+	err := ValidateTimers(playSeconds, voteSeconds)
+	if err != nil {
+		return err
+	}
+	if g.over() {
+		return ErrGameFinished
+	}
+	if !g.betweenRounds() {
+		return ErrMidRound
+	}
+	g.PlaySeconds, g.VoteSeconds = playSeconds, voteSeconds
+	g.setDeadline()
+	return nil
+}
+
+// setDeadline starts the timer for the phase just entered, if it has one.
+func (g *Game) setDeadline() {
+	seconds := g.PlaySeconds
+	if g.CurrentAction == VOTE {
+		seconds = g.VoteSeconds
+	}
+	if seconds == 0 || g.RoundsRemaining <= 0 || g.CurrentAction == LOBBY {
+		g.PhaseDeadline = nil
+		return
+	}
+	deadline := now().Add(time.Second * time.Duration(seconds))
+	g.PhaseDeadline = &deadline
+}
+
+// stopTimer clears the phase deadline of a game that's been reaped or
+// deleted, so a sweep already under way doesn't time it out and clients stop
+// counting down.
+func (g *Game) stopTimer() {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	g.PhaseDeadline = nil
+}
+
+// restartTimer gives a restored game's phase its full time again.
+func (g *Game) restartTimer() {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.over() {
+		g.setDeadline()
+	}
+}
+
+// expire handles the current phase running out of time. Players who haven't
+// played are auto-played for in AutoPlay games and otherwise sit the round
+// out; if nobody played at all, the play timer starts again. Voters who
+// haven't voted are voted for in AutoVote games and otherwise skipped, and
+// the round is settled on the votes cast.
+func (g *Game) expire() {
+	if g.over() {
+		g.PhaseDeadline = nil
+		return
+	}
+	switch g.CurrentAction {
+	case PLAY:
+		g.playTimeout()
+		if g.CurrentAction != PLAY {
+			return
+		}
+		if len(g.Rounds[g.RoundsRemaining-1].Plays) == 0 {
+			g.setDeadline()
+			return
+		}
+		g.startVoting()
+	case VOTE:
+		g.voteTimeout()
+		g.finishRound()
+	}
+}
+
+// ExpireDeadlines runs timeouts for every game whose phase deadline has
+// passed and returns the games that changed. Deleted games are skipped,
+// including those deleted while it runs.
+func ExpireDeadlines() []*Game {
+	var expired []*Game
+	t := now()
+	for _, g := range liveGames() {
+		if !isLive(g) {
+			continue
+		}
+		if g.expireBy(t) && isLive(g) {
+			expired = append(expired, g)
+		}
+	}
+	return expired
+}
+
+// isLive reports whether g is still the game GetGame returns for its id.
+func isLive(g *Game) bool {
+	found, err := GetGame(g.ID)
+	return err == nil && found == g
+}
+
+// expireBy runs expire if g's phase deadline has passed by t, and reports
+// whether it did. Paused games' timers don't run out.
+func (g *Game) expireBy(t time.Time) bool {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if g.Paused || g.PhaseDeadline == nil || t.Before(*g.PhaseDeadline) {
+		return false
+	}
+	g.expire()
+	return true
+}
@@ -0,0 +1,120 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhaseTimers(t *testing.T) {
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	g := &Game{
+		ID: 998,
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	assert.Equal(t, ErrInvalidTimer, g.SetTimers("al", 10, 0))
+	assert.Equal(t, ErrNotHost, g.SetTimers("bob", 30, 20))
+	assert.NoError(t, g.SetTimers("al", 30, 0))
+	assert.Equal(t, clock.Add(time.Second*30), *g.PhaseDeadline)
+	games[g.ID] = g
+	defer deleteGame(g.ID)
+
+	// nobody played: the play timer starts over
+	clock = clock.Add(time.Second * 30)
+	assert.Equal(t, []*Game{g}, ExpireDeadlines())
+	assert.Equal(t, PLAY, g.CurrentAction)
+	assert.Equal(t, clock.Add(time.Second*30), *g.PhaseDeadline)
+
+	// carl hasn't played when time runs out, so sits the round out
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.Equal(t, ErrMidRound, g.SetTimers("al", 30, 20))
+	assert.NoError(t, g.Play("bob", "b1"))
+	clock = clock.Add(time.Second * 29)
+	assert.Empty(t, ExpireDeadlines())
+	clock = clock.Add(time.Second)
+	assert.Equal(t, []*Game{g}, ExpireDeadlines())
+	assert.Equal(t, VOTE, g.CurrentAction)
+	assert.Nil(t, g.PhaseDeadline, "voting isn't timed")
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("bob", "a1"))
+	assert.NoError(t, g.Vote("carl", "b1"))
+	assert.Equal(t, "bob", g.Rounds[1].Winner)
+	assert.Equal(t, PLAY, g.CurrentAction)
+
+	// between rounds the timers can change, and each phase has its own
+	assert.NoError(t, g.SetTimers("al", 60, 15))
+	assert.Equal(t, clock.Add(time.Minute), *g.PhaseDeadline)
+	WithAutoPlay()(g)
+	WithAutoVote()(g)
+	clock = clock.Add(time.Minute)
+	assert.Equal(t, []*Game{g}, ExpireDeadlines())
+	assert.Len(t, g.Rounds[0].Plays, 3, "auto-played")
+	assert.Equal(t, VOTE, g.CurrentAction)
+	assert.Equal(t, clock.Add(time.Second*15), *g.PhaseDeadline)
+
+	// and in auto-vote games, voters who miss the vote timer are voted for
+	assert.NoError(t, g.Vote("al", g.Rounds[0].Plays["carl"]))
+	clock = clock.Add(time.Second * 15)
+	assert.Equal(t, []*Game{g}, ExpireDeadlines())
+	assert.Equal(t, map[string]bool{"bob": true, "carl": true}, g.Rounds[0].AutoVoted)
+	assert.Len(t, g.Rounds[0].Votes, 3)
+	assert.Equal(t, 0, g.RoundsRemaining)
+	assert.Nil(t, g.PhaseDeadline, "finished")
+	assert.Empty(t, ExpireDeadlines())
+}
+
+func TestPhaseTimersAbstain(t *testing.T) {
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	g := &Game{
+		ID: 997,
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	assert.NoError(t, g.SetTimers("al", 30, 20))
+	games[g.ID] = g
+	defer deleteGame(g.ID)
+
+	// carl abstains from playing: voting starts on the two plays there are
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	clock = clock.Add(time.Second * 30)
+	assert.Equal(t, []*Game{g}, ExpireDeadlines())
+	assert.Equal(t, VOTE, g.CurrentAction)
+	assert.ElementsMatch(t, []Card{"a1", "b1"}, g.Rounds[1].Ballot)
+	assert.Equal(t, clock.Add(time.Second*20), *g.PhaseDeadline)
+
+	// bob and carl abstain from voting: the round is settled on al's vote
+	assert.NoError(t, g.Vote("al", "b1"))
+	clock = clock.Add(time.Second * 19)
+	assert.Empty(t, ExpireDeadlines())
+	clock = clock.Add(time.Second)
+	assert.Equal(t, []*Game{g}, ExpireDeadlines())
+	assert.Equal(t, map[string]Card{"al": "b1"}, g.Rounds[1].Votes)
+	assert.Empty(t, g.Rounds[1].AutoVoted)
+	assert.Equal(t, "bob", g.Rounds[1].Winner)
+	assert.Equal(t, 1, g.RoundsRemaining)
+	assert.Equal(t, PLAY, g.CurrentAction)
+	assert.Equal(t, clock.Add(time.Second*30), *g.PhaseDeadline, "the next round's play timer")
+}
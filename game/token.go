@@ -0,0 +1,89 @@
+package game
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// IssueToken creates a random token for the named player or spectator,
+// keeping only its hash. It's the fallback for deployments without a token
+// signing secret, and only works where the game itself is available to
+// check against.
+func (g *Game) IssueToken(playerName string) (string, error) {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	player := g.player(playerName)
+	if player == nil && !g.isSpectator(playerName) {
+		return "", ErrPlayerNotFound
+	}
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	if player != nil {
+		player.TokenHash = hashToken(token)
+		return token, nil
+	}
+	if g.specTokens == nil {
+		g.specTokens = make(map[string]string)
+	}
+	g.specTokens[playerName] = hashToken(token)
+	return token, nil
+}
+
+// TokenPlayer returns the name of the player or spectator token was issued
+// to.
+func (g *Game) TokenPlayer(token string) (string, bool) {
+	g.mutex().lockToRead()
+	defer g.mutex().Unlock()
+	for _, player := range g.Players {
+		if matchesHash(token, player.TokenHash) {
+			return player.Name, true
+		}
+	}
+	for name, hash := range g.specTokens {
+		if matchesHash(token, hash) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// TokenGeneration returns the generation of name's tokens. Signed tokens
+// carry it, so bumping it when someone leaves, is kicked or is renamed
+// revokes the tokens they were issued.
+func (g *Game) TokenGeneration(name string) int {
+	g.mutex().lockToRead()
+	defer g.mutex().Unlock()
+	return g.tokenGens[name]
+}
+
+// revokeTokens stops the tokens issued under name from working.
+func (g *Game) revokeTokens(name string) {
+	if g.tokenGens == nil {
+		g.tokenGens = make(map[string]int)
+	}
+	g.tokenGens[name]++
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// matchesHash reports whether token hashes to hash, which must be set.
+func matchesHash(token, hash string) bool {
+	return hash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(hashToken(token))) == 1
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,37 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueToken(t *testing.T) {
+	g := &Game{Players: []Player{{Name: "al"}, {Name: "bob"}}}
+	token, err := g.IssueToken("bob")
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, g.Players[1].TokenHash)
+
+	name, ok := g.TokenPlayer(token)
+	assert.True(t, ok)
+	assert.Equal(t, "bob", name)
+
+	_, ok = g.TokenPlayer("not-a-token")
+	assert.False(t, ok)
+	_, ok = g.TokenPlayer("")
+	assert.False(t, ok)
+
+	_, err = g.IssueToken("carl")
+	assert.Equal(t, ErrPlayerNotFound, err)
+
+	// spectators get tokens too, which stop working when they leave
+	assert.NoError(t, g.AddSpectator("sam", ""))
+	token, err = g.IssueToken("sam")
+	assert.NoError(t, err)
+	name, ok = g.TokenPlayer(token)
+	assert.True(t, ok)
+	assert.Equal(t, "sam", name)
+	assert.NoError(t, g.RemovePlayer("sam"))
+	_, ok = g.TokenPlayer(token)
+	assert.False(t, ok)
+}
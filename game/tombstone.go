@@ -0,0 +1,82 @@
+package game
+
+import (
+	"errors"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+)
+
+var (
+	ErrGameGone = errors.New("game has been deleted")
+
+	tombstones = make(map[int]tombstone)
+)
+
+// tombstone keeps a deleted game around so the deletion can be undone. Its
+// id stays taken until the game is purged.
+type tombstone struct {
+	game    *Game
+	deleted time.Time
+}
+
+// Delete removes a game from lookup and stops its phase timer, after which
+// GetGame returns ErrGameGone. It can be restored until PurgeDeleted runs
+// after the configured TombstoneWindow.
+func Delete(id int) error {
+	g, err := tombstoneGame(id)
+	if err != nil {
+		return err
+	}
+	g.stopTimer()
+	return nil
+}
+
+func tombstoneGame(id int) (*Game, error) {
+	gamesMu.Lock()
+	defer gamesMu.Unlock()
+	g, ok := games[id]
+	if !ok || g == nil {
+		return nil, ErrGameNotFound
+	}
+	delete(games, id)
+	tombstones[id] = tombstone{game: g, deleted: now()}
+	return g, nil
+}
+
+// Restore undoes Delete, starting the current phase's timer over.
+func Restore(id int) (*Game, error) {
+	g, err := restoreGame(id)
+	if err != nil {
+		return nil, err
+	}
+	g.restartTimer()
+	return g, nil
+}
+
+func restoreGame(id int) (*Game, error) {
+	gamesMu.Lock()
+	defer gamesMu.Unlock()
+	t, ok := tombstones[id]
+	if !ok {
+		return nil, ErrGameNotFound
+	}
+	delete(tombstones, id)
+	games[id] = t.game
+	return t.game, nil
+}
+
+// PurgeDeleted permanently removes games deleted more than TombstoneWindow
+// ago, freeing their ids.
+func PurgeDeleted() {
+	cutoff := now().Add(-config.Current().TombstoneWindow)
+	gamesMu.Lock()
+	defer gamesMu.Unlock()
+	for id, t := range tombstones {
+		if t.deleted.Before(cutoff) {
+			delete(tombstones, id)
+			delete(codes, t.game.Code)
+			gameIDs.release(id)
+		}
+	}
+}
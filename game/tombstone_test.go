@@ -0,0 +1,62 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteRestorePurge(t *testing.T) {
+	start := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	clock := start
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+	savedIDs := gameIDs
+	defer func() { gameIDs = savedIDs }()
+	gameIDs = newIDPool(1)
+
+	id, err := findID(testRNG)
+	assert.NoError(t, err)
+	g := &Game{ID: id, Rounds: make([]Round, 1), RoundsRemaining: 1, CurrentAction: PLAY, PlaySeconds: 30}
+	g.setDeadline()
+	games[id] = g
+
+	assert.NoError(t, Delete(id))
+	_, err = GetGame(id)
+	assert.Equal(t, ErrGameGone, err)
+	assert.Equal(t, ErrGameNotFound, Delete(id))
+	assert.Nil(t, g.PhaseDeadline, "its timer is stopped")
+	clock = start.Add(time.Minute)
+	assert.Empty(t, ExpireDeadlines())
+
+	restored, err := Restore(id)
+	assert.NoError(t, err)
+	assert.Equal(t, g, restored)
+	assert.Equal(t, clock.Add(time.Second*30), *g.PhaseDeadline, "the phase starts over")
+	found, err := GetGame(id)
+	assert.NoError(t, err)
+	assert.Equal(t, g, found)
+	_, err = Restore(id)
+	assert.Equal(t, ErrGameNotFound, err)
+	clock = start
+
+	// purged only after the window, which frees the id
+	assert.NoError(t, Delete(id))
+	clock = start.Add(time.Minute * 5)
+	PurgeDeleted()
+	_, err = GetGame(id)
+	assert.Equal(t, ErrGameGone, err)
+	_, err = findID(testRNG)
+	assert.Equal(t, ErrNoGamesAvailable, err)
+
+	clock = start.Add(time.Minute * 11)
+	PurgeDeleted()
+	_, err = GetGame(id)
+	assert.Equal(t, ErrGameNotFound, err)
+	_, err = Restore(id)
+	assert.Equal(t, ErrGameNotFound, err)
+	reused, err := findID(testRNG)
+	assert.NoError(t, err)
+	assert.Equal(t, id, reused)
+}
@@ -0,0 +1,141 @@
+package game
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"text/template"
+)
+
+const (
+	FormatMarkdown = "markdown"
+	FormatText     = "text"
+)
+
+var ErrUnknownFormat = errors.New("format must be markdown or text")
+
+// Transcript is a game laid out for reading: standings, then each played
+// round in order.
+type Transcript struct {
+	GameID    int
+	League    string
+	Locale    string
+	Standings []Standing
+	Rounds    []TranscriptRound
+}
+
+type TranscriptRound struct {
+	Number  int
+	Setup   []Card
+	Plays   []TranscriptPlay // best first, see Round.Results
+	Comment string
+}
+
+type TranscriptPlay struct {
+	Player string
+	Card   Card
+	Votes  int
+	Winner bool
+}
+
+// Transcript builds the game's transcript from the rounds played so far.
+func (g *Game) Transcript() Transcript {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	t := Transcript{GameID: g.ID, League: g.League, Locale: g.Locale, Standings: g.standings()}
+	for number := 1; number <= len(g.Rounds); number++ {
+		round := g.Rounds[len(g.Rounds)-number]
+		if len(round.Plays) == 0 {
+			break
+		}
+		results := round.Results
+		if results == nil {
+			results = roundResults(round)
+		}
+		tr := TranscriptRound{Number: number, Setup: round.Setup, Comment: round.Comment}
+		for _, result := range results {
+			tr.Plays = append(tr.Plays, TranscriptPlay{
+				Player: result.Player,
+				Card:   result.Card,
+				Votes:  result.Votes,
+				Winner: result.Won,
+			})
+		}
+		t.Rounds = append(t.Rounds, tr)
+	}
+	return t
+}
+
+// T formats the message key in the transcript's locale, for the templates.
+func (t Transcript) T(key string, args ...interface{}) string {
+	return translate(t.Locale, key, args...)
+}
+
+// Heading formats the heading of round number, naming its setups.
+func (t Transcript) Heading(number int, setup []Card) string {
+	key := "round_heading"
+	if len(setup) == 3 {
+		key = "round_heading_3"
+	}
+	args := []interface{}{number}
+	for _, card := range setup {
+		args = append(args, card)
+	}
+	return t.T(key, args...)
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "|", `\|`, "[", `\[`, "]", `\]`, "#", `\#`, "<", `\<`,
+)
+
+var transcriptTemplates = map[string]*template.Template{
+	FormatMarkdown: template.Must(template.New(FormatMarkdown).Funcs(template.FuncMap{
+		"md": func(s interface{}) string {
+			switch v := s.(type) {
+			case Card:
+				return markdownEscaper.Replace(string(v))
+			case string:
+				return markdownEscaper.Replace(v)
+			}
+			return ""
+		},
+		"mdCards": func(cards []Card) []Card {
+			escaped := make([]Card, len(cards))
+			for i, card := range cards {
+				escaped[i] = Card(markdownEscaper.Replace(string(card)))
+			}
+			return escaped
+		},
+	}).Parse(`# {{.T "game" .GameID}}{{if .League}} ({{md .League}}){{end}}
+
+| {{.T "player"}} | {{.T "rounds_won"}} |
+| --- | ---: |
+{{range .Standings}}| {{md .Player}}{{if .JoinedAtRound}} ({{$.T "joined_round" .JoinedAtRound}}){{end}} | {{.RoundsWon}} |
+{{end}}{{range .Rounds}}
+## {{$.Heading .Number (mdCards .Setup)}}
+
+{{range .Plays}}- {{if .Winner}}**{{md .Player}}: {{md .Card}}** ({{$.T "winner"}}){{else}}{{md .Player}}: {{md .Card}}{{end}} — {{if eq .Votes 1}}{{$.T "vote" .Votes}}{{else}}{{$.T "votes" .Votes}}{{end}}
+{{end}}{{if .Comment}}
+> {{md .Comment}}
+{{end}}{{end}}`)),
+
+	FormatText: template.Must(template.New(FormatText).Parse(`{{.T "game" .GameID}}{{if .League}} ({{.League}}){{end}}
+
+{{.T "standings"}}
+{{range $i, $s := .Standings}}  {{$s.Player}}: {{$s.RoundsWon}}{{if $s.JoinedAtRound}} ({{$.T "joined_round" $s.JoinedAtRound}}){{end}}
+{{end}}{{range .Rounds}}
+{{$.Heading .Number .Setup}}
+{{range .Plays}}  {{if .Winner}}* {{else}}  {{end}}{{.Player}}: {{.Card}} ({{if eq .Votes 1}}{{$.T "vote" .Votes}}{{else}}{{$.T "votes" .Votes}}{{end}})
+{{end}}{{if .Comment}}  {{$.T "winner_quote" .Comment}}
+{{end}}{{end}}`)),
+}
+
+// WriteTranscript renders the game's transcript as FormatMarkdown or
+// FormatText.
+func (g *Game) WriteTranscript(w io.Writer, format string) error {
+	tmpl, ok := transcriptTemplates[format]
+	if !ok {
+		return ErrUnknownFormat
+	}
+	return tmpl.Execute(w, g.Transcript())
+}
@@ -0,0 +1,83 @@
+package game
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func transcriptGame() *Game {
+	return &Game{
+		ID:      12,
+		League:  "thursday-club",
+		Players: []Player{{Name: "al"}, {Name: "bob", Score: 1}, {Name: "c_j"}},
+		Rounds: []Round{
+			{Setup: []Card{"Unplayed", "Round"}},
+			{
+				Setup: []Card{"A cat", "A dog"},
+				Plays: map[string]Card{"al": "Fleas", "bob": "Loyalty", "c_j": "*Everything*"},
+				Votes: map[string]Card{"al": "*Everything*", "bob": "Fleas", "c_j": "Fleas"},
+			},
+			{
+				Setup:   []Card{"Love", "Lust"},
+				Plays:   map[string]Card{"al": "About three dates", "bob": "A | pipe", "c_j": "Roughly nothing"},
+				Votes:   map[string]Card{"al": "A | pipe", "bob": "A | pipe", "c_j": "About three dates"},
+				Winner:  "bob",
+				Comment: "I'll be here all week",
+			},
+		},
+		RoundsRemaining: 1,
+	}
+}
+
+func TestWriteTranscript(t *testing.T) {
+	for format, golden := range map[string]string{
+		FormatMarkdown: "transcript.md.golden",
+		FormatText:     "transcript.txt.golden",
+	} {
+		var buf bytes.Buffer
+		assert.NoError(t, transcriptGame().WriteTranscript(&buf, format))
+		path := filepath.Join("testdata", golden)
+		if *update {
+			assert.NoError(t, ioutil.WriteFile(path, buf.Bytes(), 0644))
+		}
+		expected, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, string(expected), buf.String(), format)
+	}
+	assert.Equal(t, ErrUnknownFormat, transcriptGame().WriteTranscript(ioutil.Discard, "pdf"))
+}
+
+func TestTranscriptLocale(t *testing.T) {
+	g := transcriptGame()
+	WithLocale("es")(g)
+	var buf bytes.Buffer
+	assert.NoError(t, g.WriteTranscript(&buf, FormatText))
+	assert.Contains(t, buf.String(), "Partida 12 (thursday-club)\n\nClasificación:\n")
+	assert.Contains(t, buf.String(), "Ronda 1: la diferencia entre Love y Lust\n  * bob: A | pipe (2 votos)\n    al: About three dates (1 voto)\n")
+
+	// missing strings fall back to English
+	messages["xx"] = map[string]string{"game": "Spiel %d"}
+	defer delete(messages, "xx")
+	WithLocale("xx")(g)
+	buf.Reset()
+	assert.NoError(t, g.WriteTranscript(&buf, FormatMarkdown))
+	assert.Contains(t, buf.String(), "# Spiel 12 (thursday-club)\n\n| Player | Rounds won |\n")
+
+	assert.NoError(t, ValidateLocale("es"))
+	assert.Equal(t, ErrUnknownLocale, ValidateLocale("fr"))
+}
+
+func TestLocalesComplete(t *testing.T) {
+	for locale, catalog := range messages {
+		for key := range messages[DefaultLocale] {
+			assert.Contains(t, catalog, key, locale)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/stinkyfingers/differencebetween/api/metrics"
+)
+
+const (
+	usageCreated  = "created"
+	usageStarted  = "started"
+	usageFinished = "finished"
+)
+
+// recordUsage counts a game being created, started or finished, broken down
+// by how it was set up. It's the only place usage metrics are recorded.
+func recordUsage(event string, g *Game) {
+	for _, key := range usageKeys(event, g) {
+		metrics.CountUsage(key)
+	}
+}
+
+// usageKeys returns the usage counter keys for g. Counts are bucketed, and
+// modes and features counted separately, so the number of keys stays small.
+// Players are only counted once the game has started, since until then the
+// host is usually the only one.
+func usageKeys(event string, g *Game) []string {
+	labels := []string{
+		"event=" + event,
+		"cleanliness=" + g.Cleanliness,
+		"rounds=" + bucket(len(g.Rounds), roundBuckets),
+	}
+	if event != usageCreated {
+		labels = append(labels, "players="+bucket(len(g.Players), playerBuckets))
+	}
+	keys := []string{strings.Join(labels, ",")}
+	for _, mode := range []string{
+		"voting=" + votingLabel(g.VotingMode),
+		"timers=" + pairLabel(g.PlaySeconds > 0, g.VoteSeconds > 0),
+		"auto=" + pairLabel(g.AutoPlay, g.AutoVote),
+	} {
+		keys = append(keys, "event="+event+","+mode)
+	}
+	for _, feature := range g.Features {
+		if g.HasFeature(feature) {
+			keys = append(keys, "event="+event+",feature="+feature)
+		}
+	}
+	return keys
+}
+
+// votingLabel is mode, or single for anything that isn't a voting mode.
+func votingLabel(mode string) string {
+	switch mode {
+	case VotingRanked, VotingJudge, VotingMulti:
+		return mode
+	}
+	return VotingSingle
+}
+
+// pairLabel labels an option that can be on for the play phase, the vote
+// phase, both or neither.
+func pairLabel(play, vote bool) string {
+	switch {
+	case play && vote:
+		return "both"
+	case play:
+		return "play"
+	case vote:
+		return "vote"
+	}
+	return "off"
+}
+
+type countBucket struct {
+	max   int
+	label string
+}
+
+var (
+	roundBuckets  = []countBucket{{3, "1-3"}, {6, "4-6"}, {10, "7-10"}}
+	playerBuckets = []countBucket{{1, "1"}, {3, "2-3"}, {6, "4-6"}}
+)
+
+// bucket labels n with the first bucket it fits, or the last bucket's
+// successor, e.g. "11+".
+func bucket(n int, buckets []countBucket) string {
+	for _, b := range buckets {
+		if n <= b.max {
+			return b.label
+		}
+	}
+	return strconv.Itoa(buckets[len(buckets)-1].max+1) + "+"
+}
@@ -0,0 +1,51 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stinkyfingers/differencebetween/api/flags"
+	"github.com/stinkyfingers/differencebetween/api/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsageKeys(t *testing.T) {
+	defer config.Set(config.Current())
+	config.Set(&config.Config{Features: map[string]bool{flags.CzarMode: true}})
+
+	g := &Game{
+		Players:     []Player{{Name: "al"}, {Name: "bob"}, {Name: "carl"}, {Name: "dee"}},
+		Rounds:      make([]Round, 12),
+		Cleanliness: "PG",
+		Features:    []string{flags.CzarMode, flags.Chaos},
+		VotingMode:  VotingJudge,
+		PlaySeconds: 60,
+		AutoPlay:    true,
+		AutoVote:    true,
+	}
+	assert.Equal(t, []string{
+		"event=finished,cleanliness=PG,rounds=11+,players=4-6",
+		"event=finished,voting=judge",
+		"event=finished,timers=play",
+		"event=finished,auto=both",
+		"event=finished,feature=czar_mode",
+	}, usageKeys(usageFinished, g))
+	assert.Equal(t, []string{
+		"event=created,cleanliness=PG,rounds=11+",
+		"event=created,voting=single",
+		"event=created,timers=off",
+		"event=created,auto=off",
+	}, usageKeys(usageCreated, &Game{Players: g.Players[:1], Rounds: g.Rounds, Cleanliness: "PG"}))
+
+	before := metrics.UsageCount("event=finished,feature=czar_mode")
+	recordUsage(usageFinished, g)
+	assert.Equal(t, before+1, metrics.UsageCount("event=finished,feature=czar_mode"))
+}
+
+func TestBucket(t *testing.T) {
+	for n, label := range map[int]string{1: "1-3", 3: "1-3", 4: "4-6", 10: "7-10", 11: "11+"} {
+		assert.Equal(t, label, bucket(n, roundBuckets))
+	}
+	assert.Equal(t, "1", bucket(1, playerBuckets))
+	assert.Equal(t, "7+", bucket(9, playerBuckets))
+}
@@ -0,0 +1,135 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+)
+
+var (
+	// ErrInvalidRounds is wrapped by ValidateRounds' errors, which give the
+	// accepted range.
+	ErrInvalidRounds = errors.New("invalid number of rounds")
+	// ErrTooManyPlayers is wrapped by the error for a WithMaxPlayers the
+	// decks can't deal full hands to, which gives the most they can.
+	ErrTooManyPlayers = errors.New("max players is more than the deck can deal hands to")
+)
+
+// Settings are the parts of a new game that ValidateGameSettings checks.
+type Settings struct {
+	Rounds      int
+	Cleanliness string
+	Options     []Option
+}
+
+// Capacity is the largest game a pair of decks supports.
+type Capacity struct {
+	MaxRounds  int `json:"maxRounds"`
+	MaxPlayers int `json:"maxPlayers"` // with full hands and no replenishing
+}
+
+// Violations lists every problem ValidateGameSettings found.
+type Violations []error
+
+func (v Violations) Error() string {
+	messages := make([]string, len(v))
+	for i, err := range v {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ValidateRounds checks a new game's rounds against 1 and the configured
+// MaxRounds.
+func ValidateRounds(rounds int) error {
+	max := config.Current().MaxRounds
+	if rounds < 1 || rounds > max {
+		return fmt.Errorf("%w: must be between 1 and %d", ErrInvalidRounds, max)
+	}
+	return nil
+}
+
+// maxRounds is how many rounds a game can deal from setups cards,
+// setupSize a round.
+func maxRounds(setups, setupSize int) int {
+	return setups / setupSize
+}
+
+// maxPlayers is how many full hands of handSize a game can deal from
+// punchlines cards.
+func maxPlayers(punchlines, handSize int) int {
+	return punchlines / handSize
+}
+
+// ValidateGameSettings runs NewGame's checks without creating a game,
+// returning the decks' capacity and, if the settings won't work,
+// Violations. The server's decks are checked as last loaded rather than
+// fetched again where possible.
+func ValidateGameSettings(ctx context.Context, s Settings) (Capacity, error) {
+	g := &Game{RoundsRemaining: s.Rounds, HandSize: defaultHandSize, source: cardSource}
+	for _, opt := range s.Options {
+		opt(g)
+	}
+	var violations Violations
+	roundsErr := g.setRounds(s.Rounds)
+	if roundsErr != nil {
+		violations = append(violations, roundsErr)
+	}
+	setups, setupsErr := cachedCards(ctx, g.source, SetupDeck, s.Cleanliness)
+	if setupsErr != nil {
+		violations = append(violations, setupsErr)
+	}
+	punchlines, punchlinesErr := cachedCards(ctx, g.source, PunchlineDeck, s.Cleanliness)
+	if punchlinesErr != nil && punchlinesErr != setupsErr {
+		violations = append(violations, punchlinesErr)
+	}
+	if setupsErr != nil || punchlinesErr != nil {
+		return Capacity{}, violations
+	}
+	capacity := deckCapacity(setups, punchlines, g.HandSize, g.setupSize())
+	rounds := g.RoundsRemaining
+	if roundsErr != nil {
+		rounds = 0 // already reported
+	}
+	violations = append(violations, capacity.check(rounds, g.MaxPlayers)...)
+	if len(violations) > 0 {
+		return capacity, violations
+	}
+	return capacity, nil
+}
+
+func deckCapacity(setups, punchlines []Card, handSize, setupSize int) Capacity {
+	return Capacity{MaxRounds: maxRounds(len(setups), setupSize), MaxPlayers: maxPlayers(len(punchlines), handSize)}
+}
+
+// check returns the problems creating a game of rounds would run into,
+// capped at maxPlayers if not 0.
+func (c Capacity) check(rounds, maxPlayers int) Violations {
+	var violations Violations
+	if rounds > c.MaxRounds {
+		violations = append(violations, ErrTooFewSetups)
+	}
+	if c.MaxPlayers < 1 {
+		violations = append(violations, ErrTooFewPunchlines)
+	} else if maxPlayers > c.MaxPlayers {
+		violations = append(violations, fmt.Errorf("%w: at most %d", ErrTooManyPlayers, c.MaxPlayers))
+	}
+	return violations
+}
+
+// cachedCards is getCards, except the server's decks come from loadedDecks
+// once they've loaded.
+func cachedCards(ctx context.Context, source CardSource, deck Deck, cleanliness string) ([]Card, error) {
+	if source == cardSource {
+		loadedDecks.mu.Lock()
+		rated, ok := loadedDecks.decks[deck]
+		loadedDecks.mu.Unlock()
+		if ok {
+			return filterCards(rated, cleanliness)
+		}
+	}
+	return getCards(ctx, source, deck, cleanliness)
+}
@@ -0,0 +1,82 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateGameSettings(t *testing.T) {
+	source := &staticSource{}
+	for _, text := range []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13"} {
+		*source = append(*source, RatedCard{Text: text, Rating: "G"})
+	}
+	*source = append(*source, RatedCard{Text: "dirty", Rating: "R"}, RatedCard{Text: "filthy", Rating: "R"})
+	count := Count()
+
+	tests := []struct {
+		settings Settings
+		capacity Capacity
+		err      error
+	}{
+		{Settings{Rounds: 6, Cleanliness: "PG"}, Capacity{MaxRounds: 6, MaxPlayers: 2}, nil},
+		{Settings{Rounds: 7, Cleanliness: "PG"}, Capacity{MaxRounds: 6, MaxPlayers: 2}, Violations{ErrTooFewSetups}},
+		{Settings{Rounds: 7, Cleanliness: "R"}, Capacity{MaxRounds: 7, MaxPlayers: 2}, nil},
+		{Settings{Rounds: 1, Cleanliness: "NC-17"}, Capacity{}, Violations{ErrMalformedCSV}},
+	}
+	for _, test := range tests {
+		test.settings.Options = []Option{WithCardSource(source)}
+		capacity, err := ValidateGameSettings(context.Background(), test.settings)
+		assert.Equal(t, test.capacity, capacity)
+		assert.Equal(t, test.err, err)
+	}
+
+	small := &staticSource{{Text: "1", Rating: "G"}, {Text: "2", Rating: "G"}}
+	capacity, err := ValidateGameSettings(context.Background(), Settings{Rounds: 2, Cleanliness: "R", Options: []Option{WithCardSource(small)}})
+	assert.Equal(t, Capacity{MaxRounds: 1}, capacity)
+	assert.Equal(t, Violations{ErrTooFewSetups, ErrTooFewPunchlines}, err)
+	assert.Equal(t, "not enough setup cards; not enough punchline cards", err.Error())
+
+	// NewGame fails the same way
+	_, err = NewGame(context.Background(), Player{Name: "al"}, 2, "R", WithCardSource(small))
+	assert.Equal(t, ErrTooFewSetups, err)
+
+	// a player cap the deck can't deal hands to is reported, not lowered
+	capacity, err = ValidateGameSettings(context.Background(), Settings{Rounds: 7, Cleanliness: "PG", Options: []Option{WithCardSource(source), WithMaxPlayers(3)}})
+	assert.Equal(t, Capacity{MaxRounds: 6, MaxPlayers: 2}, capacity)
+	if assert.Len(t, err, 2) {
+		assert.Equal(t, ErrTooFewSetups, err.(Violations)[0])
+		assert.True(t, errors.Is(err.(Violations)[1], ErrTooManyPlayers))
+		assert.Equal(t, "max players is more than the deck can deal hands to: at most 2", err.(Violations)[1].Error())
+	}
+	_, err = NewGame(context.Background(), Player{Name: "al"}, 6, "PG", WithCardSource(source), WithMaxPlayers(3))
+	assert.True(t, errors.Is(err, ErrTooManyPlayers))
+	assert.Equal(t, count, Count(), "validating creates nothing")
+}
+
+func TestValidateRounds(t *testing.T) {
+	defer config.Set(config.Current())
+	cfg := *config.Current()
+	cfg.MaxRounds = 10
+	config.Set(&cfg)
+	failing := &staticSource{{Text: "bad", Rating: "NC-17"}}
+
+	assert.NoError(t, ValidateRounds(1))
+	assert.NoError(t, ValidateRounds(10))
+	for _, rounds := range []int{-1, 0, 11} {
+		err := ValidateRounds(rounds)
+		assert.True(t, errors.Is(err, ErrInvalidRounds), rounds)
+		assert.Equal(t, "invalid number of rounds: must be between 1 and 10", err.Error())
+
+		_, err = NewGame(context.Background(), Player{Name: "al"}, rounds, "R", WithCardSource(failing))
+		assert.True(t, errors.Is(err, ErrInvalidRounds), "checked before the decks are loaded")
+		_, err = ValidateGameSettings(context.Background(), Settings{Rounds: rounds, Cleanliness: "R", Options: []Option{WithCardSource(failing)}})
+		if assert.Len(t, err, 2, "the decks are still checked") {
+			assert.True(t, errors.Is(err.(Violations)[0], ErrInvalidRounds))
+			assert.Equal(t, ErrMalformedCSV, err.(Violations)[1])
+		}
+	}
+}
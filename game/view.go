@@ -0,0 +1,254 @@
+package game
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// GameView is the game as one player or spectator sees it, see ViewFor.
+// Nobody sees the deck or anyone else's hand, and until a round is settled
+// each viewer only sees their own play and vote in it.
+type GameView struct {
+	ID              int           `json:"id"`
+	Code            string        `json:"code"`
+	Host            string        `json:"host"`
+	Players         []PlayerView  `json:"players"`
+	Spectators      []string      `json:"spectators"`
+	DeckSize        int           `json:"deckSize"` // punchlines left to deal
+	Rounds          []RoundView   `json:"rounds"`
+	RoundsRemaining int           `json:"roundsRemaining"`
+	TargetScore     int           `json:"targetScore,omitempty"`
+	RoundLimit      int           `json:"roundLimit,omitempty"`
+	CurrentAction   string        `json:"currentAction"`
+	Finished        bool          `json:"finished"`
+	FinalWinners    []string      `json:"winners"`
+	TieBreak        string        `json:"tieBreak"`
+	TieBrokenBy     string        `json:"tieBrokenBy,omitempty"`
+	SuddenDeath     []string      `json:"suddenDeath,omitempty"`
+	Unready         []string      `json:"unready,omitempty"`
+	Cleanliness     string        `json:"cleanliness"`
+	Features        []string      `json:"features,omitempty"`
+	Replenish       bool          `json:"replenish"`
+	AutoPlay        bool          `json:"autoPlay"`
+	AutoVote        bool          `json:"autoVote"`
+	Locale          string        `json:"locale"`
+	League          string        `json:"league,omitempty"`
+	Session         string        `json:"session,omitempty"`
+	VotingMode      string        `json:"votingMode"`
+	RankCount       int           `json:"rankCount,omitempty"`
+	VotesPerPlayer  int           `json:"votesPerPlayer,omitempty"`
+	WriteIns        int           `json:"writeIns,omitempty"`
+	SetupSize       int           `json:"setupSize,omitempty"`
+	PairPlay        bool          `json:"pairPlay,omitempty"`
+	AudienceWeight  float64       `json:"audienceWeight,omitempty"`
+	AudiencePooled  bool          `json:"audiencePooled,omitempty"`
+	HandSize        int           `json:"handSize"`
+	MinPlayers      int           `json:"minPlayers"`
+	MaxPlayers      int           `json:"maxPlayers"`
+	PlaySeconds     int           `json:"playSeconds"`
+	VoteSeconds     int           `json:"voteSeconds"`
+	PhaseDeadline   *time.Time    `json:"phaseDeadline,omitempty"`
+	SkipsUsed       int           `json:"skipsUsed"`
+	RematchID       *int          `json:"rematchId,omitempty"`
+	Teams           bool          `json:"teams,omitempty"`
+	Paused          bool          `json:"paused,omitempty"`
+	PausedBy        string        `json:"pausedBy,omitempty"`
+	PausedAt        *time.Time    `json:"pausedAt,omitempty"`
+	Messages        []ChatMessage `json:"messages,omitempty"`
+	Scoreboard      []PlayerScore `json:"scoreboard"`
+	TeamScoreboard  []TeamScore   `json:"teamScoreboard,omitempty"`
+}
+
+// PlayerView is a player as ViewFor shows them. Punchlines is only set for
+// the viewer; everyone else's hand is just counted.
+type PlayerView struct {
+	Name          string    `json:"name"`
+	Punchlines    []Card    `json:"punchlines,omitempty"`
+	Cards         int       `json:"cards"` // in their hand
+	Ready         bool      `json:"ready,omitempty"`
+	Score         int       `json:"score"`
+	Mulliganed    bool      `json:"mulliganed"`
+	WriteIns      int       `json:"writeIns,omitempty"` // left to play
+	Connected     bool      `json:"connected"`
+	LastSeen      time.Time `json:"lastSeen"`
+	JoinedAtRound int       `json:"joinedAtRound,omitempty"`
+	Waiting       bool      `json:"waiting,omitempty"`
+	Team          string    `json:"team,omitempty"`
+}
+
+// RoundView is a round as ViewFor shows it. A settled round is shown in
+// full. Until then Plays, Votes and Rankings hold only the viewer's own, and
+// Played and Voted say who else has.
+type RoundView struct {
+	plainRound
+	Played map[string]bool `json:"played,omitempty"`
+	Voted  map[string]bool `json:"voted,omitempty"`
+}
+
+// ViewFor returns the game as viewer should see it, with their hand in the
+// given order. Spectators, or anyone else not playing, see no hands at all.
+func (g *Game) ViewFor(viewer, order string) GameView {
+	g.mutex().lockToRead()
+	defer g.mutex().Unlock()
+	return g.viewFor(viewer, order)
+}
+
+// viewCache holds marshaled views of one version of a game, see ViewJSON.
+type viewCache struct {
+	version uint64
+	views   map[viewKey][]byte
+}
+
+type viewKey struct {
+	viewer, order string
+}
+
+// ViewJSON is ViewFor marshaled. Views are kept until the game's lock is
+// next taken to change it, so pushing an unchanged game to every client
+// doesn't marshal it again. The result is shared and must not be modified.
+func (g *Game) ViewJSON(viewer, order string) ([]byte, error) {
+	lock := g.mutex()
+	lock.lockToRead()
+	defer lock.Unlock()
+	if g.views == nil || g.views.version != lock.version {
+		g.views = &viewCache{version: lock.version, views: make(map[viewKey][]byte)}
+	}
+	key := viewKey{viewer, order}
+	if j, ok := g.views.views[key]; ok {
+		return j, nil
+	}
+	j, err := json.Marshal(g.viewFor(viewer, order))
+	if err != nil {
+		return nil, err
+	}
+	g.views.views[key] = j
+	return j, nil
+}
+
+// viewFor is ViewFor for callers holding the game's lock.
+func (g *Game) viewFor(viewer, order string) GameView {
+	view := GameView{
+		ID:              g.ID,
+		Code:            g.Code,
+		Host:            g.host(),
+		Players:         make([]PlayerView, len(g.Players)),
+		Spectators:      g.Spectators,
+		DeckSize:        len(g.Punchlines),
+		Rounds:          make([]RoundView, len(g.Rounds)),
+		RoundsRemaining: g.RoundsRemaining,
+		TargetScore:     g.TargetScore,
+		RoundLimit:      g.RoundLimit,
+		CurrentAction:   g.CurrentAction,
+		Finished:        g.Finished,
+		FinalWinners:    g.FinalWinners,
+		TieBreak:        g.TieBreak,
+		TieBrokenBy:     g.TieBrokenBy,
+		SuddenDeath:     g.SuddenDeath,
+		Unready:         g.Unready,
+		Cleanliness:     g.Cleanliness,
+		Features:        g.Features,
+		Replenish:       g.Replenish,
+		AutoPlay:        g.AutoPlay,
+		AutoVote:        g.AutoVote,
+		Locale:          g.Locale,
+		League:          g.League,
+		Session:         g.Session,
+		VotingMode:      g.VotingMode,
+		RankCount:       g.RankCount,
+		VotesPerPlayer:  g.VotesPerPlayer,
+		WriteIns:        g.WriteIns,
+		SetupSize:       g.SetupSize,
+		PairPlay:        g.PairPlay,
+		AudienceWeight:  g.AudienceWeight,
+		AudiencePooled:  g.AudiencePooled,
+		HandSize:        g.HandSize,
+		MinPlayers:      g.MinPlayers,
+		MaxPlayers:      g.MaxPlayers,
+		PlaySeconds:     g.PlaySeconds,
+		VoteSeconds:     g.VoteSeconds,
+		PhaseDeadline:   g.PhaseDeadline,
+		SkipsUsed:       g.SkipsUsed,
+		RematchID:       g.RematchID,
+		Teams:           g.Teams,
+		Paused:          g.Paused,
+		PausedBy:        g.PausedBy,
+		PausedAt:        g.PausedAt,
+		Messages:        g.Messages,
+		Scoreboard:      g.scoreboard(),
+		TeamScoreboard:  g.teamScoreboard(),
+	}
+	for i, player := range g.Players {
+		view.Players[i] = PlayerView{
+			Name:          player.Name,
+			Cards:         len(player.Punchlines),
+			Ready:         player.Ready,
+			Score:         player.Score,
+			Mulliganed:    player.Mulliganed,
+			WriteIns:      player.WriteInsRemaining,
+			Connected:     player.Connected,
+			LastSeen:      player.LastSeen,
+			JoinedAtRound: player.JoinedAtRound,
+			Waiting:       player.Waiting,
+			Team:          player.Team,
+		}
+		if player.Name == viewer {
+			view.Players[i].Punchlines = sortHand(player.Punchlines, order)
+		}
+	}
+	for i, round := range g.Rounds {
+		view.Rounds[i] = round.viewFor(viewer)
+	}
+	return view
+}
+
+// sortHand returns a copy of hand in the given order.
+func sortHand(hand []Card, order string) []Card {
+	sorted := make([]Card, len(hand))
+	copy(sorted, hand)
+	if order == HandAlpha {
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	}
+	return sorted
+}
+
+func (round Round) viewFor(viewer string) RoundView {
+	view := RoundView{plainRound: plainRound(round)}
+	if round.resolved {
+		_, view.Played = ownEntry(round.Plays, viewer)
+		return view
+	}
+	view.Plays, view.Played = ownEntry(round.Plays, viewer)
+	view.Votes, view.Voted = ownEntry(round.Votes, viewer)
+	view.AudienceVotes, _ = ownEntry(round.AudienceVotes, viewer)
+	view.Rankings = nil
+	if picks, ok := round.Rankings[viewer]; ok {
+		view.Rankings = map[string][]Card{viewer: picks}
+	}
+	view.WriteIns = nil
+	if round.WriteIns[viewer] {
+		view.WriteIns = map[string]bool{viewer: true}
+	}
+	view.Pairs = nil
+	if pair, ok := round.Pairs[viewer]; ok {
+		view.Pairs = map[string][]Card{viewer: pair}
+	}
+	return view
+}
+
+// ownEntry returns viewer's entry alone from m, which maps players to their
+// card, and who has one.
+func ownEntry(m map[string]Card, viewer string) (map[string]Card, map[string]bool) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	who := make(map[string]bool, len(m))
+	for name := range m {
+		who[name] = true
+	}
+	card, ok := m[viewer]
+	if !ok {
+		return nil, who
+	}
+	return map[string]Card{viewer: card}, who
+}
@@ -0,0 +1,177 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewFor(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3"}},
+			{Name: "carl", Punchlines: []Card{"c1", "c2", "c3"}},
+		},
+		Spectators:      []string{"sam"},
+		Punchlines:      []Card{"d1", "d2", "d3", "d4", "d5", "d6"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	fetch := func(viewer string) string {
+		j, err := json.Marshal(g.ViewFor(viewer, HandDealt))
+		assert.NoError(t, err)
+		return string(j)
+	}
+
+	view := g.ViewFor("al", HandDealt)
+	assert.Equal(t, []Card{"a1", "a2", "a3"}, view.Players[0].Punchlines)
+	assert.Nil(t, view.Players[1].Punchlines)
+	assert.Equal(t, 3, view.Players[1].Cards)
+	assert.Equal(t, 6, view.DeckSize)
+	for _, hidden := range []string{"b1", "c1", "d1"} {
+		assert.NotContains(t, fetch("al"), hidden)
+	}
+	for _, viewer := range []string{"sam", "nobody"} {
+		for _, hidden := range []string{"a1", "b1", "c1", "d1"} {
+			assert.NotContains(t, fetch(viewer), hidden, viewer)
+		}
+	}
+
+	// during the play phase, only your own play shows
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	round := g.ViewFor("al", HandDealt).Rounds[1]
+	assert.Equal(t, map[string]Card{"al": "a1"}, round.Plays)
+	assert.Equal(t, map[string]bool{"al": true, "bob": true}, round.Played)
+	assert.NotContains(t, fetch("al"), "b1")
+	assert.NotContains(t, fetch("carl"), "a1")
+	assert.NotContains(t, fetch("sam"), "a1")
+
+	// during the vote phase the cards are on the ballot, but not who played
+	// them or who voted for what
+	assert.NoError(t, g.Play("carl", "c1"))
+	assert.NoError(t, g.Vote("al", "b1"))
+	round = g.ViewFor("bob", HandDealt).Rounds[1]
+	assert.ElementsMatch(t, []Card{"a1", "b1", "c1"}, round.Ballot)
+	assert.Equal(t, map[string]Card{"bob": "b1"}, round.Plays)
+	assert.Nil(t, round.Votes)
+	assert.Equal(t, map[string]bool{"al": true}, round.Voted)
+	assert.Equal(t, map[string]Card{"al": "b1"}, g.ViewFor("al", HandDealt).Rounds[1].Votes)
+
+	// once settled, the round is shown in full
+	assert.NoError(t, g.Vote("bob", "c1"))
+	assert.NoError(t, g.Vote("carl", "b1"))
+	round = g.ViewFor("sam", HandDealt).Rounds[1]
+	assert.Equal(t, map[string]Card{"al": "a1", "bob": "b1", "carl": "c1"}, round.Plays)
+	assert.Equal(t, map[string]Card{"al": "b1", "bob": "c1", "carl": "b1"}, round.Votes)
+	assert.Equal(t, "bob", round.Winner)
+	assert.Len(t, g.ViewFor("sam", HandDealt).Scoreboard, 3)
+}
+
+// TestViewForWhilePlaying is for the race detector: the hub builds views
+// while other requests play the game.
+func TestViewForWhilePlaying(t *testing.T) {
+	source := &staticSource{}
+	for i := 0; i < 40; i++ {
+		*source = append(*source, RatedCard{Text: Card(fmt.Sprintf("card %d", i)), Rating: "G"})
+	}
+	g, err := NewGame(context.Background(), Player{Name: "al"}, 3, "R", WithCardSource(source), WithHandSize(3), WithMinPlayers(2))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	assert.NoError(t, g.AddPlayer(Player{Name: "bob"}, ""))
+	assert.NoError(t, g.Start("al", false))
+
+	done := make(chan struct{})
+	var started, wg sync.WaitGroup
+	for _, viewer := range []string{"al", "bob", "sam"} {
+		started.Add(1)
+		wg.Add(1)
+		go func(viewer string) {
+			defer wg.Done()
+			first := true
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				g.ViewFor(viewer, HandAlpha)
+				_, err := g.ViewJSON(viewer, HandDealt)
+				assert.NoError(t, err)
+				g.Observe()
+				g.Standings()
+				g.Summary()
+				_, err = json.Marshal(g)
+				assert.NoError(t, err)
+				_, err = g.Pacing("al")
+				assert.NoError(t, err)
+				if first {
+					started.Done()
+					first = false
+				}
+			}
+		}(viewer)
+	}
+	started.Wait()
+
+	for round := 0; round < 3; round++ {
+		for i, name := range []string{"al", "bob"} {
+			assert.NoError(t, g.Play(name, g.ViewFor(name, HandDealt).Players[i].Punchlines[0]))
+		}
+		for _, name := range []string{"al", "bob"} {
+			view := g.ViewFor(name, HandDealt)
+			current := view.Rounds[view.RoundsRemaining-1]
+			for _, card := range current.Ballot {
+				if card != current.Plays[name] {
+					assert.NoError(t, g.Vote(name, card))
+					break
+				}
+			}
+		}
+	}
+	close(done)
+	wg.Wait()
+	assert.True(t, g.ViewFor("al", HandDealt).Finished)
+}
+
+func TestViewJSON(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "cy", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   PLAY,
+	}
+	al, err := g.ViewJSON("al", HandDealt)
+	assert.NoError(t, err)
+	want, err := json.Marshal(g.ViewFor("al", HandDealt))
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(al))
+	again, err := g.ViewJSON("al", HandDealt)
+	assert.NoError(t, err)
+	assert.True(t, &al[0] == &again[0], "unchanged, so not marshaled again")
+	bob, err := g.ViewJSON("bob", HandDealt)
+	assert.NoError(t, err)
+	assert.NotEqual(t, string(al), string(bob), "each viewer's own")
+	sorted, err := g.ViewJSON("al", HandAlpha)
+	assert.NoError(t, err)
+	assert.False(t, &al[0] == &sorted[0], "each order's own")
+
+	assert.NoError(t, g.Play("al", "a1"))
+	played, err := g.ViewJSON("al", HandDealt)
+	assert.NoError(t, err)
+	assert.Contains(t, string(played), `"plays":{"al":"a1"}`, "changed by the play")
+	want, err = json.Marshal(g.ViewFor("al", HandDealt))
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(played))
+}
@@ -0,0 +1,137 @@
+package game
+
+import "errors"
+
+const (
+	VotingSingle = "single" // each voter picks one card
+	VotingRanked = "ranked" // each voter ranks up to RankCount cards
+	VotingJudge  = "judge"  // a judge, taking turns, picks one card, see WithJudge
+	VotingMulti  = "multi"  // each voter picks up to VotesPerPlayer cards, a point each
+
+	defaultRankCount      = 2
+	maxRankCount          = 5
+	defaultVotesPerPlayer = 2
+	maxVotesPerPlayer     = 5
+)
+
+var (
+	ErrInvalidRankCount      = errors.New("rank count must be between 2 and 5")
+	ErrInvalidVotesPerPlayer = errors.New("votes per player must be between 2 and 5")
+	ErrVoteCount             = errors.New("wrong number of votes")
+	ErrOwnCard               = errors.New("can't vote for your own card")
+	ErrDuplicateVote         = errors.New("can't vote for a card twice")
+)
+
+// WithRankedVoting has each voter rank up to rankCount cards, best first.
+// A card ranked first scores rankCount points, second rankCount-1, and so
+// on, and the round goes to the top scorer. Check rankCount with
+// ValidateRankCount; 0 means the default of 2.
+func WithRankedVoting(rankCount int) Option {
+	return func(g *Game) {
+		if rankCount == 0 {
+			rankCount = defaultRankCount
+		}
+		g.VotingMode, g.RankCount = VotingRanked, rankCount
+	}
+}
+
+func ValidateRankCount(rankCount int) error {
+	if rankCount != 0 && (rankCount < 2 || rankCount > maxRankCount) {
+		return ErrInvalidRankCount
+	}
+	return nil
+}
+
+// WithVotesPerPlayer has each voter pick up to votes different cards, none
+// their own, each scoring a point. Check votes with ValidateVotesPerPlayer;
+// 0 means the default of 2.
+func WithVotesPerPlayer(votes int) Option {
+	return func(g *Game) {
+		if votes == 0 {
+			votes = defaultVotesPerPlayer
+		}
+		g.VotingMode, g.VotesPerPlayer = VotingMulti, votes
+	}
+}
+
+func ValidateVotesPerPlayer(votes int) error {
+	if votes != 0 && (votes < 2 || votes > maxVotesPerPlayer) {
+		return ErrInvalidVotesPerPlayer
+	}
+	return nil
+}
+
+// multipleVotes reports whether voters can pick more than one card, kept in
+// the round's Rankings.
+func (g *Game) multipleVotes() bool {
+	return g.VotingMode == VotingRanked || g.VotingMode == VotingMulti
+}
+
+// maxVotes is how many cards each voter may pick.
+func (g *Game) maxVotes() int {
+	switch g.VotingMode {
+	case VotingRanked:
+		return g.RankCount
+	case VotingMulti:
+		return g.VotesPerPlayer
+	}
+	return 1
+}
+
+// checkVote validates a vote for the game's voting mode. Every card must be
+// on the ballot, and ranked and multiple votes may not include the voter's
+// own card or the same card twice. In team games no card may be a
+// teammate's.
+func (g *Game) checkVote(round Round, playerName string, cards []Card) error {
+	if !g.multipleVotes() {
+		if len(cards) != 1 {
+			return ErrVoteCount
+		}
+		if !round.onBallot(cards[0]) {
+			return ErrNotOnBallot
+		}
+		return g.checkTeammate(round, playerName, cards[0])
+	}
+	if len(cards) == 0 || len(cards) > g.maxVotes() {
+		return ErrVoteCount
+	}
+	seen := make(map[Card]bool)
+	for _, card := range cards {
+		if !round.onBallot(card) {
+			return ErrNotOnBallot
+		}
+		if own, ok := round.Plays[playerName]; ok && card == own {
+			return ErrOwnCard
+		}
+		if seen[card] {
+			return ErrDuplicateVote
+		}
+		if err := g.checkTeammate(round, playerName, card); err != nil {
+			return err
+		}
+		seen[card] = true
+	}
+	return nil
+}
+
+// multiTallies counts a point for each pick in a multiple vote round.
+func multiTallies(round Round) map[Card]int {
+	tallies := make(map[Card]int)
+	for _, cards := range round.Rankings {
+		for _, card := range cards {
+			tallies[card]++
+		}
+	}
+	return tallies
+}
+
+// rankedTallies sums the weighted points each card got in a ranked round.
+func (g *Game) rankedTallies(round Round) map[Card]int {
+	tallies := make(map[Card]int)
+	for _, cards := range round.Rankings {
+		for i, card := range cards {
+			tallies[card] += g.RankCount - i
+		}
+	}
+	return tallies
+}
@@ -0,0 +1,96 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankedVoting(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+		Rounds:          make([]Round, 2),
+		RoundsRemaining: 2,
+		CurrentAction:   VOTE,
+	}
+	assert.Equal(t, ErrVoteCount, g.Vote("al", "b1", "c1"), "single vote by default")
+	g.CurrentAction = PLAY
+	WithRankedVoting(0)(g)
+	assert.Equal(t, 2, g.RankCount)
+
+	assert.NoError(t, g.Play("al", "a1"))
+	assert.NoError(t, g.Play("bob", "b1"))
+	assert.NoError(t, g.Play("carl", "c1"))
+	assert.Equal(t, ErrOwnCard, g.Vote("al", "b1", "a1"))
+	assert.Equal(t, ErrDuplicateVote, g.Vote("al", "b1", "b1"))
+	assert.Equal(t, ErrVoteCount, g.Vote("al", "b1", "c1", "a1"))
+	assert.Equal(t, ErrVoteCount, g.Vote("al"))
+	assert.Empty(t, g.Rounds[1].Votes)
+
+	// c1 has the most first choices, but b1 the most points
+	assert.NoError(t, g.Vote("al", "c1", "b1"))
+	assert.NoError(t, g.Vote("bob", "c1", "a1"))
+	assert.NoError(t, g.Vote("carl", "b1", "a1"))
+	round := g.Rounds[1]
+	assert.Equal(t, map[Card]int{"a1": 2, "b1": 3, "c1": 4}, round.Tallies)
+	assert.Equal(t, "carl", round.Winner)
+	assert.Equal(t, Card("c1"), round.Votes["al"])
+	assert.Equal(t, []Card{"c1", "b1"}, round.Rankings["al"])
+
+	// ties go to nobody, as with single votes
+	assert.NoError(t, g.Play("al", "a2"))
+	assert.NoError(t, g.Play("bob", "b2"))
+	assert.NoError(t, g.Play("carl", "c2"))
+	assert.NoError(t, g.Vote("al", "b2", "c2"))
+	assert.NoError(t, g.Vote("bob", "c2", "a2"))
+	assert.NoError(t, g.Vote("carl", "a2"))
+	assert.Equal(t, map[Card]int{"a2": 3, "b2": 2, "c2": 3}, g.Rounds[0].Tallies)
+	assert.Equal(t, "", g.Rounds[0].Winner)
+	assert.Equal(t, 0, g.RoundsRemaining)
+}
+
+func TestMultiVoting(t *testing.T) {
+	g := &Game{
+		Players: []Player{
+			{Name: "al", Punchlines: []Card{"a1", "a2", "a3", "a4", "a5", "a6"}},
+			{Name: "bob", Punchlines: []Card{"b1", "b2", "b3", "b4", "b5", "b6"}},
+			{Name: "carl", Punchlines: []Card{"c1", "c2", "c3", "c4", "c5", "c6"}},
+			{Name: "dan", Punchlines: []Card{"d1", "d2", "d3", "d4", "d5", "d6"}},
+		},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+		Rounds:          make([]Round, 1),
+		RoundsRemaining: 1,
+		CurrentAction:   PLAY,
+	}
+	WithVotesPerPlayer(0)(g)
+	assert.Equal(t, VotingMulti, g.VotingMode)
+	assert.Equal(t, 2, g.VotesPerPlayer)
+	assert.Equal(t, ErrInvalidVotesPerPlayer, ValidateVotesPerPlayer(1))
+	assert.Equal(t, ErrInvalidVotesPerPlayer, ValidateVotesPerPlayer(6))
+	assert.NoError(t, ValidateVotesPerPlayer(3))
+
+	for _, name := range []string{"al", "bob", "carl", "dan"} {
+		assert.NoError(t, g.Play(name, Card(name[:1]+"1")))
+	}
+	assert.Equal(t, ErrOwnCard, g.Vote("al", "b1", "a1"))
+	assert.Equal(t, ErrDuplicateVote, g.Vote("al", "b1", "b1"), "two picks are two different cards")
+	assert.Equal(t, ErrVoteCount, g.Vote("al", "b1", "c1", "d1"))
+	assert.Empty(t, g.Rounds[0].Votes)
+
+	// every pick is worth the same, so b1 and c1 tie on first choices but
+	// c1 wins on picks
+	assert.NoError(t, g.Vote("al", "b1", "c1"))
+	assert.NoError(t, g.Vote("bob", "c1", "d1"))
+	assert.NoError(t, g.Vote("carl", "b1"), "fewer picks are fine")
+	assert.Equal(t, []Card{"b1", "c1"}, g.Rounds[0].Rankings["al"])
+	assert.NoError(t, g.Vote("dan", "c1", "a1"))
+	round := g.Rounds[0]
+	assert.Equal(t, map[Card]int{"a1": 1, "b1": 2, "c1": 3, "d1": 1}, round.Tallies)
+	assert.Equal(t, "carl", round.Winner)
+	assert.True(t, g.Finished)
+}
@@ -0,0 +1,214 @@
+package game
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stinkyfingers/differencebetween/api/metrics"
+)
+
+// SignatureHeader carries "t=<unix seconds>,v1=<hex HMAC-SHA256>" on webhook
+// deliveries. The MAC is over the timestamp, a dot, and the exact body, so a
+// receiver can reject replays by checking the timestamp is recent.
+const SignatureHeader = "X-Differencebetween-Signature"
+
+var (
+	ErrNoWebhook      = errors.New("game has no webhook")
+	ErrInvalidWebhook = errors.New("webhook must be an http or https url")
+
+	webhookClient      = &http.Client{Timeout: time.Second * 10, Transport: publicTransport}
+	webhookRetryDelays = []time.Duration{0, time.Second * 10, time.Minute}
+)
+
+// Result is the payload delivered to a game's webhook when it finishes.
+type Result struct {
+	GameID          int        `json:"gameId"`
+	League          string     `json:"league,omitempty"`
+	Standings       []Standing `json:"standings"`
+	Winners         []string   `json:"winners"`
+	TieBrokenBy     string     `json:"tieBrokenBy,omitempty"` // see Game.TieBrokenBy
+	TranscriptURL   string     `json:"transcriptUrl,omitempty"`
+	DurationSeconds float64    `json:"durationSeconds"`
+	Finished        time.Time  `json:"finished"`
+}
+
+type Standing struct {
+	Player        string `json:"player"`
+	RoundsWon     int    `json:"roundsWon"`               // including ties, see PlayerScore.Score
+	JoinedAtRound int    `json:"joinedAtRound,omitempty"` // for late joiners, see Player
+}
+
+// Delivery records one attempt at delivering a game's result.
+type Delivery struct {
+	Attempt    int       `json:"attempt"`
+	Time       time.Time `json:"time"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	LatencyMS  int64     `json:"latencyMs"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type webhook struct {
+	url string
+
+	mu         sync.Mutex
+	deliveries []Delivery
+}
+
+// WithWebhook has the game's result delivered to rawURL when it finishes.
+func WithWebhook(rawURL string) Option {
+	return func(g *Game) {
+		g.webhook = &webhook{url: rawURL}
+	}
+}
+
+// ValidateWebhook checks rawURL can be used with WithWebhook. Deliveries
+// only go to public addresses, see publicTransport.
+func ValidateWebhook(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ErrInvalidWebhook
+	}
+	return checkPublicURL(u, ErrInvalidWebhook)
+}
+
+// Standings ranks the game's players as the Scoreboard does, so rounds won
+// include those they tied for.
+func (g *Game) Standings() []Standing {
+	g.mutex().lockToRead()
+	defer g.mutex().Unlock()
+	return g.standings()
+}
+
+func (g *Game) standings() []Standing {
+	joined := make(map[string]int, len(g.Players))
+	for _, player := range g.Players {
+		joined[player.Name] = player.JoinedAtRound
+	}
+	standings := make([]Standing, 0, len(g.Players))
+	for _, score := range g.scoreboard() {
+		if !score.Left {
+			standings = append(standings, Standing{Player: score.Name, RoundsWon: score.Score, JoinedAtRound: joined[score.Name]})
+		}
+	}
+	return standings
+}
+
+func (g *Game) result() Result {
+	finished := now()
+	r := Result{
+		GameID:          g.ID,
+		League:          g.League,
+		Standings:       g.standings(),
+		Winners:         append([]string{}, g.FinalWinners...),
+		TieBrokenBy:     g.TieBrokenBy,
+		DurationSeconds: finished.Sub(g.Created).Seconds(),
+		Finished:        finished,
+	}
+	if base := config.Current().PublicURL; base != "" {
+		r.TranscriptURL = fmt.Sprintf("%s/games/%d/transcript", base, g.ID)
+	}
+	return r
+}
+
+// deliverResult sends the game's result to its webhook in the background,
+// retrying failures after each of webhookRetryDelays.
+func (g *Game) deliverResult() {
+	if g.webhook == nil {
+		return
+	}
+	body, err := json.Marshal(g.result())
+	if err != nil {
+		metrics.CountError("webhook")
+		return
+	}
+	go func() {
+		for _, delay := range webhookRetryDelays {
+			time.Sleep(delay)
+			if g.webhook.deliver(body).Error == "" {
+				return
+			}
+		}
+	}()
+}
+
+// Deliveries returns the attempts made to deliver the game's result.
+func (g *Game) Deliveries() []Delivery {
+	if g.webhook == nil {
+		return nil
+	}
+	g.webhook.mu.Lock()
+	defer g.webhook.mu.Unlock()
+	return append([]Delivery(nil), g.webhook.deliveries...)
+}
+
+// Redeliver makes one more attempt at delivering the finished game's result.
+func (g *Game) Redeliver() (Delivery, error) {
+	if g.webhook == nil {
+		return Delivery{}, ErrNoWebhook
+	}
+	g.mutex().Lock()
+	body, err := json.Marshal(g.result())
+	g.mutex().Unlock()
+	if err != nil {
+		return Delivery{}, err
+	}
+	return g.webhook.deliver(body), nil
+}
+
+func (w *webhook) deliver(body []byte) Delivery {
+	w.mu.Lock()
+	d := Delivery{Attempt: len(w.deliveries) + 1, Time: now()}
+	w.mu.Unlock()
+
+	start := time.Now()
+	resp, err := w.post(body, d.Time)
+	d.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		d.Error = err.Error()
+	} else {
+		resp.Body.Close()
+		d.StatusCode = resp.StatusCode
+		if resp.StatusCode >= 300 {
+			d.Error = resp.Status
+		}
+	}
+	if d.Error != "" {
+		metrics.CountError("webhook")
+	}
+
+	w.mu.Lock()
+	w.deliveries = append(w.deliveries, d)
+	w.mu.Unlock()
+	return d
+}
+
+func (w *webhook) post(body []byte, t time.Time) (*http.Response, error) {
+	req, err := http.NewRequest("POST", w.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, "t="+timestamp+",v1="+SignWebhook([]byte(config.Current().WebhookSecret), timestamp, body))
+	return webhookClient.Do(req)
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of a delivery, for receivers to
+// compare against SignatureHeader.
+func SignWebhook(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,108 @@
+package game
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookDelivery(t *testing.T) {
+	defer config.Set(config.Current())
+	cfg := *config.Current()
+	cfg.WebhookSecret, cfg.PublicURL = "shh", "https://api.example.com"
+	cfg.FetchAllowedHosts = []string{"127.0.0.1"}
+	config.Set(&cfg)
+	savedDelays := webhookRetryDelays
+	defer func() { webhookRetryDelays = savedDelays }()
+	webhookRetryDelays = []time.Duration{0, 0, 0}
+
+	var calls int32
+	received := make(chan Result, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		parts := strings.Split(r.Header.Get(SignatureHeader), ",")
+		timestamp := strings.TrimPrefix(parts[0], "t=")
+		assert.Equal(t, "v1="+SignWebhook([]byte("shh"), timestamp, body), parts[1])
+		var result Result
+		assert.NoError(t, json.Unmarshal(body, &result))
+		received <- result
+	}))
+	defer server.Close()
+
+	g := &Game{
+		ID:      4,
+		Players: []Player{{Name: "al", Punchlines: []Card{"a1"}}, {Name: "bob", Punchlines: []Card{"b1"}}},
+		Rounds: []Round{{
+			Plays: map[string]Card{"al": "a1", "bob": "b1"},
+		}},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"},
+		RoundsRemaining: 1,
+		CurrentAction:   VOTE,
+		Created:         time.Now().Add(-time.Minute),
+	}
+	WithWebhook(server.URL)(g)
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("bob", "b1"))
+
+	select {
+	case result := <-received:
+		assert.Equal(t, 4, result.GameID)
+		assert.Equal(t, []Standing{{Player: "bob", RoundsWon: 1}, {Player: "al"}}, result.Standings)
+		assert.Equal(t, "https://api.example.com/games/4/transcript", result.TranscriptURL)
+		assert.InDelta(t, 60, result.DurationSeconds, 5)
+	case <-time.After(time.Second * 5):
+		t.Fatal("no delivery")
+	}
+	assert.Eventually(t, func() bool { return len(g.Deliveries()) == 2 }, time.Second, time.Millisecond)
+	deliveries := g.Deliveries()
+	assert.Equal(t, http.StatusBadGateway, deliveries[0].StatusCode)
+	assert.NotEmpty(t, deliveries[0].Error)
+	assert.Equal(t, http.StatusOK, deliveries[1].StatusCode)
+	assert.Empty(t, deliveries[1].Error)
+
+	go func() { <-received }()
+	d, err := g.Redeliver()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, d.Attempt)
+	assert.Equal(t, http.StatusOK, d.StatusCode)
+}
+
+func TestStandingsCountTies(t *testing.T) {
+	g := &Game{
+		Players: []Player{{Name: "al", Punchlines: []Card{"a1"}}, {Name: "bob", Punchlines: []Card{"b1"}}, {Name: "cy", Punchlines: []Card{"c1"}}},
+		Rounds: []Round{{
+			Plays: map[string]Card{"al": "a1", "bob": "b1", "cy": "c1"},
+		}},
+		Punchlines:      []Card{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"},
+		RoundsRemaining: 1,
+		CurrentAction:   VOTE,
+	}
+	assert.NoError(t, g.Vote("al", "b1"))
+	assert.NoError(t, g.Vote("bob", "c1"))
+	assert.NoError(t, g.Vote("cy", "a1"))
+	assert.Len(t, g.Rounds[0].Winners, 3)
+	assert.Equal(t, []Standing{{Player: "al", RoundsWon: 1}, {Player: "bob", RoundsWon: 1}, {Player: "cy", RoundsWon: 1}}, g.Standings())
+	assert.Equal(t, g.Standings(), g.Observe().Standings)
+}
+
+func TestValidateWebhook(t *testing.T) {
+	assert.NoError(t, ValidateWebhook("https://example.com/hook"))
+	assert.Equal(t, ErrInvalidWebhook, ValidateWebhook("ftp://example.com"))
+	assert.Equal(t, ErrInvalidWebhook, ValidateWebhook("/relative"))
+	assert.Equal(t, ErrPrivateAddress, ValidateWebhook("http://169.254.169.254/latest/meta-data"))
+	assert.Equal(t, ErrPrivateAddress, ValidateWebhook("http://localhost:7777/admin/reload"))
+	_, err := (&Game{}).Redeliver()
+	assert.Equal(t, ErrNoWebhook, err)
+}
@@ -0,0 +1,47 @@
+package game
+
+// Winners returns the players with the highest score once the game is
+// finished, more than one on a tie the game's TieBreak leaves shared. If no
+// round was won, e.g. nobody ever voted, there are no winners.
+func (g *Game) Winners() ([]Player, error) {
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if !g.Finished {
+		return nil, ErrNotFinished
+	}
+	winners := []Player{}
+	for _, player := range g.Players {
+		if contains(g.FinalWinners, player.Name) {
+			winners = append(winners, player)
+		}
+	}
+	return winners, nil
+}
+
+func (g *Game) topScorers() []Player {
+	top := 0
+	for _, player := range g.Players {
+		if player.Score > top {
+			top = player.Score
+		}
+	}
+	winners := []Player{}
+	if top == 0 {
+		return winners
+	}
+	for _, player := range g.Players {
+		if player.Score == top {
+			winners = append(winners, player)
+		}
+	}
+	return winners
+}
+
+// winnerNames lists the top scorers' names for FinalWinners.
+func (g *Game) winnerNames() []string {
+	names := []string{}
+	for _, player := range g.topScorers() {
+		names = append(names, player.Name)
+	}
+	return names
+}
@@ -0,0 +1,146 @@
+package game
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+const maxWriteIns = 5 // per player per game
+
+var (
+	ErrInvalidWriteIns = errors.New("write-ins must be between 0 and 5")
+	ErrInvalidWriteIn  = errors.New("write-in must be 1 to 140 printable characters")
+	ErrNoWriteIns      = errors.New("you have no write-ins left")
+	ErrWriteInTaken    = errors.New("write-in matches a card already in the game")
+)
+
+// WithWriteIns lets each player write in their own punchline n times a
+// game, see PlayWriteIn. Check n with ValidateWriteIns; 0, the default,
+// turns write-ins off.
+func WithWriteIns(n int) Option {
+	return func(g *Game) {
+		g.WriteIns = n
+		for i := range g.Players {
+			g.Players[i].WriteInsRemaining = n
+		}
+	}
+}
+
+func ValidateWriteIns(n int) error {
+	if n < 0 || n > maxWriteIns {
+		return ErrInvalidWriteIns
+	}
+	return nil
+}
+
+// PlayWriteIn plays text of playerName's own for the current round instead
+// of a card from their hand, using up one of their write-ins. Write-ins
+// aren't rated, so all that's checked is that they're 1 to 140 printable
+// characters and aren't a punchline the game has had or another play this
+// round. Their hand is left alone, and isn't topped up once the round is
+// settled.
+func (g *Game) PlayWriteIn(playerName, text string) error {
+	card := NormalizeCard(text)
+	if err := validateWriteIn(card); err != nil {
+		return err
+	}
+	g.mutex().Lock()
+	defer g.mutex().Unlock()
+	if g.isSpectator(playerName) {
+		return ErrSpectator
+	}
+	if g.over() {
+		return ErrGameFinished
+	}
+	if repeat, err := g.repeatedPlay(playerName, card); repeat {
+		return err
+	}
+	if g.Paused {
+		return ErrGamePaused
+	}
+	if g.CurrentAction != PLAY {
+		return ErrWrongPhase
+	}
+	if g.judge() == playerName {
+		return ErrJudge
+	}
+	player := g.player(playerName)
+	if player == nil {
+		return ErrPlayerNotFound
+	}
+	if g.PairPlay {
+		return ErrPairPlay
+	}
+	if player.WriteInsRemaining <= 0 {
+		return ErrNoWriteIns
+	}
+	err := g.allowAction(playerName)
+	if err != nil {
+		return err
+	}
+	err = g.checkParticipant(playerName)
+	if err != nil {
+		return err
+	}
+	err = g.checkSuddenDeath(playerName)
+	if err != nil {
+		return err
+	}
+	if g.cardTaken(card) {
+		return ErrWriteInTaken
+	}
+	round := &g.Rounds[g.RoundsRemaining-1]
+	if round.WriteIns == nil {
+		round.WriteIns = make(map[string]bool)
+	}
+	round.WriteIns[playerName] = true
+	player.WriteInsRemaining--
+	g.recordAction(playerName, PLAY)
+	g.play(playerName, card)
+	return nil
+}
+
+func validateWriteIn(card Card) error {
+	if card == "" || utf8.RuneCountInString(string(card)) > MaxCardLength {
+		return ErrInvalidWriteIn
+	}
+	if _, ok := suspiciousRune(string(card)); ok {
+		return ErrInvalidWriteIn
+	}
+	return nil
+}
+
+// cardTaken reports whether card is a punchline the game has had or has
+// been played this round, so a write-in of it would be ambiguous.
+func (g *Game) cardTaken(card Card) bool {
+	for _, played := range g.Rounds[g.RoundsRemaining-1].Plays {
+		if played == card {
+			return true
+		}
+	}
+	return g.replenishState().seen[card]
+}
+
+// returnPlay gives playerName back the card they played in round, as when
+// they take it back: a write-in is refunded, anything else goes back in
+// their hand, along with the rest of their cards in pair-play games.
+func (g *Game) returnPlay(round *Round, playerName string, card Card) {
+	player := g.player(playerName)
+	if pair, ok := round.Pairs[playerName]; ok {
+		delete(round.Pairs, playerName)
+		if player != nil {
+			player.Punchlines = append(player.Punchlines, pair...)
+		}
+		return
+	}
+	if round.WriteIns[playerName] {
+		delete(round.WriteIns, playerName)
+		if player != nil {
+			player.WriteInsRemaining++
+		}
+		return
+	}
+	if player != nil {
+		player.Punchlines = append(player.Punchlines, card)
+	}
+}
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayWriteIn(t *testing.T) {
+	defer config.Set(config.Current())
+	cfg := *config.Current()
+	cfg.MaxActions = 100
+	config.Set(&cfg)
+	source := &staticSource{}
+	for i := 0; i < 20; i++ {
+		*source = append(*source, RatedCard{Text: Card(strconv.Itoa(i)), Rating: "G"})
+	}
+	ctx := context.Background()
+	g, err := NewGame(ctx, Player{Name: "al"}, 3, "R", WithCardSource(source), WithHandSize(4), WithMinPlayers(2), WithWriteIns(1))
+	assert.NoError(t, err)
+	defer deleteGame(g.ID)
+	assert.NoError(t, g.AddPlayer(Player{Name: "bob"}, ""))
+	assert.Equal(t, 1, g.Players[1].WriteInsRemaining, "players joining get the game's write-ins")
+	assert.Equal(t, ErrWrongPhase, g.PlayWriteIn("al", "my own answer"))
+	assert.NoError(t, g.Start("al", false))
+
+	assert.Equal(t, ErrInvalidWriteIn, g.PlayWriteIn("al", "  "))
+	assert.Equal(t, ErrInvalidWriteIn, g.PlayWriteIn("al", strings.Repeat("a", MaxCardLength+1)))
+	assert.Equal(t, ErrInvalidWriteIn, g.PlayWriteIn("al", "zero\u200bwidth"))
+	assert.Equal(t, ErrWriteInTaken, g.PlayWriteIn("al", string(g.Players[1].Punchlines[0])))
+
+	hand := append([]Card(nil), g.Players[0].Punchlines...)
+	assert.NoError(t, g.PlayWriteIn("al", " my  own answer "))
+	assert.Equal(t, 0, g.Players[0].WriteInsRemaining)
+	assert.Equal(t, hand, g.Players[0].Punchlines, "a write-in leaves the hand alone")
+	assert.True(t, g.Rounds[2].WriteIns["al"])
+	assert.NoError(t, g.PlayWriteIn("al", "my own answer"), "a retry is accepted")
+
+	assert.NoError(t, g.RetractPlay("al"))
+	assert.Equal(t, 1, g.Players[0].WriteInsRemaining, "a retracted write-in is refunded")
+	assert.Equal(t, hand, g.Players[0].Punchlines)
+	assert.NoError(t, g.PlayWriteIn("al", "my own answer"))
+	bob := g.Players[1].Punchlines[0]
+	assert.Equal(t, ErrWriteInTaken, g.PlayWriteIn("bob", "my own answer"))
+	assert.NoError(t, g.Play("bob", bob))
+	assert.Equal(t, VOTE, g.CurrentAction)
+	assert.Nil(t, g.ViewFor("bob", HandDealt).Rounds[2].WriteIns, "who wrote in is hidden until the round is settled")
+	assert.NoError(t, g.Vote("al", "my own answer"))
+	assert.NoError(t, g.Vote("bob", "my own answer"))
+
+	assert.Equal(t, "al", g.Rounds[2].Winner)
+	assert.Equal(t, hand, g.Players[0].Punchlines, "nothing is dealt for a write-in")
+	assert.Len(t, g.Players[1].Punchlines, 4)
+	assert.Equal(t, []Card{bob}, g.discards, "write-ins aren't discarded")
+	assert.Equal(t, ErrNoWriteIns, g.PlayWriteIn("al", "another"))
+}
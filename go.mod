@@ -3,8 +3,10 @@ module github.com/stinkyfingers/differencebetween/api
 go 1.14
 
 require (
+	cloud.google.com/go/storage v1.10.0
 	github.com/aws/aws-sdk-go v1.33.5
 	github.com/stinkyfingers/easyrouter v0.0.0-20181021165214-f02b67d7de0c
 	github.com/stretchr/testify v1.5.1
 	golang.org/x/net v0.0.0-20200707034311-ab3426394381
+	golang.org/x/text v0.3.2
 )
@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stinkyfingers/differencebetween/api/flags"
+	"github.com/stinkyfingers/differencebetween/api/game"
+	"github.com/stinkyfingers/differencebetween/api/metrics"
+)
+
+const maxImportSize = 10 << 20
+
+var deckNameRegex = regexp.MustCompile(`^[a-z0-9-]{1,40}$`)
+
+// ImportResponse reports what a CAH import kept and skipped
+type ImportResponse struct {
+	Setups     int                `json:"setups"`
+	Punchlines int                `json:"punchlines"`
+	Skipped    []game.SkippedCard `json:"skipped"`
+	Keys       []string           `json:"keys,omitempty"` // s3 keys written
+}
+
+// StatsResponse summarizes server health for admins
+type StatsResponse struct {
+	Games    int              `json:"games"`
+	Errors   map[string]int64 `json:"errors"`   // code:count
+	Usage    map[string]int64 `json:"usage"`    // labels:count
+	Features []string         `json:"features"` // enabled flags
+}
+
+func Stats(w http.ResponseWriter, r *http.Request) {
+	j, err := json.Marshal(StatsResponse{
+		Games:    game.Count(),
+		Errors:   metrics.ErrorCounts(),
+		Usage:    metrics.UsageCounts(),
+		Features: flags.Active(),
+	})
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// AdminGames summarizes the games listed in ?ids=1,2,3, or all games,
+// optionally filtered by ?state=open|active|finished
+func AdminGames(w http.ResponseWriter, r *http.Request) {
+	var ids []int
+	if list := r.URL.Query().Get("ids"); list != "" {
+		for _, s := range strings.Split(list, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				HTTPStatusError(w, errors.New("ids must be a comma separated list of game ids"), http.StatusBadRequest)
+				return
+			}
+			ids = append(ids, id)
+		}
+	}
+	state := r.URL.Query().Get("state")
+	switch state {
+	case "", game.StateOpen, game.StateActive, game.StateFinished:
+	default:
+		HTTPStatusError(w, errors.New("state must be open, active or finished"), http.StatusBadRequest)
+		return
+	}
+	writeSummaries(w, ids, state)
+}
+
+// AdminGameResponse is everything about a game, including what players
+// don't see
+type AdminGameResponse struct {
+	Game       *game.Game      `json:"game"`
+	Deliveries []game.Delivery `json:"deliveries"` // webhook attempts
+}
+
+// AdminGame shows the game at /admin/games/{id}
+func AdminGame(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, ok := lookupGame(w, id)
+	if !ok {
+		return
+	}
+	j, err := json.Marshal(AdminGameResponse{Game: g, Deliveries: g.Deliveries()})
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// Redeliver retries delivering the result of the game at
+// /admin/games/{id}/redeliver to its webhook, once, and reports the attempt
+func Redeliver(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	setAuditTarget(r.Context(), "game/"+strconv.Itoa(id))
+	g, ok := lookupGame(w, id)
+	if !ok {
+		return
+	}
+	if g.State() != game.StateFinished {
+		HTTPStatusError(w, errors.New("game has not finished"), http.StatusConflict)
+		return
+	}
+	delivery, err := g.Redeliver()
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	j, err := json.Marshal(delivery)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// DeleteGame soft deletes the game at /admin/games/{id}. It can be restored
+// for a while with RestoreGame.
+func DeleteGame(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	setAuditTarget(r.Context(), "game/"+strconv.Itoa(id))
+	err = game.Delete(id)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreGame undoes DeleteGame for /admin/games/{id}/restore and pushes the
+// game to any clients still connected so they resync.
+func RestoreGame(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	setAuditTarget(r.Context(), "game/"+strconv.Itoa(id))
+	g, err := game.Restore(id)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusNotFound)
+		return
+	}
+	hub.Push(g)
+	writeSummaries(w, []int{id}, "")
+}
+
+// ResetSession lets /admin/sessions/{id}'s next game deal any setups again.
+func ResetSession(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	err := game.ValidateSession(id)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	game.ResetSession(id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Snapshot downloads everything about the game at /admin/games/{id}/snapshot,
+// for LoadSnapshot to bring back, e.g. after a restart
+func Snapshot(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	setAuditTarget(r.Context(), "game/"+strconv.Itoa(id))
+	g, ok := lookupGame(w, id)
+	if !ok {
+		return
+	}
+	j, err := g.Snapshot()
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// LoadSnapshot brings back a game from a Snapshot posted to /admin/snapshots
+// under its old id, if no game in memory has it
+func LoadSnapshot(w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxImportSize))
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, err := game.RestoreGame(data)
+	switch {
+	case err == nil:
+	case errors.Is(err, game.ErrInvalidSnapshot):
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	case err == game.ErrGameExists:
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	default:
+		HTTPError(w, err)
+		return
+	}
+	setAuditTarget(r.Context(), "game/"+strconv.Itoa(g.ID))
+	writeSummaries(w, []int{g.ID}, "")
+}
+
+func writeSummaries(w http.ResponseWriter, ids []int, state string) {
+	summaries, err := game.GamesSummary(ids, state)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	j, err := json.Marshal(summaries)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// Reload reloads the configuration, like SIGHUP, and lists what changed
+func Reload(w http.ResponseWriter, r *http.Request) {
+	changes, err := config.Reload()
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	for _, change := range changes {
+		log.Print("config: ", change)
+	}
+	if changes == nil {
+		changes = []config.Change{}
+	}
+	j, err := json.Marshal(changes)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// ImportCAH converts an uploaded CAH-format JSON card set, rating every card
+// with ?rating= (default R). With ?name= both decks are written to S3 under
+// decks/{name}/; otherwise they are returned as a download, selected by
+// ?format=json (default), setups.csv or punchlines.csv.
+func ImportCAH(w http.ResponseWriter, r *http.Request) {
+	rating := r.URL.Query().Get("rating")
+	if rating == "" {
+		rating = "R"
+	}
+	imported, err := game.ImportCAH(http.MaxBytesReader(w, r.Body, maxImportSize), rating)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	if name := r.URL.Query().Get("name"); name != "" {
+		setAuditTarget(r.Context(), "decks/"+name)
+		if !deckNameRegex.MatchString(name) {
+			HTTPStatusError(w, errors.New("name must be 1-40 lowercase letters, digits or dashes"), http.StatusBadRequest)
+			return
+		}
+		resp := ImportResponse{
+			Setups:     len(imported.Setups),
+			Punchlines: len(imported.Punchlines),
+			Skipped:    imported.Skipped,
+			Keys:       []string{"decks/" + name + "/setups.csv", "decks/" + name + "/punchlines.csv"},
+		}
+		err = game.PutDeck(r.Context(), resp.Keys[0], imported.Setups)
+		if err != nil {
+			HTTPStatusError(w, err, http.StatusBadGateway)
+			return
+		}
+		err = game.PutDeck(r.Context(), resp.Keys[1], imported.Punchlines)
+		if err != nil {
+			HTTPStatusError(w, err, http.StatusBadGateway)
+			return
+		}
+		j, err := json.Marshal(resp)
+		if err != nil {
+			HTTPError(w, err)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.Write(j)
+		return
+	}
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "setups.csv", "punchlines.csv":
+		cards := imported.Setups
+		if format == "punchlines.csv" {
+			cards = imported.Punchlines
+		}
+		w.Header().Add("Content-Type", "text/csv")
+		w.Header().Add("Content-Disposition", `attachment; filename="`+format+`"`)
+		game.WriteCSVDeck(w, cards)
+	case "", "json":
+		j, err := json.Marshal(imported)
+		if err != nil {
+			HTTPError(w, err)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("Content-Disposition", `attachment; filename="import.json"`)
+		w.Write(j)
+	default:
+		HTTPStatusError(w, errors.New("format must be json, setups.csv or punchlines.csv"), http.StatusBadRequest)
+	}
+}
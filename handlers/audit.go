@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/audit"
+)
+
+// AuditLog receives an entry for requests to Admin routes. It keeps the
+// latest entries in memory unless swapped before serving, as main does for
+// AUDIT_BUCKET; GET /admin/audit only works when it's also an audit.Reader.
+var AuditLog audit.Writer = audit.NewMemoryLog()
+
+// statusWriter remembers the status a handler responded with.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusWriter) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func newAuditEntry(r *http.Request) *audit.Entry {
+	return &audit.Entry{
+		Time:       time.Now(),
+		Actor:      "anonymous",
+		Action:     r.Method + " " + r.URL.Path,
+		RemoteAddr: r.RemoteAddr,
+	}
+}
+
+func writeAudit(e *audit.Entry) {
+	err := AuditLog.Write(*e)
+	if err != nil {
+		log.Print("audit: ", err)
+	}
+}
+
+// deniedWindow is how often Admin writes a denied entry for one address;
+// the denials in between are counted in the next entry's Suppressed rather
+// than written, so anyone probing the admin routes can't flood the log.
+const deniedWindow = time.Minute
+
+// maxDeniedAddrs bounds how many addresses denials are tracked for.
+const maxDeniedAddrs = 10000
+
+var denials = &deniedLimiter{seen: make(map[string]*deniedAddr)}
+
+type deniedLimiter struct {
+	mu   sync.Mutex
+	seen map[string]*deniedAddr
+}
+
+type deniedAddr struct {
+	written    time.Time
+	suppressed int
+}
+
+// allow reports whether a denied request from remoteAddr should be written
+// at now, and if so how many since the last one weren't.
+func (d *deniedLimiter) allow(remoteAddr string, now time.Time) (int, bool) {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if addr, ok := d.seen[host]; ok {
+		if now.Sub(addr.written) < deniedWindow {
+			addr.suppressed++
+			return 0, false
+		}
+		suppressed := addr.suppressed
+		*addr = deniedAddr{written: now}
+		return suppressed, true
+	}
+	if len(d.seen) >= maxDeniedAddrs {
+		for h, addr := range d.seen {
+			if now.Sub(addr.written) >= deniedWindow {
+				delete(d.seen, h)
+			}
+		}
+	}
+	if len(d.seen) < maxDeniedAddrs {
+		d.seen[host] = &deniedAddr{written: now}
+	}
+	return 0, true
+}
+
+// setAuditTarget names the game, deck or card an admin request acted on.
+func setAuditTarget(ctx context.Context, target string) {
+	if e, ok := ctx.Value(auditEntryKey).(*audit.Entry); ok {
+		e.Target = target
+	}
+}
+
+// Audit lists audit entries, optionally limited with RFC3339 ?from= and ?to=.
+func Audit(w http.ResponseWriter, r *http.Request) {
+	reader, ok := AuditLog.(audit.Reader)
+	if !ok {
+		HTTPStatusError(w, errors.New("audit log is write-only"), http.StatusNotImplemented)
+		return
+	}
+	var from, to time.Time
+	var err error
+	if s := r.URL.Query().Get("from"); s != "" {
+		from, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			HTTPStatusError(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+	if s := r.URL.Query().Get("to"); s != "" {
+		to, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			HTTPStatusError(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+	entries, err := reader.Entries(from, to)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	j, err := json.Marshal(entries)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/audit"
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuditsDenialsOnce(t *testing.T) {
+	previous, log := AuditLog, audit.NewMemoryLog()
+	AuditLog = log
+	denials = &deniedLimiter{seen: make(map[string]*deniedAddr)}
+	old := config.Current()
+	cfg := *old
+	cfg.AdminSecret = "secret"
+	config.Set(&cfg)
+	t.Cleanup(func() {
+		AuditLog = previous
+		config.Set(old)
+	})
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		Admin(Stats)(w, httptest.NewRequest("GET", "/admin/stats", nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	}
+	r := httptest.NewRequest("GET", "/admin/stats", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	Admin(Stats)(httptest.NewRecorder(), r)
+	entries, err := log.Entries(time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2, "one denial and the allowed request") {
+		assert.Equal(t, audit.OutcomeDenied, entries[0].Outcome)
+		assert.Equal(t, audit.OutcomeSuccess, entries[1].Outcome)
+	}
+
+	// the denials in between are counted on the next one written
+	start := time.Now().Add(time.Hour)
+	_, ok := denials.allow("192.0.2.1:1234", start)
+	assert.True(t, ok)
+	_, ok = denials.allow("192.0.2.1:5678", start.Add(time.Second))
+	assert.False(t, ok, "same address, another port")
+	_, ok = denials.allow("192.0.2.2:1234", start.Add(time.Second))
+	assert.True(t, ok, "another address")
+	suppressed, ok := denials.allow("192.0.2.1:1234", start.Add(deniedWindow))
+	assert.True(t, ok)
+	assert.Equal(t, 1, suppressed)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/stinkyfingers/differencebetween/api/auth"
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stinkyfingers/differencebetween/api/game"
+)
+
+// TokenHeader carries a newly issued player token on create and join responses.
+const TokenHeader = "X-Player-Token"
+
+// ReconnectTokenHeader carries a player's reconnect token, for POST
+// /reconnect, on create, join and rematch responses. It's only sent once.
+const ReconnectTokenHeader = "X-Reconnect-Token"
+
+type contextKey int
+
+const (
+	claimsKey contextKey = iota
+	auditEntryKey
+	observerClaimsKey
+)
+
+var errUnauthorized = errors.New("missing or invalid player token")
+
+// tokenSigner returns nil when no TOKEN_SECRET is configured, in which case
+// tokens are random strings checked against the hash stored on the game's
+// player.
+func tokenSigner() *auth.Signer {
+	cfg := config.Current()
+	if cfg.TokenSecret == "" {
+		return nil
+	}
+	return &auth.Signer{
+		Key:           []byte(cfg.TokenSecret),
+		PreviousKey:   []byte(cfg.TokenPreviousSecret),
+		PreviousUntil: cfg.TokenPreviousUntil,
+		TTL:           cfg.GameTTL,
+	}
+}
+
+// issueToken returns a token authenticating playerName in g. Signed tokens
+// expire with the game, so one can't outlive it and be replayed against a
+// new game reusing the id; a pause pushes the game's expiry back but not
+// its tokens', and those players get new ones by reconnecting.
+func issueToken(g *game.Game, playerName string) (string, error) {
+	if signer := tokenSigner(); signer != nil {
+		return signer.IssueClaims(auth.Claims{
+			GameID:     g.ID,
+			Player:     playerName,
+			Expires:    g.Expires().Unix(),
+			Generation: g.TokenGeneration(playerName),
+		})
+	}
+	return g.IssueToken(playerName)
+}
+
+// PlayerAuth rejects requests without a valid player token, taken from the
+// Authorization bearer or, for websockets, the token query param. If the
+// route has an id param it must match the token's game. The authenticated
+// player is available to the handler via PlayerFromContext.
+func PlayerAuth(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" {
+			token = r.URL.Query().Get("token")
+		}
+		claims, err := authenticate(token, r.URL.Query().Get("id"))
+		if err != nil {
+			HTTPStatusError(w, err, http.StatusUnauthorized)
+			return
+		}
+		fn(w, r.WithContext(context.WithValue(r.Context(), claimsKey, claims)))
+	}
+}
+
+// PlayerFromContext returns the claims PlayerAuth verified for the request.
+func PlayerFromContext(ctx context.Context) (*auth.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey).(*auth.Claims)
+	return claims, ok
+}
+
+func authenticate(token, idStr string) (*auth.Claims, error) {
+	if token == "" {
+		return nil, errUnauthorized
+	}
+	if signer := tokenSigner(); signer != nil {
+		claims, err := signer.Verify(token)
+		if err != nil {
+			return nil, err
+		}
+		if idStr != "" && idStr != strconv.Itoa(claims.GameID) {
+			return nil, errUnauthorized
+		}
+		// revoked when the player left, was kicked or renamed; handlers
+		// report games that are gone themselves
+		if g, err := game.GetGame(claims.GameID); err == nil && g.TokenGeneration(claims.Player) != claims.Generation {
+			return nil, errUnauthorized
+		}
+		return claims, nil
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return nil, errUnauthorized
+	}
+	g, err := game.GetGame(id)
+	if err != nil {
+		return nil, errUnauthorized
+	}
+	player, ok := g.TokenPlayer(token)
+	if !ok {
+		return nil, errUnauthorized
+	}
+	return &auth.Claims{GameID: id, Player: player}, nil
+}
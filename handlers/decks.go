@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/stinkyfingers/differencebetween/api/game"
+)
+
+var deckTypes = map[string]game.Deck{
+	"":          "",
+	"setup":     game.SetupDeck,
+	"punchline": game.PunchlineDeck,
+}
+
+// SearchDecks finds cards in the loaded decks:
+// /decks/search?q=taxes&type=punchline&rating=PG-13&offset=0&limit=50. type
+// is setup or punchline, both if omitted, and rating limits cards to those
+// clean enough for a game at that rating.
+func SearchDecks(w http.ResponseWriter, r *http.Request) {
+	searchDecks(w, r, false)
+}
+
+// AdminSearchDecks is SearchDecks with each card's lint problems.
+func AdminSearchDecks(w http.ResponseWriter, r *http.Request) {
+	searchDecks(w, r, true)
+}
+
+func searchDecks(w http.ResponseWriter, r *http.Request, admin bool) {
+	query := r.URL.Query()
+	search := game.CardSearch{
+		Query:  query.Get("q"),
+		Rating: query.Get("rating"),
+		Admin:  admin,
+	}
+	if search.Query == "" {
+		HTTPStatusError(w, errors.New("q is required"), http.StatusBadRequest)
+		return
+	}
+	deck, ok := deckTypes[query.Get("type")]
+	if !ok {
+		HTTPStatusError(w, errors.New("type must be setup or punchline"), http.StatusBadRequest)
+		return
+	}
+	search.Deck = deck
+	var err error
+	for param, value := range map[string]*int{"offset": &search.Offset, "limit": &search.Limit} {
+		if v := query.Get(param); v != "" {
+			*value, err = strconv.Atoi(v)
+			if err != nil || *value < 0 {
+				HTTPStatusError(w, errors.New(param+" must be a non-negative integer"), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	result, err := game.SearchCards(search)
+	switch err {
+	case nil:
+	case game.ErrDecksNotLoaded:
+		HTTPStatusError(w, err, http.StatusServiceUnavailable)
+		return
+	default:
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	j, err := json.Marshal(result)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
@@ -1,69 +1,516 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/stinkyfingers/differencebetween/api/flags"
 	"github.com/stinkyfingers/differencebetween/api/game"
 	"golang.org/x/net/websocket"
 )
 
+var errInvalidAction = errors.New("invalid action")
+
 type GameRequest struct {
-	Player string `json:"player"` // name
-	Rounds int    `json:"rounds"` // num rounds
+	Player string       `json:"player"` // name
+	Rounds int          `json:"rounds"` // num rounds, or the most a game with a targetScore may last, 0 for no limit
+	Deck   *DeckRequest `json:"deck,omitempty"`
+
+	Features   []string `json:"features,omitempty"` // see GET /features
+	ReadyCheck bool     `json:"readyCheck"`         // players must say they're ready before the host can start, see /games/{id}/ready
+	Replenish  bool     `json:"replenish"`          // fetch more punchlines when the deck runs low
+	AutoPlay   bool     `json:"autoPlay"`           // play at random for players who miss the play timer, rather than skip them
+	AutoVote   bool     `json:"autoVote"`           // vote at random for players who miss the vote timer, rather than skip them
+	Webhook    string   `json:"webhook,omitempty"`  // receives the signed result when the game finishes
+	PIN        string   `json:"pin,omitempty"`      // needed to join or watch, 4 to 64 characters
+	Teams      bool     `json:"teams"`              // play in teams, which players pick as they join
+	PairPlay   bool     `json:"pairPlay"`           // each player plays a card for every setup, and the sets are voted on
+	Team       string   `json:"team,omitempty"`     // the host's team, in games played in teams
+
+	Locale     string `json:"locale,omitempty"`     // for generated text such as transcripts, en by default
+	League     string `json:"league,omitempty"`     // a short slug grouping the game with others, see /leagues
+	Session    string `json:"session,omitempty"`    // a slug shared by back-to-back games, so they don't repeat setups
+	VotingMode string `json:"votingMode,omitempty"` // "single", the default, "ranked", "judge" or "multi"
+	RankCount  int    `json:"rankCount,omitempty"`  // cards each voter ranks in ranked games, 2 by default
+	TieBreak   string `json:"tieBreak,omitempty"`   // "shared", the default, "votes" or "sudden_death"
+
+	VotesPerPlayer int `json:"votesPerPlayer,omitempty"` // cards each voter picks in multi games, 2 by default
+	TargetScore    int `json:"targetScore,omitempty"`    // rounds a player must win to end the game, 1 to 50, rather than playing a fixed number
+	SetupSize      int `json:"setupSize,omitempty"`      // setups in each round, 2, the default, or 3
+	WriteIns       int `json:"writeIns,omitempty"`       // punchlines each player may write in, 0 to 5, none by default
+	HandSize       int `json:"handSize,omitempty"`       // cards dealt to each player, 3 to 12, 6 by default
+	MinPlayers     int `json:"minPlayers,omitempty"`     // needed to start, 2 to maxPlayers, 3 by default
+	MaxPlayers     int `json:"maxPlayers,omitempty"`     // 2 to 30 and no more than the deck can deal to, 12 or as many as it can by default
+
+	// how much each spectator's vote counts, more than 0 and at most 1, or
+	// with AudiencePooled all of theirs together; 0 leaves the audience out
+	AudienceWeight float64 `json:"audienceWeight,omitempty"`
+	AudiencePooled bool    `json:"audiencePooled,omitempty"`
+
+	// seconds each phase may last, 0 for no limit; see TimersRequest
+	PlaySeconds int `json:"playSeconds"`
+	VoteSeconds int `json:"voteSeconds"`
+}
+
+// DeckRequest points a game at a custom deck hosted somewhere other than S3
+type DeckRequest struct {
+	SetupsURL     string `json:"setupsUrl"`
+	PunchlinesURL string `json:"punchlinesUrl"`
 }
 
 type PlayerRequest struct {
 	Player string `json:"player"` // name
 	GameID int    `json:"id"`
+	Code   string `json:"code,omitempty"` // the game's join code, instead of id
+	PIN    string `json:"pin,omitempty"`  // if the game has one
+	Team   string `json:"team,omitempty"` // in games played in teams
+}
+
+// game looks up the game to join, by code if one was given.
+func (playerRequest PlayerRequest) game() (*game.Game, error) {
+	if playerRequest.Code != "" {
+		return game.GetGameByCode(playerRequest.Code)
+	}
+	return game.GetGame(playerRequest.GameID)
+}
+
+// lookupGame returns the game with id, or responds 410 if it was deleted
+// and 404 if there's no such game and reports false.
+func lookupGame(w http.ResponseWriter, id int) (*game.Game, bool) {
+	g, err := game.GetGame(id)
+	if err != nil {
+		HTTPStatusError(w, err, lookupStatus(err))
+		return nil, false
+	}
+	return g, true
+}
+
+// lookupStatus is the status for an error looking up a game.
+func lookupStatus(err error) int {
+	if err == game.ErrGameGone {
+		return http.StatusGone
+	}
+	return http.StatusNotFound
+}
+
+// cleanliness is the rating a new game's cards must fit: R, or PG with
+// ?pg=true.
+func cleanliness(r *http.Request) string {
+	if r.URL.Query().Get("pg") == "true" {
+		return "PG"
+	}
+	return "R"
+}
+
+// options checks the request's settings and converts them to game options.
+// Every problem found is returned, not just the first.
+func (gameRequest *GameRequest) options() ([]game.Option, []error) {
+	var opts []game.Option
+	var violations []error
+	gameRequest.Player = game.NormalizePlayerName(gameRequest.Player)
+	if err := game.ValidatePlayerName(gameRequest.Player); err != nil {
+		violations = append(violations, err)
+	}
+	if gameRequest.Deck != nil {
+		if gameRequest.Deck.SetupsURL == "" || gameRequest.Deck.PunchlinesURL == "" {
+			violations = append(violations, errors.New("custom deck requires both setupsUrl and punchlinesUrl"))
+		} else if err := game.ValidateDeckURL(gameRequest.Deck.SetupsURL); err != nil {
+			violations = append(violations, err)
+		} else if err := game.ValidateDeckURL(gameRequest.Deck.PunchlinesURL); err != nil {
+			violations = append(violations, err)
+		} else {
+			opts = append(opts, game.WithCardSource(game.NewHTTPCardSource(gameRequest.Deck.SetupsURL, gameRequest.Deck.PunchlinesURL)))
+		}
+	}
+	for _, feature := range gameRequest.Features {
+		err := flags.Check(feature)
+		if err != nil {
+			violations = append(violations, err)
+		}
+	}
+	if gameRequest.Webhook != "" {
+		err := game.ValidateWebhook(gameRequest.Webhook)
+		if err != nil {
+			violations = append(violations, err)
+		}
+		opts = append(opts, game.WithWebhook(gameRequest.Webhook))
+	}
+	switch gameRequest.VotingMode {
+	case "", game.VotingSingle:
+	case game.VotingRanked:
+		err := game.ValidateRankCount(gameRequest.RankCount)
+		if err != nil {
+			violations = append(violations, err)
+		}
+		opts = append(opts, game.WithRankedVoting(gameRequest.RankCount))
+	case game.VotingJudge:
+		opts = append(opts, game.WithJudge())
+	case game.VotingMulti:
+		err := game.ValidateVotesPerPlayer(gameRequest.VotesPerPlayer)
+		if err != nil {
+			violations = append(violations, err)
+		}
+		opts = append(opts, game.WithVotesPerPlayer(gameRequest.VotesPerPlayer))
+	default:
+		violations = append(violations, errors.New("votingMode must be single, ranked, judge or multi"))
+	}
+	if gameRequest.TieBreak != "" {
+		err := game.ValidateTieBreak(gameRequest.TieBreak)
+		if err != nil {
+			violations = append(violations, err)
+		}
+		opts = append(opts, game.WithTieBreak(gameRequest.TieBreak))
+	}
+	if gameRequest.SetupSize != 0 {
+		err := game.ValidateSetupSize(gameRequest.SetupSize)
+		if err != nil {
+			violations = append(violations, err)
+		}
+		opts = append(opts, game.WithSetupSize(gameRequest.SetupSize))
+	}
+	if gameRequest.TargetScore != 0 {
+		err := game.ValidateTargetScore(gameRequest.TargetScore)
+		if err != nil {
+			violations = append(violations, err)
+		}
+		opts = append(opts, game.WithTargetScore(gameRequest.TargetScore))
+	}
+	if gameRequest.Locale != "" {
+		err := game.ValidateLocale(gameRequest.Locale)
+		if err != nil {
+			violations = append(violations, err)
+		}
+		opts = append(opts, game.WithLocale(gameRequest.Locale))
+	}
+	if gameRequest.League != "" {
+		err := game.ValidateLeague(gameRequest.League)
+		if err != nil {
+			violations = append(violations, err)
+		}
+		opts = append(opts, game.WithLeague(gameRequest.League))
+	}
+	if gameRequest.Session != "" {
+		err := game.ValidateSession(gameRequest.Session)
+		if err != nil {
+			violations = append(violations, err)
+		}
+		opts = append(opts, game.WithSession(gameRequest.Session))
+	}
+	if gameRequest.HandSize != 0 {
+		err := game.ValidateHandSize(gameRequest.HandSize)
+		if err != nil {
+			violations = append(violations, err)
+		}
+		opts = append(opts, game.WithHandSize(gameRequest.HandSize))
+	}
+	if gameRequest.MaxPlayers != 0 {
+		err := game.ValidateMaxPlayers(gameRequest.MaxPlayers)
+		if err != nil {
+			violations = append(violations, err)
+		}
+		opts = append(opts, game.WithMaxPlayers(gameRequest.MaxPlayers))
+	}
+	if gameRequest.MinPlayers != 0 {
+		err := game.ValidateMinPlayers(gameRequest.MinPlayers, gameRequest.MaxPlayers)
+		if err != nil {
+			violations = append(violations, err)
+		}
+		opts = append(opts, game.WithMinPlayers(gameRequest.MinPlayers))
+	}
+	if gameRequest.WriteIns != 0 {
+		err := game.ValidateWriteIns(gameRequest.WriteIns)
+		if gameRequest.PairPlay {
+			err = game.ErrPairPlay
+		}
+		if err != nil {
+			violations = append(violations, err)
+		}
+		opts = append(opts, game.WithWriteIns(gameRequest.WriteIns))
+	}
+	if gameRequest.PairPlay {
+		opts = append(opts, game.WithPairPlay())
+	}
+	if gameRequest.AudienceWeight != 0 {
+		err := game.ValidateAudienceWeight(gameRequest.AudienceWeight)
+		if err != nil {
+			violations = append(violations, err)
+		}
+		opts = append(opts, game.WithAudienceVoting(gameRequest.AudienceWeight, gameRequest.AudiencePooled))
+	}
+	err := game.ValidateTimers(gameRequest.PlaySeconds, gameRequest.VoteSeconds)
+	if err != nil {
+		violations = append(violations, err)
+	}
+	opts = append(opts, game.WithTimers(gameRequest.PlaySeconds, gameRequest.VoteSeconds))
+	if gameRequest.PIN != "" {
+		err := game.ValidatePIN(gameRequest.PIN)
+		if err != nil {
+			violations = append(violations, err)
+		}
+		opts = append(opts, game.WithPIN(gameRequest.PIN))
+	}
+	if gameRequest.AutoPlay {
+		opts = append(opts, game.WithAutoPlay())
+	}
+	if gameRequest.AutoVote {
+		opts = append(opts, game.WithAutoVote())
+	}
+	if gameRequest.ReadyCheck {
+		opts = append(opts, game.WithReadyCheck())
+	}
+	if gameRequest.Replenish {
+		opts = append(opts, game.WithReplenish())
+	}
+	if gameRequest.Teams {
+		opts = append(opts, game.WithTeams())
+	}
+	gameRequest.Team = game.NormalizePlayerName(gameRequest.Team)
+	if gameRequest.Team != "" {
+		err := game.ValidateTeam(gameRequest.Team)
+		if !gameRequest.Teams {
+			err = game.ErrNoTeams
+		}
+		if err != nil {
+			violations = append(violations, err)
+		}
+	}
+	if len(gameRequest.Features) > 0 {
+		opts = append(opts, game.WithFeatures(gameRequest.Features...))
+	}
+	return opts, violations
 }
 
 func CreateGame(w http.ResponseWriter, r *http.Request) {
 	var gameRequest GameRequest
 	err := json.NewDecoder(r.Body).Decode(&gameRequest)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	opts, violations := gameRequest.options()
+	if len(violations) > 0 {
+		HTTPStatusError(w, violations[0], http.StatusBadRequest)
+		return
+	}
+	g, err := game.NewGame(r.Context(), game.Player{Name: gameRequest.Player, Team: gameRequest.Team}, gameRequest.Rounds, cleanliness(r), opts...)
+	if errors.Is(err, game.ErrInvalidRounds) || errors.Is(err, game.ErrTooManyPlayers) {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		HTTPError(w, err)
 		return
 	}
-	cleanliness := "R"
-	if r.URL.Query().Get("pg") == "true" {
-		cleanliness = "PG"
+	token, err := issueToken(g, gameRequest.Player)
+	if err != nil {
+		HTTPError(w, err)
+		return
 	}
-	g, err := game.NewGame(game.Player{Name: gameRequest.Player}, gameRequest.Rounds, cleanliness)
+	reconnectToken, err := g.IssueReconnectToken(gameRequest.Player)
 	if err != nil {
 		HTTPError(w, err)
 		return
 	}
-	j, err := json.Marshal(g)
+	j, err := g.ViewJSON(gameRequest.Player, game.HandDealt)
 	if err != nil {
 		HTTPError(w, err)
 		return
 	}
 	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add(TokenHeader, token)
+	w.Header().Add(ReconnectTokenHeader, reconnectToken)
 	w.Write(j)
 }
 
-func AddPlayer(w http.ResponseWriter, r *http.Request) {
-	var playerRequest PlayerRequest
-	err := json.NewDecoder(r.Body).Decode(&playerRequest)
+// ValidationResult reports whether a GameRequest would create a game, and
+// how big a game its decks support.
+type ValidationResult struct {
+	Valid bool `json:"valid"`
+	game.Capacity
+	Violations []Error `json:"violations"`
+}
+
+// ValidateGame checks a GameRequest as CreateGame would, without creating
+// the game, and lists every problem found.
+func ValidateGame(w http.ResponseWriter, r *http.Request) {
+	var gameRequest GameRequest
+	err := json.NewDecoder(r.Body).Decode(&gameRequest)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	opts, violations := gameRequest.options()
+	capacity, err := game.ValidateGameSettings(r.Context(), game.Settings{
+		Rounds:      gameRequest.Rounds,
+		Cleanliness: cleanliness(r),
+		Options:     opts,
+	})
+	if v, ok := err.(game.Violations); ok {
+		violations = append(violations, v...)
+	} else if err != nil {
+		violations = append(violations, err)
+	}
+	result := ValidationResult{Valid: len(violations) == 0, Capacity: capacity, Violations: []Error{}}
+	for _, violation := range violations {
+		result.Violations = append(result.Violations, newError(violation))
+	}
+	j, err := json.Marshal(result)
 	if err != nil {
 		HTTPError(w, err)
 		return
 	}
-	g, err := game.GetGame(playerRequest.GameID)
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+type CommentRequest struct {
+	Comment string `json:"comment"`
+}
+
+// CommentRound lets the authenticated winner of /games/{id}/rounds/{n}
+// attach a comment to it, then pushes the game to its players.
+func CommentRound(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	number, err := strconv.Atoi(r.URL.Query().Get("n"))
+	if err != nil {
+		HTTPStatusError(w, game.ErrRoundNotFound, http.StatusNotFound)
+		return
+	}
+	var commentRequest CommentRequest
+	err = json.NewDecoder(r.Body).Decode(&commentRequest)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	err = g.Comment(claims.Player, number, commentRequest.Comment)
+	switch err {
+	case nil:
+	case game.ErrNotRoundWinner:
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	case game.ErrRoundNotFound:
+		HTTPStatusError(w, err, http.StatusNotFound)
+		return
+	case game.ErrRoundNotFinished, game.ErrCommentClosed:
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	default:
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(claims.Player, game.HandDealt)
 	if err != nil {
 		HTTPError(w, err)
 		return
 	}
-	err = g.AddPlayer(game.Player{Name: playerRequest.Player})
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+type MessageRequest struct {
+	Text string `json:"text"`
+}
+
+// PostMessage adds the authenticated player's, or spectator's, message to
+// the chat of /games/{id}, then pushes the game to its players.
+func PostMessage(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	var messageRequest MessageRequest
+	err := json.NewDecoder(r.Body).Decode(&messageRequest)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	err = g.PostMessage(claims.Player, messageRequest.Text)
+	switch err {
+	case nil:
+	case game.ErrPlayerNotFound:
+		HTTPStatusError(w, err, http.StatusNotFound)
+		return
+	case game.ErrTooManyActions:
+		HTTPStatusError(w, err, http.StatusTooManyRequests)
+		return
+	default:
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(claims.Player, game.HandDealt)
 	if err != nil {
 		HTTPError(w, err)
 		return
 	}
-	j, err := json.Marshal(g)
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+type ReactionRequest struct {
+	Card  game.Card `json:"card"`
+	Emoji string    `json:"emoji"` // one of game.ReactionEmoji
+}
+
+// React records the authenticated player's reaction to a card on the ballot
+// of /games/{id}'s current round, then pushes the game to its players.
+func React(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	var reactionRequest ReactionRequest
+	err := json.NewDecoder(r.Body).Decode(&reactionRequest)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	err = g.React(claims.Player, reactionRequest.Card, reactionRequest.Emoji)
+	switch err {
+	case nil:
+	case game.ErrSpectator:
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	case game.ErrPlayerNotFound:
+		HTTPStatusError(w, err, http.StatusNotFound)
+		return
+	case game.ErrWrongPhase, game.ErrGameFinished:
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	case game.ErrTooManyActions:
+		HTTPStatusError(w, err, http.StatusTooManyRequests)
+		return
+	default:
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(claims.Player, game.HandDealt)
 	if err != nil {
 		HTTPError(w, err)
 		return
@@ -72,72 +519,1096 @@ func AddPlayer(w http.ResponseWriter, r *http.Request) {
 	w.Write(j)
 }
 
-func Game(ws *websocket.Conn, hub *Hub) {
-	idStr := ws.Request().URL.Query().Get("id")
-	id, err := strconv.Atoi(idStr)
+// Transcript renders the finished game at /games/{id}/transcript as
+// Markdown or plain text, chosen by ?format= or else the Accept header
+func Transcript(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
 	if err != nil {
-		WSError(ws, err)
+		HTTPStatusError(w, err, http.StatusBadRequest)
 		return
 	}
-	g, err := game.GetGame(id)
+	g, ok := lookupGame(w, id)
+	if !ok {
+		return
+	}
+	if g.State() != game.StateFinished {
+		HTTPStatusError(w, errors.New("game has not finished"), http.StatusConflict)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = game.FormatMarkdown
+		if strings.Contains(r.Header.Get("Accept"), "text/plain") {
+			format = game.FormatText
+		}
+	}
+	contentType := "text/markdown; charset=utf-8"
+	if format == game.FormatText {
+		contentType = "text/plain; charset=utf-8"
+	}
+	var buf bytes.Buffer
+	err = g.WriteTranscript(&buf, format)
 	if err != nil {
-		WSError(ws, err)
+		HTTPStatusError(w, err, http.StatusBadRequest)
 		return
 	}
+	w.Header().Add("Content-Type", contentType)
+	w.Write(buf.Bytes())
+}
 
-	gameConn := &GameConn{
-		GameID:    id,
-		Conn:      ws,
-		WriteChan: make(chan *game.Game),
+// Recap serves the highlights of the finished game at /games/{id}/recap,
+// for clients' share screens.
+func Recap(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, ok := lookupGame(w, id)
+	if !ok {
+		return
+	}
+	recap, err := g.Recap()
+	if err == game.ErrNotFinished {
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
 	}
-	hub.Register(gameConn)
-	go gameConn.write(hub)
-	hub.Broadcast <- g
-	err = gameConn.read(hub)
 	if err != nil {
-		WSError(ws, err)
+		HTTPError(w, err)
+		return
+	}
+	j, err := json.Marshal(recap)
+	if err != nil {
+		HTTPError(w, err)
 		return
 	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
 }
 
-func (gc *GameConn) write(hub *Hub) error {
-	for g := range hub.Broadcast {
-		for _, client := range hub.ClientMap[g.ID] {
-			err := websocket.JSON.Send(client.Conn, g)
-			if err != nil {
-				hub.Unregister(client)
-				// continue
-			}
-		}
+// History serves the summary of the finished game at /games/{id}/history,
+// which outlives the game itself for a while.
+func History(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
 	}
-	return nil
+	summary, err := game.GetHistory(id)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusNotFound)
+		return
+	}
+	j, err := json.Marshal(summary)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
 }
 
-func (gc *GameConn) read(hub *Hub) error {
-	defer func() {
-		hub.Unregister(gc)
-		gc.Conn.Close()
-	}()
-	for {
-		var p game.Play
-		err := websocket.JSON.Receive(gc.Conn, &p)
-		if err != nil {
-			return err
-		}
-		g, err := game.GetGame(gc.GameID)
-		if err != nil {
-			return err
-		}
-		if p.Ping != "" {
-			// ping noop
-		} else if p.Vote != "" && g.CurrentAction == game.VOTE {
-			g.Vote(p.Name, p.Vote)
-		} else if p.Punchline != "" && g.CurrentAction == game.PLAY {
-			g.Play(p.Name, p.Punchline)
-		} else {
-			log.Print("wrong action") // TODO err
-			return errors.New("invalid action")
-		}
-		hub.Broadcast <- g
+// Games lists games that are open to join, for the lobby
+func Games(w http.ResponseWriter, r *http.Request) {
+	writeSummaries(w, nil, game.StateOpen)
+}
+
+// LeagueGames lists /leagues/{slug}'s finished games.
+func LeagueGames(w http.ResponseWriter, r *http.Request) {
+	slug := r.URL.Query().Get("slug")
+	err := game.ValidateLeague(slug)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	j, err := json.Marshal(game.LeagueSummaries(slug))
+	if err != nil {
+		HTTPError(w, err)
+		return
 	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// LeagueStats aggregates /leagues/{slug}'s finished games.
+func LeagueStats(w http.ResponseWriter, r *http.Request) {
+	slug := r.URL.Query().Get("slug")
+	err := game.ValidateLeague(slug)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	j, err := json.Marshal(game.GetLeagueStats(slug))
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// Features lists every feature flag and whether games can use it
+func Features(w http.ResponseWriter, r *http.Request) {
+	j, err := json.Marshal(flags.All())
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// AddPlayer joins a player to a game, then pushes the game to the players
+// already in it.
+func AddPlayer(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	var playerRequest PlayerRequest
+	err := json.NewDecoder(r.Body).Decode(&playerRequest)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, err := playerRequest.game()
+	if err == game.ErrGameGone {
+		HTTPStatusError(w, err, http.StatusGone)
+		return
+	}
+	if err == game.ErrInvalidCode {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	playerRequest.Player = game.NormalizePlayerName(playerRequest.Player)
+	playerRequest.Team = game.NormalizePlayerName(playerRequest.Team)
+	err = g.AddPlayer(game.Player{Name: playerRequest.Player, Team: playerRequest.Team}, playerRequest.PIN)
+	if err == game.ErrGameFull {
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	}
+	if err == game.ErrInvalidPlayerName || err == game.ErrInvalidTeam || err == game.ErrNoTeams {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	if err == game.ErrWrongPIN {
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	token, err := issueToken(g, playerRequest.Player)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	reconnectToken, err := g.IssueReconnectToken(playerRequest.Player)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(playerRequest.Player, game.HandDealt)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add(TokenHeader, token)
+	w.Header().Add(ReconnectTokenHeader, reconnectToken)
+	w.Write(j)
+}
+
+type ReconnectRequest struct {
+	PlayerRequest
+	ReconnectToken string `json:"reconnectToken"` // as sent when they joined
+}
+
+// Reconnect lets a player back into the game in a ReconnectRequest with
+// the reconnect token they were given on joining, e.g. after losing their
+// player token with a dead phone. It returns the game, hand intact, with a
+// new player token.
+func Reconnect(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	var reconnectRequest ReconnectRequest
+	err := json.NewDecoder(r.Body).Decode(&reconnectRequest)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, err := reconnectRequest.game()
+	switch err {
+	case nil:
+	case game.ErrGameGone:
+		HTTPStatusError(w, err, http.StatusGone)
+		return
+	case game.ErrInvalidCode:
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	default:
+		HTTPStatusError(w, err, http.StatusNotFound)
+		return
+	}
+	player, err := g.Reconnect(reconnectRequest.Player, reconnectRequest.ReconnectToken)
+	switch err {
+	case nil:
+	case game.ErrPlayerNotFound:
+		HTTPStatusError(w, err, http.StatusNotFound)
+		return
+	case game.ErrWrongReconnectToken:
+		HTTPStatusError(w, err, http.StatusUnauthorized)
+		return
+	default:
+		HTTPError(w, err)
+		return
+	}
+	token, err := issueToken(g, player.Name)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(player.Name, game.HandDealt)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add(TokenHeader, token)
+	w.Write(j)
+}
+
+// AddSpectator lets someone watch the game in a PlayerRequest, returning it
+// with a token for its websocket. Spectators can't play, and only vote in
+// games with audience voting.
+func AddSpectator(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	var playerRequest PlayerRequest
+	err := json.NewDecoder(r.Body).Decode(&playerRequest)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, err := playerRequest.game()
+	switch err {
+	case nil:
+	case game.ErrGameGone:
+		HTTPStatusError(w, err, http.StatusGone)
+		return
+	case game.ErrInvalidCode:
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	default:
+		HTTPStatusError(w, err, http.StatusNotFound)
+		return
+	}
+	playerRequest.Player = game.NormalizePlayerName(playerRequest.Player)
+	err = g.AddSpectator(playerRequest.Player, playerRequest.PIN)
+	if err == game.ErrInvalidPlayerName {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	if err == game.ErrWrongPIN {
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	}
+	token, err := issueToken(g, playerRequest.Player)
+	if err != nil {
+		// without a token they couldn't watch, so they aren't left watching
+		g.RemovePlayer(playerRequest.Player)
+		HTTPStatusError(w, err, http.StatusInternalServerError)
+		return
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(playerRequest.Player, game.HandDealt)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add(TokenHeader, token)
+	w.Write(j)
+}
+
+// Leave takes the authenticated player out of their game and closes their
+// websockets.
+func Leave(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	err := g.RemovePlayer(claims.Player)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusNotFound)
+		return
+	}
+	for _, client := range hub.Clients(g.ID) {
+		if client.Observer == "" && client.Player == claims.Player {
+			client.Conn.Close()
+		}
+	}
+	hub.Push(g)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type KickRequest struct {
+	Player string `json:"player"`
+}
+
+// Kick lets the host remove a player or spectator from their game, closing
+// the kicked player's websockets, then pushes the game to everyone left.
+func Kick(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	var kickRequest KickRequest
+	err := json.NewDecoder(r.Body).Decode(&kickRequest)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	err = g.Kick(claims.Player, kickRequest.Player)
+	switch err {
+	case nil:
+	case game.ErrNotHost:
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	case game.ErrPlayerNotFound:
+		HTTPStatusError(w, err, http.StatusNotFound)
+		return
+	default:
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	for _, client := range hub.Clients(g.ID) {
+		if client.Observer == "" && client.Player == kickRequest.Player {
+			client.Conn.Close()
+		}
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(claims.Player, game.HandDealt)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+type RenameRequest struct {
+	Name string `json:"name"`
+}
+
+// Rename changes the authenticated player's name in their game, returning
+// it with a token for the new name. Their websockets are closed so they
+// reconnect with it.
+func Rename(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	var renameRequest RenameRequest
+	err := json.NewDecoder(r.Body).Decode(&renameRequest)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	renameRequest.Name = game.NormalizePlayerName(renameRequest.Name)
+	err = g.RenamePlayer(claims.Player, renameRequest.Name)
+	switch err {
+	case nil:
+	case game.ErrInvalidPlayerName:
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	case game.ErrPlayerNotFound:
+		HTTPStatusError(w, err, http.StatusNotFound)
+		return
+	case game.ErrNameTaken:
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	default:
+		HTTPError(w, err)
+		return
+	}
+	token, err := issueToken(g, renameRequest.Name)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	for _, client := range hub.Clients(g.ID) {
+		if client.Observer == "" && client.Player == claims.Player {
+			client.Conn.Close()
+		}
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(renameRequest.Name, game.HandDealt)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add(TokenHeader, token)
+	w.Write(j)
+}
+
+// Game serves a player's websocket. It must be wrapped in PlayerAuth; plays
+// and votes are attributed to the authenticated player, not the name sent.
+// The sort param, dealt or alpha, orders the player's hand.
+func Game(ws *websocket.Conn, hub *Hub) {
+	claims, ok := PlayerFromContext(ws.Request().Context())
+	if !ok {
+		WSError(ws, errUnauthorized)
+		return
+	}
+	g, err := game.GetGame(claims.GameID)
+	if err != nil {
+		WSError(ws, err)
+		return
+	}
+	handOrder := ws.Request().URL.Query().Get("sort")
+	err = game.ValidateHandOrder(handOrder)
+	if err != nil {
+		WSError(ws, err)
+		return
+	}
+	err = g.Ping(claims.Player)
+	if err != nil {
+		WSError(ws, err)
+		return
+	}
+
+	gameConn := &GameConn{
+		GameID:    claims.GameID,
+		Player:    claims.Player,
+		HandOrder: handOrder,
+		Conn:      ws,
+		WriteChan: make(chan *game.Game),
+	}
+	hub.Register(gameConn)
+	go gameConn.write(hub)
+	hub.Broadcast <- g
+	err = gameConn.read(hub)
+	if err != nil {
+		WSError(ws, err)
+		return
+	}
+}
+
+func (gc *GameConn) write(hub *Hub) error {
+	for g := range hub.Broadcast {
+		for _, client := range hub.Clients(g.ID) {
+			err := client.send(g)
+			if err != nil {
+				hub.Unregister(client)
+				// continue
+			}
+		}
+	}
+	return nil
+}
+
+func (gc *GameConn) read(hub *Hub) error {
+	defer func() {
+		hub.Unregister(gc)
+		gc.Conn.Close()
+		gc.disconnect(hub)
+	}()
+	for {
+		var p game.Play
+		err := websocket.JSON.Receive(gc.Conn, &p)
+		if err != nil {
+			return err
+		}
+		g, err := game.GetGame(gc.GameID)
+		if err != nil {
+			return err
+		}
+		p.Name = gc.Player
+		if p.Ping != "" {
+			err = g.Ping(p.Name)
+		} else if p.Vote != "" || len(p.Votes) > 0 {
+			votes := p.Votes
+			if len(votes) == 0 {
+				votes = []game.Card{p.Vote}
+			}
+			err = g.Vote(p.Name, votes...)
+		} else if p.Punchline != "" {
+			err = g.Play(p.Name, p.Punchline)
+		} else if p.WriteIn != "" {
+			err = g.PlayWriteIn(p.Name, p.WriteIn)
+		} else if p.Retract {
+			err = g.RetractPlay(p.Name)
+		} else {
+			log.Print("wrong action") // TODO err
+			return errInvalidAction
+		}
+		switch err {
+		case game.ErrTooManyActions, game.ErrVoteCount, game.ErrOwnCard, game.ErrDuplicateVote, game.ErrGameFinished,
+			game.ErrCardNotInHand, game.ErrPlayerNotFound, game.ErrWrongPhase,
+			game.ErrAlreadyPlayed, game.ErrNotPlayed, game.ErrSpectator, game.ErrNotOnBallot, game.ErrGamePaused,
+			game.ErrJudge, game.ErrNotJudge, game.ErrInvalidWriteIn, game.ErrNoWriteIns, game.ErrWriteInTaken,
+			game.ErrNotInSuddenDeath, game.ErrPairPlay:
+			WSError(gc.Conn, err)
+			continue
+		}
+		hub.Broadcast <- g
+	}
+}
+
+// disconnect marks the player disconnected once their last websocket closes.
+func (gc *GameConn) disconnect(hub *Hub) {
+	for _, client := range hub.Clients(gc.GameID) {
+		if client.Observer == "" && client.Player == gc.Player {
+			return
+		}
+	}
+	g, err := game.GetGame(gc.GameID)
+	if err != nil {
+		return
+	}
+	if g.Disconnect(gc.Player) == nil {
+		hub.Push(g)
+	}
+}
+
+// TimersRequest sets the play and vote phase time limits in seconds. 0 turns
+// a phase's timer off; otherwise limits are 15 to 600 seconds.
+type TimersRequest struct {
+	PlaySeconds int `json:"playSeconds"`
+	VoteSeconds int `json:"voteSeconds"`
+}
+
+// SetTimers lets the authenticated creator of /games/{id} change its timers
+// between rounds, then pushes the game to its players.
+func SetTimers(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	var timersRequest TimersRequest
+	err := json.NewDecoder(r.Body).Decode(&timersRequest)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	err = g.SetTimers(claims.Player, timersRequest.PlaySeconds, timersRequest.VoteSeconds)
+	switch err {
+	case nil:
+	case game.ErrNotHost:
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	case game.ErrMidRound, game.ErrGameFinished:
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	default:
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(claims.Player, game.HandDealt)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// CleanlinessRequest changes the highest card rating for the rest of a game.
+// SwapHands also swaps cards rated above it out of players' hands.
+type CleanlinessRequest struct {
+	Cleanliness string `json:"cleanliness"`
+	SwapHands   bool   `json:"swapHands"`
+}
+
+// SetCleanliness lets the authenticated creator of /games/{id} change its
+// cleanliness rating for the rounds still to come, then pushes the game to
+// its players.
+func SetCleanliness(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	var cleanlinessRequest CleanlinessRequest
+	err := json.NewDecoder(r.Body).Decode(&cleanlinessRequest)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	err = g.SetCleanliness(r.Context(), claims.Player, cleanlinessRequest.Cleanliness, cleanlinessRequest.SwapHands)
+	switch err {
+	case nil:
+	case game.ErrNotHost:
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	case game.ErrUnknownRating:
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	case game.ErrGameFinished, game.ErrTooFewSetups:
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	default:
+		HTTPError(w, err)
+		return
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(claims.Player, game.HandDealt)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// GameState returns /games/{id} as the authenticated player sees it. The
+// sort param, dealt or alpha, orders their hand as it does on /play.
+func GameState(w http.ResponseWriter, r *http.Request) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	handOrder := r.URL.Query().Get("sort")
+	err := game.ValidateHandOrder(handOrder)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	j, err := g.ViewJSON(claims.Player, handOrder)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// Pacing returns how long players have been taking in /games/{id}, for its
+// host only.
+func Pacing(w http.ResponseWriter, r *http.Request) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	pacing, err := g.Pacing(claims.Player)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	}
+	j, err := json.Marshal(pacing)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// RotateHands lets the host of /games/{id} have everyone pass their hand to
+// the left between rounds, in games with the chaos feature.
+func RotateHands(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	err := g.RotateHands(claims.Player)
+	switch err {
+	case nil:
+	case game.ErrNotHost, flags.ErrDisabled:
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	case game.ErrMidRound, game.ErrGameFinished:
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	default:
+		HTTPError(w, err)
+		return
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(claims.Player, game.HandDealt)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// Pause lets the host of /games/{id} pause it, then pushes the game to its
+// players.
+func Pause(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	err := g.Pause(claims.Player)
+	switch err {
+	case nil:
+	case game.ErrNotHost:
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	case game.ErrGamePaused, game.ErrWrongPhase, game.ErrGameFinished:
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	default:
+		HTTPError(w, err)
+		return
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(claims.Player, game.HandDealt)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// Resume lets the host of /games/{id} carry on with it after Pause, then
+// pushes the game to its players.
+func Resume(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	err := g.Resume(claims.Player)
+	switch err {
+	case nil:
+	case game.ErrNotHost:
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	case game.ErrNotPaused:
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	default:
+		HTTPError(w, err)
+		return
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(claims.Player, game.HandDealt)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// SkipSetup adds the authenticated player's vote to redraw the setups of
+// /games/{id}'s current round, then pushes the game to its players.
+func SkipSetup(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	err := g.SkipSetup(claims.Player)
+	switch err {
+	case nil:
+	case game.ErrSpectator, game.ErrJoinedMidRound:
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	case game.ErrWrongPhase, game.ErrGameFinished, game.ErrNoSkipsLeft, game.ErrTooFewSetups:
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	default:
+		HTTPError(w, err)
+		return
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(claims.Player, game.HandDealt)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// Mulligan swaps the authenticated player's hand in /games/{id} for a fresh
+// one, then pushes the game to its players.
+func Mulligan(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	err := g.Mulligan(claims.Player)
+	switch err {
+	case nil:
+	case game.ErrSpectator:
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	case game.ErrGameFinished, game.ErrMulliganUsed, game.ErrMulliganPlayed, game.ErrTooFewPunchlines:
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	default:
+		HTTPError(w, err)
+		return
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(claims.Player, game.HandDealt)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+type ExtendRequest struct {
+	Rounds int `json:"rounds"`
+}
+
+// ExtendRounds adds rounds to /games/{id} at the request of the player who
+// created it, then pushes the game to its players.
+func ExtendRounds(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	var extendRequest ExtendRequest
+	err := json.NewDecoder(r.Body).Decode(&extendRequest)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	err = g.ExtendRounds(r.Context(), claims.Player, extendRequest.Rounds)
+	switch {
+	case err == nil:
+	case err == game.ErrNotHost:
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	case errors.Is(err, game.ErrInvalidRounds):
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	case err == game.ErrGameFinished, err == game.ErrMidRound, err == game.ErrTooFewSetups, err == game.ErrSuddenDeath, err == game.ErrTargetScore:
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	default:
+		HTTPError(w, err)
+		return
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(claims.Player, game.HandDealt)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+type ReadyRequest struct {
+	Ready bool `json:"ready"`
+}
+
+// Ready sets whether the authenticated player is ready for /games/{id} to
+// start, then pushes the lobby to its players.
+func Ready(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	var readyRequest ReadyRequest
+	err := json.NewDecoder(r.Body).Decode(&readyRequest)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	err = g.SetReady(claims.Player, readyRequest.Ready)
+	switch err {
+	case nil:
+	case game.ErrNotInLobby:
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	default:
+		HTTPStatusError(w, err, http.StatusNotFound)
+		return
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(claims.Player, game.HandDealt)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+type StartRequest struct {
+	Force bool `json:"force"` // start even if some players aren't ready
+}
+
+// Start lets the host of /games/{id} start it from the lobby, then pushes
+// the game to its players. The StartRequest body is optional.
+func Start(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	var startRequest StartRequest
+	err := json.NewDecoder(r.Body).Decode(&startRequest)
+	if err != nil && err != io.EOF {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	err = g.Start(claims.Player, startRequest.Force)
+	switch err {
+	case nil:
+	case game.ErrNotHost:
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	case game.ErrNotInLobby, game.ErrPlayersNotReady, game.ErrNotEnoughPlayers:
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	default:
+		HTTPError(w, err)
+		return
+	}
+	hub.Push(g)
+	j, err := g.ViewJSON(claims.Player, game.HandDealt)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+type RematchRequest struct {
+	Rounds int `json:"rounds"`
+}
+
+// Rematch starts a new game with the players and settings of the
+// authenticated player's finished game, or finds the one already started,
+// and returns it with a token for it. The finished game is pushed so the
+// other players see its rematchId and can follow.
+func Rematch(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	var rematchRequest RematchRequest
+	err := json.NewDecoder(r.Body).Decode(&rematchRequest)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	next, err := g.Rematch(r.Context(), rematchRequest.Rounds)
+	switch {
+	case err == nil:
+	case err == game.ErrNotFinished:
+		HTTPStatusError(w, err, http.StatusConflict)
+		return
+	case errors.Is(err, game.ErrInvalidRounds):
+		HTTPStatusError(w, err, http.StatusBadRequest)
+		return
+	default:
+		HTTPError(w, err)
+		return
+	}
+	token, err := issueToken(next, claims.Player)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	reconnectToken, err := next.IssueReconnectToken(claims.Player)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	hub.Push(g)
+	j, err := next.ViewJSON(claims.Player, game.HandDealt)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add(TokenHeader, token)
+	w.Header().Add(ReconnectTokenHeader, reconnectToken)
+	w.Write(j)
 }
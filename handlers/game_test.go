@@ -0,0 +1,430 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/auth"
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stinkyfingers/differencebetween/api/flags"
+	"github.com/stinkyfingers/differencebetween/api/game"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+func TestCreateGameRejectsDisabledFeature(t *testing.T) {
+	for feature, code := range map[string]string{"chaos": "feature_disabled", "nope": "unknown_feature"} {
+		w := httptest.NewRecorder()
+		CreateGame(w, httptest.NewRequest("POST", "/game", strings.NewReader(`{"player":"al","rounds":3,"features":["`+feature+`"]}`)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var e Error
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&e))
+		assert.Equal(t, code, e.Code)
+	}
+}
+
+func TestValidateGameListsEveryViolation(t *testing.T) {
+	allowLocalFetches(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("a,G\nb,G\nc,G\nd,G\ne,G\nf,G\ng,G\n"))
+	}))
+	defer server.Close()
+	count := game.Count()
+
+	w := httptest.NewRecorder()
+	ValidateGame(w, httptest.NewRequest("POST", "/games/validate", strings.NewReader(
+		`{"player":"al","rounds":4,"maxPlayers":2,"features":["chaos"],"playSeconds":5,"deck":{"setupsUrl":"`+server.URL+`/s","punchlinesUrl":"`+server.URL+`/p"}}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result ValidationResult
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+	assert.False(t, result.Valid)
+	assert.Equal(t, game.Capacity{MaxRounds: 3, MaxPlayers: 1}, result.Capacity)
+	assert.Equal(t, []Error{
+		{Message: flags.ErrDisabled.Error(), Code: "feature_disabled"},
+		{Message: game.ErrInvalidTimer.Error()},
+		{Message: game.ErrTooFewSetups.Error(), Code: "deck_load"},
+		{Message: game.ErrTooManyPlayers.Error() + ": at most 1", Code: "too_many_players"},
+	}, result.Violations)
+
+	w = httptest.NewRecorder()
+	ValidateGame(w, httptest.NewRequest("POST", "/games/validate", strings.NewReader(
+		`{"player":"al","rounds":3,"deck":{"setupsUrl":"`+server.URL+`/s","punchlinesUrl":"`+server.URL+`/p"}}`)))
+	result = ValidationResult{}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+	assert.True(t, result.Valid)
+	assert.Empty(t, result.Violations)
+	assert.Equal(t, count, game.Count(), "nothing created")
+}
+
+func TestCreateGameRejectsPrivateDecks(t *testing.T) {
+	for _, deck := range []string{"http://169.254.169.254/latest/meta-data", "http://localhost:8080/admin", "file:///etc/passwd"} {
+		w := httptest.NewRecorder()
+		CreateGame(w, httptest.NewRequest("POST", "/game", strings.NewReader(`{"player":"al","rounds":3,"deck":{"setupsUrl":"`+deck+`","punchlinesUrl":"`+deck+`"}}`)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, deck)
+		var e Error
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&e))
+		assert.Equal(t, "invalid_url", e.Code, deck)
+	}
+}
+
+func TestCreateGameRejectsInvalidPlayerName(t *testing.T) {
+	for _, name := range []string{"", "   ", strings.Repeat("x", 33)} {
+		w := httptest.NewRecorder()
+		CreateGame(w, httptest.NewRequest("POST", "/game", strings.NewReader(`{"player":"`+name+`","rounds":3}`)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, name)
+		var e Error
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&e))
+		assert.Equal(t, "invalid_player_name", e.Code)
+	}
+}
+
+func TestAddPlayerHidesOtherHands(t *testing.T) {
+	g := newTestGame(t)
+	defer game.Delete(g.ID)
+	w := httptest.NewRecorder()
+	AddPlayer(w, httptest.NewRequest("POST", "/player", strings.NewReader(`{"player":"bob","code":"`+g.Code+`"}`)), NewHub())
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.Bytes()
+	var view game.GameView
+	assert.NoError(t, json.Unmarshal(body, &view))
+	assert.Nil(t, view.Players[0].Punchlines)
+	assert.Equal(t, len(g.Players[0].Punchlines), view.Players[0].Cards)
+	assert.Equal(t, g.Players[1].Punchlines, view.Players[1].Punchlines)
+	assertHandsHidden(t, g, "bob", body)
+}
+
+// assertHandsHidden fails if body, a JSON response or push sent to viewer,
+// has a card from anyone else's hand or from g's deck anywhere in it, as a
+// value or a key.
+func assertHandsHidden(t *testing.T, g *game.Game, viewer string, body []byte) {
+	t.Helper()
+	j, err := json.Marshal(g)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var full struct {
+		Players []struct {
+			Name       string      `json:"name"`
+			Punchlines []game.Card `json:"punchlines"`
+		} `json:"players"`
+		Punchlines []game.Card `json:"punchlines"`
+	}
+	assert.NoError(t, json.Unmarshal(j, &full))
+	hidden := make(map[string]string)
+	for _, card := range full.Punchlines {
+		hidden[string(card)] = "the deck"
+	}
+	for _, player := range full.Players {
+		if player.Name == viewer {
+			continue
+		}
+		for _, card := range player.Punchlines {
+			hidden[string(card)] = player.Name + "'s hand"
+		}
+	}
+
+	var v interface{}
+	if !assert.NoError(t, json.Unmarshal(body, &v)) {
+		return
+	}
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case string:
+			if owner, ok := hidden[v]; ok {
+				t.Errorf("%s sees %q from %s", viewer, v, owner)
+			}
+		case []interface{}:
+			for _, e := range v {
+				walk(e)
+			}
+		case map[string]interface{}:
+			for k, e := range v {
+				walk(k)
+				walk(e)
+			}
+		}
+	}
+	walk(v)
+}
+
+func TestResponsesHideOtherHands(t *testing.T) {
+	g := newTestGame(t)
+	defer game.Delete(g.ID)
+	hub := NewHub()
+	id := strconv.Itoa(g.ID)
+	key, _, err := g.AddObserver("al")
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	AddPlayer(w, httptest.NewRequest("POST", "/player", strings.NewReader(`{"player":"bob","code":"`+g.Code+`"}`)), hub)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertHandsHidden(t, g, "bob", w.Body.Bytes())
+	w = httptest.NewRecorder()
+	AddSpectator(w, httptest.NewRequest("POST", "/spectator", strings.NewReader(`{"player":"sam","code":"`+g.Code+`"}`)), hub)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertHandsHidden(t, g, "sam", w.Body.Bytes())
+	var view game.GameView
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &view))
+	assert.Equal(t, g.ID, view.ID)
+	assert.Equal(t, []string{"sam"}, view.Spectators)
+	for _, player := range view.Players {
+		assert.Nil(t, player.Punchlines, player.Name)
+	}
+	claims, err := authenticate(w.Header().Get(TokenHeader), id)
+	if assert.NoError(t, err, "the spectator's token") {
+		assert.Equal(t, "sam", claims.Player)
+	}
+
+	// what each connection is sent when the game changes
+	server := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		query := ws.Request().URL.Query()
+		gc := &GameConn{Conn: ws, GameID: g.ID, Player: query.Get("player"), Observer: query.Get("observer"), HandOrder: game.HandAlpha}
+		gc.send(g)
+	}))
+	defer server.Close()
+	pushes := func() {
+		t.Helper()
+		for _, query := range []string{"player=al", "player=bob", "player=sam", "observer=" + key} {
+			ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/?"+query, "", server.URL)
+			if !assert.NoError(t, err) {
+				return
+			}
+			var msg []byte
+			assert.NoError(t, websocket.Message.Receive(ws, &msg))
+			ws.Close()
+			assertHandsHidden(t, g, strings.TrimPrefix(query, "player="), msg)
+		}
+	}
+	call := func(viewer string, handler func(http.ResponseWriter, *http.Request, *Hub), body string) {
+		t.Helper()
+		token, err := issueToken(g, viewer)
+		assert.NoError(t, err)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/games/"+id+"?id="+id+"&n=1", strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer "+token)
+		PlayerAuth(func(w http.ResponseWriter, r *http.Request) { handler(w, r, hub) })(w, r)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assertHandsHidden(t, g, viewer, w.Body.Bytes())
+	}
+
+	pushes()
+	call("bob", Mulligan, `{}`)
+	call("bob", PostMessage, `{"text":"hi"}`)
+	call("al", SetTimers, `{"playSeconds":60,"voteSeconds":60}`)
+	for !g.Finished {
+		for _, player := range g.Players {
+			assert.NoError(t, g.Play(player.Name, player.Punchlines[0]))
+			pushes()
+		}
+		for _, player := range g.Players {
+			assert.NoError(t, g.Vote(player.Name, g.Rounds[g.RoundsRemaining-1].Ballot[0]))
+			pushes()
+		}
+		if first := g.Rounds[len(g.Rounds)-1]; first.Comment == "" {
+			call(first.Winner, CommentRound, `{"comment":"nice"}`)
+		}
+	}
+	for _, handler := range []http.HandlerFunc{History, Recap} {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest("GET", "/games/"+id+"?id="+id, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assertHandsHidden(t, g, "", w.Body.Bytes())
+	}
+}
+
+func TestGameStateSortsTheHand(t *testing.T) {
+	g := newTestGame(t)
+	defer game.Delete(g.ID)
+	id := strconv.Itoa(g.ID)
+	token, err := issueToken(g, "al")
+	assert.NoError(t, err)
+	get := func(order string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/games/"+id+"?id="+id+"&sort="+order, nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		PlayerAuth(GameState)(w, r)
+		return w
+	}
+
+	// dealt in reverse
+	hand := g.Players[0].Punchlines
+	sort.Slice(hand, func(i, j int) bool { return hand[i] > hand[j] })
+	alpha := append([]game.Card(nil), hand...)
+	sort.Slice(alpha, func(i, j int) bool { return alpha[i] < alpha[j] })
+	for order, want := range map[string][]game.Card{"": hand, game.HandDealt: hand, game.HandAlpha: alpha} {
+		w := get(order)
+		assert.Equal(t, http.StatusOK, w.Code, order)
+		var view game.GameView
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &view))
+		assert.Equal(t, want, view.Players[0].Punchlines, order)
+	}
+	assert.Equal(t, http.StatusBadRequest, get("nope").Code)
+}
+
+func TestJoiningNeedsThePIN(t *testing.T) {
+	w := httptest.NewRecorder()
+	CreateGame(w, httptest.NewRequest("POST", "/game", strings.NewReader(`{"player":"al","rounds":1,"pin":"123"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	g := newTestGame(t)
+	defer game.Delete(g.ID)
+	game.WithPIN("1234")(g)
+	hub := NewHub()
+	for path, handler := range map[string]func(http.ResponseWriter, *http.Request, *Hub){"/player": AddPlayer, "/spectator": AddSpectator} {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest("POST", path, strings.NewReader(`{"player":"bob","code":"`+g.Code+`","pin":"0000"}`)), hub)
+		assert.Equal(t, http.StatusForbidden, w.Code, path)
+		var e Error
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&e))
+		assert.Equal(t, "wrong_pin", e.Code)
+	}
+	w = httptest.NewRecorder()
+	AddPlayer(w, httptest.NewRequest("POST", "/player", strings.NewReader(`{"player":"bob","code":"`+g.Code+`","pin":"1234"}`)), hub)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestReconnect(t *testing.T) {
+	g := newTestGame(t)
+	defer game.Delete(g.ID)
+	hub := NewHub()
+	w := httptest.NewRecorder()
+	AddPlayer(w, httptest.NewRequest("POST", "/player", strings.NewReader(`{"player":"bob","code":"`+g.Code+`"}`)), hub)
+	assert.Equal(t, http.StatusOK, w.Code)
+	reconnectToken := w.Header().Get(ReconnectTokenHeader)
+	assert.NotEmpty(t, reconnectToken)
+
+	w = httptest.NewRecorder()
+	AddPlayer(w, httptest.NewRequest("POST", "/player", strings.NewReader(`{"player":"bob","code":"`+g.Code+`"}`)), hub)
+	assert.Contains(t, w.Body.String(), game.ErrNameTaken.Error())
+
+	w = httptest.NewRecorder()
+	Reconnect(w, httptest.NewRequest("POST", "/reconnect", strings.NewReader(`{"player":"bob","code":"`+g.Code+`","reconnectToken":"nope"}`)), hub)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	var e Error
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&e))
+	assert.Equal(t, "unauthorized", e.Code)
+
+	w = httptest.NewRecorder()
+	Reconnect(w, httptest.NewRequest("POST", "/reconnect", strings.NewReader(`{"player":"bob","code":"`+g.Code+`","reconnectToken":"`+reconnectToken+`"}`)), hub)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Header().Get(TokenHeader))
+	var view game.GameView
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&view))
+	assert.Equal(t, g.Players[1].Punchlines, view.Players[1].Punchlines)
+}
+
+func TestRematchHidesOtherHands(t *testing.T) {
+	g := newTestGame(t)
+	defer game.Delete(g.ID)
+	hub := NewHub()
+	w := httptest.NewRecorder()
+	AddPlayer(w, httptest.NewRequest("POST", "/player", strings.NewReader(`{"player":"bob","code":"`+g.Code+`"}`)), hub)
+	assert.Equal(t, http.StatusOK, w.Code)
+	for !g.Finished {
+		for _, player := range g.Players {
+			assert.NoError(t, g.Play(player.Name, player.Punchlines[0]))
+		}
+		for _, player := range g.Players {
+			assert.NoError(t, g.Vote(player.Name, g.Rounds[g.RoundsRemaining-1].Ballot[0]))
+		}
+	}
+	token, err := issueToken(g, "bob")
+	assert.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/games/rematch?id="+strconv.Itoa(g.ID), strings.NewReader(`{"rounds":1}`))
+	r.Header.Set("Authorization", "Bearer "+token)
+	PlayerAuth(func(w http.ResponseWriter, r *http.Request) { Rematch(w, r, hub) })(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	next, err := game.GetGame(*g.RematchID)
+	assert.NoError(t, err)
+	defer game.Delete(next.ID)
+	body := w.Body.Bytes()
+	var view game.GameView
+	assert.NoError(t, json.Unmarshal(body, &view))
+	assert.Equal(t, next.Players[1].Punchlines, view.Players[1].Punchlines)
+	assertHandsHidden(t, next, "bob", body)
+}
+
+func TestSignedTokensExpireWithTheGameAndAreRevoked(t *testing.T) {
+	old := config.Current()
+	g := newTestGame(t)
+	defer game.Delete(g.ID)
+	cfg := *config.Current()
+	cfg.TokenSecret = "secret"
+	cfg.GameTTL = time.Hour
+	config.Set(&cfg)
+	t.Cleanup(func() { config.Set(old) })
+	id := strconv.Itoa(g.ID)
+
+	tokens := make(map[string]string)
+	for _, name := range []string{"al", "bob", "cy", "dee"} {
+		if name != "al" {
+			assert.NoError(t, g.AddPlayer(game.Player{Name: name}, ""))
+		}
+		token, err := issueToken(g, name)
+		assert.NoError(t, err)
+		tokens[name] = token
+	}
+	claims, err := authenticate(tokens["bob"], id)
+	assert.NoError(t, err)
+	assert.Equal(t, g.Created.Add(time.Hour).Unix(), claims.Expires)
+
+	assert.NoError(t, g.Kick("al", "bob"))
+	assert.NoError(t, g.RemovePlayer("cy"))
+	assert.NoError(t, g.RenamePlayer("dee", "di"))
+	for _, name := range []string{"bob", "cy", "dee"} {
+		_, err := authenticate(tokens[name], id)
+		assert.Equal(t, errUnauthorized, err, name)
+	}
+	_, err = authenticate(tokens["al"], id)
+	assert.NoError(t, err)
+	renamed, err := issueToken(g, "di")
+	assert.NoError(t, err)
+	_, err = authenticate(renamed, id)
+	assert.NoError(t, err)
+
+	// someone joining under a revoked name gets a token that works
+	assert.NoError(t, g.AddPlayer(game.Player{Name: "bob"}, ""))
+	rejoined, err := issueToken(g, "bob")
+	assert.NoError(t, err)
+	_, err = authenticate(rejoined, id)
+	assert.NoError(t, err)
+	_, err = authenticate(tokens["bob"], id)
+	assert.Equal(t, errUnauthorized, err)
+}
+
+func TestDeletedGamesAreGone(t *testing.T) {
+	g := newTestGame(t)
+	id := strconv.Itoa(g.ID)
+	assert.NoError(t, game.Delete(g.ID))
+	hub := NewHub()
+	for name, handler := range map[string]http.HandlerFunc{
+		"transcript": Transcript,
+		"pause":      func(w http.ResponseWriter, r *http.Request) { Pause(w, r, hub) },
+		"kick":       func(w http.ResponseWriter, r *http.Request) { Kick(w, r, hub) },
+		"observers":  Observers,
+		"recap":      Recap,
+	} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/games/"+id+"?id="+id, strings.NewReader(`{"player":"bob"}`))
+		handler(w, r.WithContext(context.WithValue(r.Context(), claimsKey, &auth.Claims{GameID: g.ID, Player: "al"})))
+		assert.Equal(t, http.StatusGone, w.Code, name)
+		var e Error
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&e))
+		assert.Equal(t, "game_gone", e.Code, name)
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/games/-1?id=-1", nil)
+	Transcript(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code, "never existed")
+}
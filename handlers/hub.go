@@ -1,39 +1,118 @@
 package handlers
 
 import (
+	"sync"
+
 	"github.com/stinkyfingers/differencebetween/api/game"
 	"golang.org/x/net/websocket"
 )
 
+// Hub tracks each game's websockets. Its methods are safe to call from any
+// goroutine; connections are only ever handed out as copies.
 type Hub struct {
-	ClientMap map[int][]*GameConn
 	Broadcast chan *game.Game
+
+	mu        sync.Mutex
+	clientMap map[int][]*GameConn
+	pending   map[int]bool // games being pushed, true if pushed again meanwhile
 }
 
 type GameConn struct {
 	Conn      *websocket.Conn
 	GameID    int
+	Player    string
+	Observer  string // observer key id, for observers rather than players
+	HandOrder string // the player's choice of game.HandDealt or game.HandAlpha
 	WriteChan chan *game.Game
 }
 
 func NewHub() *Hub {
 	return &Hub{
-		ClientMap: make(map[int][]*GameConn),
 		Broadcast: make(chan *game.Game),
+		clientMap: make(map[int][]*GameConn),
+		pending:   make(map[int]bool),
 	}
 }
 
 func (h *Hub) Register(gc *GameConn) {
-	h.ClientMap[gc.GameID] = append(h.ClientMap[gc.GameID], gc)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clientMap[gc.GameID] = append(h.clientMap[gc.GameID], gc)
 }
 
 func (h *Hub) Unregister(gc *GameConn) {
-	if clientMap, ok := h.ClientMap[gc.GameID]; ok {
-		for i := range h.ClientMap[gc.GameID] {
-			if h.ClientMap[gc.GameID][i] == gc {
-				clientMap = append(h.ClientMap[gc.GameID][:i], h.ClientMap[gc.GameID][i+1:]...)
-			}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	clients := h.clientMap[gc.GameID]
+	for i := range clients {
+		if clients[i] == gc {
+			clients = append(clients[:i:i], clients[i+1:]...)
+			break
 		}
-		h.ClientMap[gc.GameID] = clientMap
 	}
+	if len(clients) == 0 {
+		delete(h.clientMap, gc.GameID)
+		return
+	}
+	h.clientMap[gc.GameID] = clients
+}
+
+// Clients returns a copy of the game's connections.
+func (h *Hub) Clients(gameID int) []*GameConn {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]*GameConn(nil), h.clientMap[gameID]...)
+}
+
+// Push sends g to its connected clients from outside a websocket handler.
+// It doesn't wait, since only connections' write loops receive broadcasts.
+// Each game has at most one push on its way: pushes made meanwhile are
+// folded into one more broadcast once it's received, which sends the game
+// as it is by then.
+func (h *Hub) Push(g *game.Game) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.clientMap[g.ID]) == 0 {
+		return
+	}
+	if _, ok := h.pending[g.ID]; ok {
+		h.pending[g.ID] = true
+		return
+	}
+	h.pending[g.ID] = false
+	go h.deliver(g)
 }
+
+func (h *Hub) deliver(g *game.Game) {
+	for {
+		h.Broadcast <- g
+		h.mu.Lock()
+		again := h.pending[g.ID]
+		if again {
+			h.pending[g.ID] = false
+		} else {
+			delete(h.pending, g.ID)
+		}
+		h.mu.Unlock()
+		if !again {
+			return
+		}
+	}
+}
+
+// send writes g to the client, as an observer sees it if it's an observer.
+func (gc *GameConn) send(g *game.Game) error {
+	if gc.Observer != "" {
+		return websocket.JSON.Send(gc.Conn, g.Observe())
+	}
+	j, err := g.ViewJSON(gc.Player, gc.HandOrder)
+	if err != nil {
+		return err
+	}
+	return rawJSON.Send(gc.Conn, j)
+}
+
+// rawJSON sends JSON that's already marshaled as websocket.JSON would.
+var rawJSON = websocket.Codec{Marshal: func(v interface{}) ([]byte, byte, error) {
+	return v.([]byte), websocket.TextFrame, nil
+}}
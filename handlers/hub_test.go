@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/game"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHubConcurrentAccess(t *testing.T) {
+	hub := NewHub()
+	g := &game.Game{ID: 7}
+	hub.Push(g)
+	select {
+	case <-hub.Broadcast:
+		t.Fatal("pushed to a game with no clients")
+	default:
+	}
+
+	kept := &GameConn{GameID: g.ID, Player: "al"}
+	hub.Register(kept)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			gc := &GameConn{GameID: g.ID, Player: "bob"}
+			hub.Register(gc)
+			hub.Push(g)
+			for _, client := range hub.Clients(g.ID) {
+				_ = client.Player
+			}
+			hub.Unregister(gc)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, []*GameConn{kept}, hub.Clients(g.ID))
+
+	// the 50 pushes come to at most two broadcasts: the one on its way and
+	// one for the pushes made while it was
+	broadcasts := 0
+	for {
+		select {
+		case received := <-hub.Broadcast:
+			assert.Equal(t, g, received)
+			broadcasts++
+			continue
+		case <-time.After(time.Millisecond * 50):
+		}
+		break
+	}
+	assert.True(t, broadcasts == 1 || broadcasts == 2, "%d broadcasts", broadcasts)
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	assert.Empty(t, hub.pending)
+}
@@ -1,17 +1,75 @@
 package handlers
 
 import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
+
+	"github.com/stinkyfingers/differencebetween/api/audit"
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stinkyfingers/differencebetween/api/metrics"
 )
 
+var errForbidden = errors.New("forbidden")
+
 func Cors(fn http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+ObserverKeyHeader)
+		w.Header().Set("Access-Control-Expose-Headers", TokenHeader+", "+ReconnectTokenHeader)
 		if r.Method == "OPTIONS" {
 			return
 		}
 		fn(w, r)
 	}
 }
+
+// Admin rejects requests that don't carry the ADMIN_SECRET as a bearer token.
+// Admin routes are disabled entirely when no secret is configured. Every
+// request is recorded in the AuditLog, except that denials from one address
+// are written at most once per deniedWindow.
+func Admin(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entry := newAuditEntry(r)
+
+		secret := config.Current().AdminSecret
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if secret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			if suppressed, ok := denials.allow(r.RemoteAddr, entry.Time); ok {
+				entry.Outcome = audit.OutcomeDenied
+				entry.Status = http.StatusForbidden
+				entry.Suppressed = suppressed
+				writeAudit(entry)
+			}
+			HTTPStatusError(w, errForbidden, http.StatusForbidden)
+			return
+		}
+		defer writeAudit(entry)
+		entry.Actor = "admin"
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		fn(sw, r.WithContext(context.WithValue(r.Context(), auditEntryKey, entry)))
+		entry.Status = sw.status
+		entry.Outcome = audit.OutcomeSuccess
+		if sw.status >= http.StatusBadRequest {
+			entry.Outcome = audit.OutcomeFailed
+		}
+	}
+}
+
+// Timed returns middleware recording request latency under the route
+// pattern, not the raw path, so ids don't multiply the histograms.
+func Timed(pattern string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(fn http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			fn(sw, r)
+			metrics.ObserveLatency(fmt.Sprintf("%s %s %dxx", r.Method, pattern, sw.status/100), time.Since(start))
+		}
+	}
+}
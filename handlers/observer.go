@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/stinkyfingers/differencebetween/api/game"
+	"golang.org/x/net/websocket"
+)
+
+// ObserverKeyHeader carries an observer key. Websockets, which can't set
+// headers, use the key query param instead.
+const ObserverKeyHeader = "X-Observer-Key"
+
+var errReadOnly = errors.New("observers can't play or vote")
+
+type observerClaims struct {
+	GameID int
+	ID     string // of the observer key
+}
+
+// ObserverAuth rejects requests without a valid observer key for the
+// route's id param. Observer keys only ever authorize ObserverAuth routes,
+// all of which are read-only.
+func ObserverAuth(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(ObserverKeyHeader)
+		if key == "" {
+			key = r.URL.Query().Get("key")
+		}
+		id, err := strconv.Atoi(r.URL.Query().Get("id"))
+		if key == "" || err != nil {
+			HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+			return
+		}
+		g, err := game.GetGame(id)
+		if err != nil {
+			HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+			return
+		}
+		observerID, ok := g.ObserverID(key)
+		if !ok {
+			HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+			return
+		}
+		claims := &observerClaims{GameID: id, ID: observerID}
+		fn(w, r.WithContext(context.WithValue(r.Context(), observerClaimsKey, claims)))
+	}
+}
+
+func observerFromContext(ctx context.Context) (*observerClaims, bool) {
+	claims, ok := ctx.Value(observerClaimsKey).(*observerClaims)
+	return claims, ok
+}
+
+// Observation returns the public view of /games/{id}/observation.
+func Observation(w http.ResponseWriter, r *http.Request) {
+	claims, ok := observerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	j, err := json.Marshal(g.Observe())
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// Observe streams the public view of the game over a websocket, as Game
+// does for players. Anything but a ping is refused.
+func Observe(ws *websocket.Conn, hub *Hub) {
+	claims, ok := observerFromContext(ws.Request().Context())
+	if !ok {
+		WSError(ws, errUnauthorized)
+		return
+	}
+	g, err := game.GetGame(claims.GameID)
+	if err != nil {
+		WSError(ws, err)
+		return
+	}
+
+	gameConn := &GameConn{
+		GameID:    claims.GameID,
+		Observer:  claims.ID,
+		Conn:      ws,
+		WriteChan: make(chan *game.Game),
+	}
+	hub.Register(gameConn)
+	go gameConn.write(hub)
+	hub.Broadcast <- g
+	defer func() {
+		hub.Unregister(gameConn)
+		ws.Close()
+	}()
+	for {
+		var p game.Play
+		err := websocket.JSON.Receive(ws, &p)
+		if err != nil {
+			return
+		}
+		if p.Ping == "" {
+			WSError(ws, errReadOnly)
+		}
+	}
+}
+
+// CreateObserver issues an observer key for /games/{id} to its host.
+func CreateObserver(w http.ResponseWriter, r *http.Request) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	key, observer, err := g.AddObserver(claims.Player)
+	if err == game.ErrNotHost {
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	j, err := json.Marshal(struct {
+		game.ObserverKey
+		Key string `json:"key"`
+	}{observer, key})
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// Observers lists /games/{id}'s observer keys for its host.
+func Observers(w http.ResponseWriter, r *http.Request) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	observers, err := g.Observers(claims.Player)
+	if err != nil {
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	}
+	j, err := json.Marshal(observers)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// RevokeObserver lets the host of /games/{id} revoke observer key {observer},
+// disconnecting any overlays using it.
+func RevokeObserver(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	claims, ok := PlayerFromContext(r.Context())
+	if !ok {
+		HTTPStatusError(w, errUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	g, ok := lookupGame(w, claims.GameID)
+	if !ok {
+		return
+	}
+	id := r.URL.Query().Get("observer")
+	err := g.RevokeObserver(claims.Player, id)
+	switch err {
+	case nil:
+	case game.ErrNotHost:
+		HTTPStatusError(w, err, http.StatusForbidden)
+		return
+	default:
+		HTTPStatusError(w, err, http.StatusNotFound)
+		return
+	}
+	for _, client := range hub.Clients(g.ID) {
+		if client.Observer == id {
+			client.Conn.Close()
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
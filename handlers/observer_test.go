@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stinkyfingers/differencebetween/api/game"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+// allowLocalFetches lets custom decks come from httptest servers for the
+// rest of the test.
+func allowLocalFetches(t *testing.T) {
+	old := config.Current()
+	cfg := *old
+	cfg.FetchAllowedHosts = []string{"127.0.0.1"}
+	config.Set(&cfg)
+	t.Cleanup(func() { config.Set(old) })
+}
+
+func newTestGame(t *testing.T) *game.Game {
+	allowLocalFetches(t)
+	var setups, punchlines strings.Builder
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(&setups, "setup%d,G\n", i)
+	}
+	for i := 0; i < 60; i++ {
+		fmt.Fprintf(&punchlines, "punchline%d,G\n", i)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/setups") {
+			w.Write([]byte(setups.String()))
+			return
+		}
+		w.Write([]byte(punchlines.String()))
+	}))
+	t.Cleanup(server.Close)
+	source := game.NewHTTPCardSource(server.URL+"/setups.csv", server.URL+"/punchlines.csv")
+	g, err := game.NewGame(context.Background(), game.Player{Name: "al"}, 3, "R", game.WithCardSource(source), game.WithMinPlayers(1))
+	if err == nil {
+		err = g.Start("al", false)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestObserverKeyIsReadOnly(t *testing.T) {
+	g := newTestGame(t)
+	defer game.Delete(g.ID)
+	key, _, err := g.AddObserver("al")
+	assert.NoError(t, err)
+	id := strconv.Itoa(g.ID)
+	hub := NewHub()
+
+	w := httptest.NewRecorder()
+	ObserverAuth(Observation)(w, httptest.NewRequest("GET", "/games/"+id+"/observation?id="+id+"&key="+key, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), string(g.Players[0].Punchlines[0]))
+
+	mutations := map[string]http.HandlerFunc{
+		"comment": func(w http.ResponseWriter, r *http.Request) { CommentRound(w, r, hub) },
+		"timers":  func(w http.ResponseWriter, r *http.Request) { SetTimers(w, r, hub) },
+		"create":  CreateObserver,
+		"revoke":  func(w http.ResponseWriter, r *http.Request) { RevokeObserver(w, r, hub) },
+		"play": websocket.Handler(func(ws *websocket.Conn) {
+			Game(ws, hub)
+		}).ServeHTTP,
+	}
+	for name, handler := range mutations {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/games/"+id+"?id="+id+"&n=1&token="+key, strings.NewReader(`{"comment":"hi","playSeconds":30}`))
+		r.Header.Set("Authorization", "Bearer "+key)
+		PlayerAuth(handler)(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, name)
+	}
+	for name, handler := range map[string]http.HandlerFunc{"import": ImportCAH, "reload": Reload, "delete": DeleteGame} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/admin?id="+id, nil)
+		r.Header.Set("Authorization", "Bearer "+key)
+		Admin(handler)(w, r)
+		assert.Equal(t, http.StatusForbidden, w.Code, name)
+	}
+
+	// the observer websocket refuses plays
+	server := httptest.NewServer(ObserverAuth(websocket.Handler(func(ws *websocket.Conn) {
+		Observe(ws, hub)
+	}).ServeHTTP))
+	defer server.Close()
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/?id="+id+"&key="+key, "", server.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ws.Close()
+	var o game.Observation
+	assert.NoError(t, websocket.JSON.Receive(ws, &o))
+	assert.Equal(t, g.ID, o.GameID)
+	assert.NoError(t, websocket.JSON.Send(ws, game.Play{Punchline: g.Players[0].Punchlines[0]}))
+	var e Error
+	assert.NoError(t, websocket.JSON.Receive(ws, &e))
+	assert.Equal(t, errReadOnly.Error(), e.Message)
+	assert.Empty(t, g.Rounds[g.RoundsRemaining-1].Plays)
+}
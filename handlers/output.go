@@ -2,35 +2,151 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/stinkyfingers/differencebetween/api/auth"
+	"github.com/stinkyfingers/differencebetween/api/flags"
+	"github.com/stinkyfingers/differencebetween/api/game"
+	"github.com/stinkyfingers/differencebetween/api/metrics"
 	"golang.org/x/net/websocket"
 )
 
 type Error struct {
 	Message string `json:"message"`
+	Code    string `json:"code,omitempty"` // stable, for clients to match on
 }
 
+// HTTPError writes err with the status errorStatus gives it.
 func HTTPError(w http.ResponseWriter, err error) {
-	message := "unspecified error"
-	if err != nil {
-		message = err.Error()
-	}
-	e := &Error{
-		Message: message,
-	}
+	HTTPStatusError(w, err, errorStatus(err))
+}
+
+func HTTPStatusError(w http.ResponseWriter, err error, status int) {
+	e := newError(err)
+	metrics.CountError(errorCode(err))
 	j, err := json.Marshal(e)
 	if err != nil {
+		w.WriteHeader(status)
 		w.Write([]byte("error encoding error"))
+		return
 	}
 	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(status)
 	w.Write(j)
 }
 
+func newError(err error) Error {
+	e := Error{Message: "unspecified error"}
+	if err != nil {
+		e.Message = err.Error()
+	}
+	if code := errorCode(err); code != "other" {
+		e.Code = code
+	}
+	return e
+}
+
 func WSError(ws *websocket.Conn, err error) {
+	metrics.CountError(errorCode(err))
 	message := "unspecified error"
 	if err != nil {
 		message = err.Error()
 	}
 	websocket.JSON.Send(ws, Error{Message: message})
 }
+
+// errorStatus is the HTTP status for err: one the client can act on for the
+// errors a request can cause, and 500 for anything else.
+func errorStatus(err error) int {
+	if errors.Is(err, game.ErrInvalidRounds) || errors.Is(err, game.ErrInvalidSnapshot) || errors.Is(err, game.ErrTooManyPlayers) {
+		return http.StatusBadRequest
+	}
+	switch err {
+	case game.ErrGameNotFound, game.ErrPlayerNotFound, game.ErrRoundNotFound, game.ErrNoWebhook:
+		return http.StatusNotFound
+	case game.ErrGameGone:
+		return http.StatusGone
+	case errUnauthorized, auth.ErrInvalidToken, auth.ErrExpiredToken, game.ErrWrongReconnectToken:
+		return http.StatusUnauthorized
+	case errForbidden, game.ErrNotHost, game.ErrWrongPIN, game.ErrSpectator, game.ErrJudge, game.ErrNotJudge,
+		game.ErrNotRoundWinner, game.ErrNotInSuddenDeath, flags.ErrDisabled:
+		return http.StatusForbidden
+	case game.ErrGameExists, game.ErrGameFull, game.ErrNameTaken, game.ErrGameFinished, game.ErrNotFinished,
+		game.ErrNotInLobby, game.ErrNotEnoughPlayers, game.ErrPlayersNotReady, game.ErrMidRound, game.ErrGamePaused,
+		game.ErrNotPaused, errInvalidAction, game.ErrWrongPhase, game.ErrRoundNotFinished, game.ErrCommentClosed,
+		game.ErrAlreadyPlayed, game.ErrNotPlayed, game.ErrMulliganUsed, game.ErrMulliganPlayed, game.ErrSuddenDeath,
+		game.ErrTargetScore, game.ErrNoWriteIns, game.ErrWriteInTaken, game.ErrJoinedMidRound:
+		return http.StatusConflict
+	case game.ErrTooManyActions:
+		return http.StatusTooManyRequests
+	case game.ErrDeckUnavailable:
+		return http.StatusBadGateway
+	case game.ErrNoGamesAvailable, game.ErrDecksNotLoaded:
+		return http.StatusServiceUnavailable
+	case game.ErrInvalidCode, game.ErrInvalidPIN, game.ErrInvalidTimer, game.ErrInvalidHandOrder, game.ErrInvalidHandSize,
+		game.ErrInvalidLeague, game.ErrInvalidRankCount, game.ErrInvalidVotesPerPlayer, game.ErrInvalidTargetScore,
+		game.ErrInvalidTieBreak, game.ErrInvalidWebhook, game.ErrInvalidWriteIn, game.ErrInvalidWriteIns,
+		game.ErrUnknownRating, game.ErrCardNotInHand, game.ErrOwnCard, game.ErrDuplicateVote, game.ErrVoteCount,
+		game.ErrCommentEmpty, game.ErrCommentTooLong, game.ErrMessageEmpty, game.ErrMessageTooLong:
+		return http.StatusBadRequest
+	}
+	switch errorCode(err) {
+	case "deck_load", "invalid_url", "invalid_player_name", "invalid_team", "teammate_card", "unknown_feature":
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// errorCode classifies err for the error counters.
+func errorCode(err error) string {
+	if errors.Is(err, game.ErrInvalidRounds) {
+		return "invalid_rounds"
+	}
+	if errors.Is(err, game.ErrInvalidSnapshot) {
+		return "invalid_snapshot"
+	}
+	if errors.Is(err, game.ErrTooManyPlayers) {
+		return "too_many_players"
+	}
+	switch err {
+	case game.ErrGameNotFound:
+		return "game_not_found"
+	case game.ErrGameGone:
+		return "game_gone"
+	case game.ErrGameExists:
+		return "game_exists"
+	case game.ErrTooFewSetups, game.ErrTooFewPunchlines, game.ErrMalformedCSV, game.ErrMalformedJSON,
+		game.ErrDeckTooLarge, game.ErrUnknownDeck, game.ErrTooManyRedirects, game.ErrDeckUnavailable:
+		return "deck_load"
+	case game.ErrInvalidDeckURL, game.ErrPrivateAddress:
+		return "invalid_url"
+	case errInvalidAction, game.ErrWrongPhase:
+		return "wrong_phase"
+	case game.ErrTooManyActions:
+		return "rate_limited"
+	case game.ErrGamePaused:
+		return "game_paused"
+	case errUnauthorized, auth.ErrInvalidToken, auth.ErrExpiredToken, game.ErrWrongReconnectToken:
+		return "unauthorized"
+	case errForbidden, game.ErrNotHost:
+		return "forbidden"
+	case game.ErrGameFull:
+		return "game_full"
+	case game.ErrNotEnoughPlayers:
+		return "not_enough_players"
+	case game.ErrInvalidPlayerName:
+		return "invalid_player_name"
+	case game.ErrWrongPIN:
+		return "wrong_pin"
+	case game.ErrInvalidTeam, game.ErrNoTeams:
+		return "invalid_team"
+	case game.ErrTeammateCard:
+		return "teammate_card"
+	case flags.ErrDisabled:
+		return "feature_disabled"
+	case flags.ErrUnknown:
+		return "unknown_feature"
+	}
+	return "other"
+}
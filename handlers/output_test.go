@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stinkyfingers/differencebetween/api/game"
+	"github.com/stinkyfingers/differencebetween/api/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCounters(t *testing.T) {
+	notFound := metrics.ErrorCount("game_not_found")
+	forbidden := metrics.ErrorCount("forbidden")
+	unauthorized := metrics.ErrorCount("unauthorized")
+
+	HTTPError(httptest.NewRecorder(), game.ErrGameNotFound)
+	assert.Equal(t, notFound+1, metrics.ErrorCount("game_not_found"))
+
+	w := httptest.NewRecorder()
+	Admin(Stats)(w, httptest.NewRequest("GET", "/admin/stats", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, forbidden+1, metrics.ErrorCount("forbidden"))
+
+	w = httptest.NewRecorder()
+	PlayerAuth(Status)(w, httptest.NewRequest("GET", "/play/1?token=nope", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, unauthorized+1, metrics.ErrorCount("unauthorized"))
+}
+
+func TestHTTPErrorStatus(t *testing.T) {
+	for err, status := range map[error]int{
+		game.ErrGameNotFound:     http.StatusNotFound,
+		game.ErrPlayerNotFound:   http.StatusNotFound,
+		game.ErrNotHost:          http.StatusForbidden,
+		game.ErrNameTaken:        http.StatusConflict,
+		game.ErrWrongPhase:       http.StatusConflict,
+		game.ErrInvalidTimer:     http.StatusBadRequest,
+		game.ErrTooFewSetups:     http.StatusBadRequest,
+		game.ErrDeckUnavailable:  http.StatusBadGateway,
+		errors.New("whatever"):   http.StatusInternalServerError,
+		game.ErrNoGamesAvailable: http.StatusServiceUnavailable,
+	} {
+		w := httptest.NewRecorder()
+		HTTPError(w, err)
+		assert.Equal(t, status, w.Code, err.Error())
+	}
+
+	g := newTestGame(t)
+	defer game.Delete(g.ID)
+	w := httptest.NewRecorder()
+	AddPlayer(w, httptest.NewRequest("POST", "/player", strings.NewReader(`{"player":"al","code":"`+g.Code+`"}`)), NewHub())
+	assert.Equal(t, http.StatusConflict, w.Code, "name taken")
+	w = httptest.NewRecorder()
+	AddPlayer(w, httptest.NewRequest("POST", "/player", strings.NewReader(`{"player":`)), NewHub())
+	assert.Equal(t, http.StatusBadRequest, w.Code, "malformed")
+}
@@ -1,19 +1,24 @@
 package main
 
 import (
+	"expvar"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/stinkyfingers/differencebetween/api/audit"
+	"github.com/stinkyfingers/differencebetween/api/config"
+	"github.com/stinkyfingers/differencebetween/api/flags"
+	"github.com/stinkyfingers/differencebetween/api/game"
 	"github.com/stinkyfingers/differencebetween/api/handlers"
 	"github.com/stinkyfingers/easyrouter"
 	"golang.org/x/net/websocket"
 )
 
-var (
-	port = "7777"
-)
-
 var (
 	h *handlers.Hub
 )
@@ -22,37 +27,441 @@ var routes = []easyrouter.Route{
 	{
 		Path:    "/play/{id}",
 		Methods: []string{"GET"},
-		WSHandler: websocket.Handler(func(ws *websocket.Conn) {
+		// a plain Handler rather than WSHandler so PlayerAuth runs before the handshake
+		Handler: websocket.Handler(func(ws *websocket.Conn) {
 			handlers.Game(ws, h)
-		}),
+		}).ServeHTTP,
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/observe/{id}",
+		Methods: []string{"GET"},
+		Handler: websocket.Handler(func(ws *websocket.Conn) {
+			handlers.Observe(ws, h)
+		}).ServeHTTP,
+		Middlewares: []easyrouter.Middleware{handlers.ObserverAuth},
 	},
 	{
 		Path:    "/game",
 		Methods: []string{"POST"},
 		Handler: handlers.CreateGame,
 	},
+	{
+		Path:    "/games/validate",
+		Methods: []string{"POST"},
+		Handler: handlers.ValidateGame,
+	},
+	{
+		Path:    "/games",
+		Methods: []string{"GET"},
+		Handler: handlers.Games,
+	},
+	{
+		Path:    "/games/{id}/transcript",
+		Methods: []string{"GET"},
+		Handler: handlers.Transcript,
+	},
+	{
+		Path:    "/games/{id}/recap",
+		Methods: []string{"GET"},
+		Handler: handlers.Recap,
+	},
+	{
+		Path:    "/games/{id}/history",
+		Methods: []string{"GET"},
+		Handler: handlers.History,
+	},
+	{
+		Path:    "/games/{id}/rounds/{n}/comment",
+		Methods: []string{"POST"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.CommentRound(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/messages",
+		Methods: []string{"POST"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.PostMessage(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/reactions",
+		Methods: []string{"POST"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.React(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/timers",
+		Methods: []string{"PUT"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.SetTimers(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/cleanliness",
+		Methods: []string{"PUT"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.SetCleanliness(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/player",
+		Methods: []string{"DELETE"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.Leave(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/player",
+		Methods: []string{"PATCH"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.Rename(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/rematch",
+		Methods: []string{"POST"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.Rematch(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/ready",
+		Methods: []string{"POST"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.Ready(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/start",
+		Methods: []string{"POST"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.Start(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/actions/rotate-hands",
+		Methods: []string{"POST"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.RotateHands(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/actions/pause",
+		Methods: []string{"POST"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.Pause(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/actions/resume",
+		Methods: []string{"POST"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.Resume(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/actions/skip-setup",
+		Methods: []string{"POST"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.SkipSetup(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/actions/extend",
+		Methods: []string{"POST"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.ExtendRounds(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/actions/kick",
+		Methods: []string{"POST"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.Kick(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/actions/mulligan",
+		Methods: []string{"POST"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.Mulligan(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:        "/games/{id}",
+		Methods:     []string{"GET"},
+		Handler:     handlers.GameState,
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:        "/games/{id}/pacing",
+		Methods:     []string{"GET"},
+		Handler:     handlers.Pacing,
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:        "/games/{id}/observation",
+		Methods:     []string{"GET"},
+		Handler:     handlers.Observation,
+		Middlewares: []easyrouter.Middleware{handlers.ObserverAuth},
+	},
+	{
+		Path:        "/games/{id}/observers",
+		Methods:     []string{"GET"},
+		Handler:     handlers.Observers,
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:        "/games/{id}/observers",
+		Methods:     []string{"POST"},
+		Handler:     handlers.CreateObserver,
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/games/{id}/observers/{observer}",
+		Methods: []string{"DELETE"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.RevokeObserver(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.PlayerAuth},
+	},
+	{
+		Path:    "/leagues/{slug}/games",
+		Methods: []string{"GET"},
+		Handler: handlers.LeagueGames,
+	},
+	{
+		Path:    "/leagues/{slug}/stats",
+		Methods: []string{"GET"},
+		Handler: handlers.LeagueStats,
+	},
+	{
+		Path:    "/decks/search",
+		Methods: []string{"GET"},
+		Handler: handlers.SearchDecks,
+	},
+	{
+		Path:    "/features",
+		Methods: []string{"GET"},
+		Handler: handlers.Features,
+	},
 	{
 		Path:    "/player",
 		Methods: []string{"POST"},
-		Handler: handlers.AddPlayer,
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.AddPlayer(w, r, h)
+		},
 	},
+	{
+		Path:    "/reconnect",
+		Methods: []string{"POST"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.Reconnect(w, r, h)
+		},
+	},
+	{
+		Path:    "/spectator",
+		Methods: []string{"POST"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.AddSpectator(w, r, h)
+		},
+	},
+	{
+		Path:        "/admin/import",
+		Methods:     []string{"POST"},
+		Handler:     handlers.ImportCAH,
+		Middlewares: []easyrouter.Middleware{handlers.Admin},
+	},
+	{
+		Path:        "/admin/reload",
+		Methods:     []string{"POST"},
+		Handler:     handlers.Reload,
+		Middlewares: []easyrouter.Middleware{handlers.Admin},
+	},
+	{
+		Path:        "/admin/decks/search",
+		Methods:     []string{"GET"},
+		Handler:     handlers.AdminSearchDecks,
+		Middlewares: []easyrouter.Middleware{handlers.Admin},
+	},
+	{
+		Path:        "/admin/games",
+		Methods:     []string{"GET"},
+		Handler:     handlers.AdminGames,
+		Middlewares: []easyrouter.Middleware{handlers.Admin},
+	},
+	{
+		Path:        "/admin/games/{id}",
+		Methods:     []string{"GET"},
+		Handler:     handlers.AdminGame,
+		Middlewares: []easyrouter.Middleware{handlers.Admin},
+	},
+	{
+		Path:        "/admin/games/{id}/redeliver",
+		Methods:     []string{"POST"},
+		Handler:     handlers.Redeliver,
+		Middlewares: []easyrouter.Middleware{handlers.Admin},
+	},
+	{
+		Path:        "/admin/games/{id}",
+		Methods:     []string{"DELETE"},
+		Handler:     handlers.DeleteGame,
+		Middlewares: []easyrouter.Middleware{handlers.Admin},
+	},
+	{
+		Path:    "/admin/games/{id}/restore",
+		Methods: []string{"POST"},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			handlers.RestoreGame(w, r, h)
+		},
+		Middlewares: []easyrouter.Middleware{handlers.Admin},
+	},
+	{
+		Path:        "/admin/games/{id}/snapshot",
+		Methods:     []string{"GET"},
+		Handler:     handlers.Snapshot,
+		Middlewares: []easyrouter.Middleware{handlers.Admin},
+	},
+	{
+		Path:        "/admin/snapshots",
+		Methods:     []string{"POST"},
+		Handler:     handlers.LoadSnapshot,
+		Middlewares: []easyrouter.Middleware{handlers.Admin},
+	},
+	{
+		Path:        "/admin/sessions/{id}",
+		Methods:     []string{"DELETE"},
+		Handler:     handlers.ResetSession,
+		Middlewares: []easyrouter.Middleware{handlers.Admin},
+	},
+	{
+		Path:        "/admin/stats",
+		Methods:     []string{"GET"},
+		Handler:     handlers.Stats,
+		Middlewares: []easyrouter.Middleware{handlers.Admin},
+	},
+	{
+		Path:    "/metrics",
+		Methods: []string{"GET"},
+		Handler: expvar.Handler().ServeHTTP,
+	},
+	{
+		Path:        "/admin/audit",
+		Methods:     []string{"GET"},
+		Handler:     handlers.Audit,
+		Middlewares: []easyrouter.Middleware{handlers.Admin},
+	},
+}
+
+func reloadOnHangup() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	for range hup {
+		changes, err := config.Reload()
+		if err != nil {
+			log.Print("config reload failed: ", err)
+			continue
+		}
+		log.Printf("config reloaded, %d changes", len(changes))
+		for _, change := range changes {
+			log.Print("config: ", change)
+		}
+	}
+}
+
+// purgeDeleted hard deletes soft deleted games once they can't be restored.
+func purgeDeleted() {
+	for range time.Tick(time.Minute) {
+		game.PurgeDeleted()
+	}
+}
+
+// reapExpired removes games that have outlived GAME_TTL.
+func reapExpired() {
+	for range time.Tick(time.Minute) {
+		if n := game.ReapExpired(); n > 0 {
+			log.Printf("reaped %d expired games", n)
+		}
+	}
+}
+
+// expireDeadlines times out games' play and vote phases and pushes the
+// result to their players.
+func expireDeadlines() {
+	for range time.Tick(time.Second) {
+		for _, g := range game.ExpireDeadlines() {
+			h.Push(g)
+		}
+	}
+}
+
+// dropInactive removes players who've stopped pinging and pushes the
+// result to the rest.
+func dropInactive() {
+	for range time.Tick(time.Second * 10) {
+		for _, g := range game.DropInactive() {
+			h.Push(g)
+		}
+	}
+}
+
+// timed adds latency instrumentation to each route, outermost so it covers
+// the other middlewares. The router doesn't report which route it matched,
+// so each route is given its own pattern up front. Websockets are skipped:
+// their handler runs for the life of the connection.
+func timed(routes []easyrouter.Route) []easyrouter.Route {
+	for i, route := range routes {
+		if route.WSHandler != nil || route.Path == "/play/{id}" || route.Path == "/observe/{id}" {
+			continue
+		}
+		routes[i].Middlewares = append(route.Middlewares, handlers.Timed(route.Path))
+	}
+	return routes
 }
 
 func main() {
 	fmt.Println("STARTING API")
 	h = handlers.NewHub()
 
-	if portEnv := os.Getenv("PORT"); portEnv != "" {
-		port = portEnv
-	}
+	port := config.Current().Port
 	fmt.Println("PORT: ", port)
+	fmt.Println("FEATURES: ", flags.Active())
+	if bucket := config.Current().AuditBucket; bucket != "" {
+		handlers.AuditLog = &audit.S3Log{Client: game.S3Client(), Bucket: bucket, Prefix: "audit/"}
+	}
+	go reloadOnHangup()
+	go purgeDeleted()
+	go reapExpired()
+	go expireDeadlines()
+	go dropInactive()
 	s := easyrouter.Server{
 		Port:   port,
-		Routes: routes,
+		Routes: timed(routes),
 		DefaultRoute: easyrouter.Route{
-			Path:    "/",
-			Methods: []string{"GET"},
-			Handler: handlers.Status,
+			Path:        "/",
+			Methods:     []string{"GET"},
+			Handler:     handlers.Status,
+			Middlewares: []easyrouter.Middleware{handlers.Timed("default")},
 		},
 		Middlewares: []easyrouter.Middleware{handlers.Cors},
 	}
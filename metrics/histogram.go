@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"sync"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds of the latency histograms, spanning
+// cached responses well under a millisecond to cold S3 deck fetches.
+var LatencyBuckets = []time.Duration{
+	time.Millisecond / 2,
+	time.Millisecond,
+	time.Millisecond * 5,
+	time.Millisecond * 10,
+	time.Millisecond * 25,
+	time.Millisecond * 50,
+	time.Millisecond * 100,
+	time.Millisecond * 250,
+	time.Millisecond * 500,
+	time.Second,
+	time.Second * 2,
+	time.Second * 5,
+	time.Second * 10,
+}
+
+// Latency holds a histogram per "METHOD /route/{pattern} Nxx" key.
+var Latency = expvar.NewMap("latency")
+
+// Histogram counts durations into fixed buckets. Durations over the last
+// bound fall into an overflow bucket.
+type Histogram struct {
+	mu     sync.Mutex
+	bounds []time.Duration
+	counts []int64 // len(bounds)+1
+	sum    time.Duration
+}
+
+func NewHistogram(bounds []time.Duration) *Histogram {
+	return &Histogram{bounds: bounds, counts: make([]int64, len(bounds)+1)}
+}
+
+func (h *Histogram) Observe(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	i := 0
+	for i < len(h.bounds) && d > h.bounds[i] {
+		i++
+	}
+	h.counts[i]++
+	h.sum += d
+}
+
+// Quantile returns the upper bound of the bucket holding the q quantile, or
+// -1 if it's in the overflow bucket. It's 0 when nothing was observed.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.quantile(q)
+}
+
+func (h *Histogram) quantile(q float64) time.Duration {
+	var total int64
+	for _, c := range h.counts {
+		total += c
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := int64(q*float64(total) + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+	var seen int64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= rank && i < len(h.bounds) {
+			return h.bounds[i]
+		} else if seen >= rank {
+			break
+		}
+	}
+	return -1
+}
+
+type histogramJSON struct {
+	Buckets []float64 `json:"buckets"` // upper bounds, ms
+	Counts  []int64   `json:"counts"`  // one more than buckets, the last for overflow
+	Count   int64     `json:"count"`
+	SumMS   float64   `json:"sumMs"`
+	P50MS   float64   `json:"p50Ms"`
+	P90MS   float64   `json:"p90Ms"`
+	P99MS   float64   `json:"p99Ms"`
+}
+
+// String renders the histogram as JSON, satisfying expvar.Var.
+func (h *Histogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := histogramJSON{
+		Counts: append([]int64(nil), h.counts...),
+		SumMS:  ms(h.sum),
+		P50MS:  ms(h.quantile(0.5)),
+		P90MS:  ms(h.quantile(0.9)),
+		P99MS:  ms(h.quantile(0.99)),
+	}
+	for _, b := range h.bounds {
+		out.Buckets = append(out.Buckets, ms(b))
+	}
+	for _, c := range h.counts {
+		out.Count += c
+	}
+	j, _ := json.Marshal(out)
+	return string(j)
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+var latencyMu sync.Mutex
+
+// ObserveLatency records d against the histogram for key, creating it on
+// first use.
+func ObserveLatency(key string, d time.Duration) {
+	latencyMu.Lock()
+	h, ok := Latency.Get(key).(*Histogram)
+	if !ok {
+		h = NewHistogram(LatencyBuckets)
+		Latency.Set(key, h)
+	}
+	latencyMu.Unlock()
+	h.Observe(d)
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram([]time.Duration{time.Millisecond, time.Millisecond * 10, time.Second})
+	assert.Equal(t, time.Duration(0), h.Quantile(0.5))
+
+	for i := 0; i < 90; i++ {
+		h.Observe(time.Millisecond / 2)
+	}
+	for i := 0; i < 9; i++ {
+		h.Observe(time.Millisecond * 5)
+	}
+	h.Observe(time.Second * 3)
+
+	assert.Equal(t, time.Millisecond, h.Quantile(0.5))
+	assert.Equal(t, time.Millisecond, h.Quantile(0.9))
+	assert.Equal(t, time.Millisecond*10, h.Quantile(0.95))
+	assert.Equal(t, time.Duration(-1), h.Quantile(1))
+
+	var out histogramJSON
+	assert.NoError(t, json.Unmarshal([]byte(h.String()), &out))
+	assert.Equal(t, []int64{90, 9, 0, 1}, out.Counts)
+	assert.Equal(t, int64(100), out.Count)
+	assert.Equal(t, []float64{1, 10, 1000}, out.Buckets)
+}
+
+func TestObserveLatency(t *testing.T) {
+	ObserveLatency("GET /game 2xx", time.Millisecond)
+	ObserveLatency("GET /game 2xx", time.Millisecond)
+	h, ok := Latency.Get("GET /game 2xx").(*Histogram)
+	assert.True(t, ok)
+	assert.Equal(t, time.Millisecond, h.Quantile(0.5))
+}
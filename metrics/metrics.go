@@ -0,0 +1,59 @@
+// Package metrics holds process-wide counters, published with expvar so they
+// can be scraped as JSON from the metrics endpoint.
+package metrics
+
+import "expvar"
+
+var (
+	// Errors counts failures by code, e.g. "game_not_found" or "deck_load".
+	Errors = expvar.NewMap("errors")
+
+	// Usage counts games by how they were set up, keyed by comma separated
+	// label=value pairs, e.g. "event=created,cleanliness=PG,rounds=4-6".
+	Usage = expvar.NewMap("usage")
+)
+
+func CountError(code string) {
+	Errors.Add(code, 1)
+}
+
+// ErrorCount returns how many times code has been counted.
+func ErrorCount(code string) int64 {
+	return count(Errors, code)
+}
+
+// ErrorCounts returns every error code's count.
+func ErrorCounts() map[string]int64 {
+	return counts(Errors)
+}
+
+func CountUsage(key string) {
+	Usage.Add(key, 1)
+}
+
+// UsageCount returns how many games have been counted under key.
+func UsageCount(key string) int64 {
+	return count(Usage, key)
+}
+
+// UsageCounts returns every usage key's count.
+func UsageCounts() map[string]int64 {
+	return counts(Usage)
+}
+
+func count(m *expvar.Map, key string) int64 {
+	if v, ok := m.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func counts(m *expvar.Map) map[string]int64 {
+	counts := make(map[string]int64)
+	m.Do(func(kv expvar.KeyValue) {
+		if v, ok := kv.Value.(*expvar.Int); ok {
+			counts[kv.Key] = v.Value()
+		}
+	})
+	return counts
+}
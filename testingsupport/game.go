@@ -1,6 +1,15 @@
 package testingsupport
 
 import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 )
@@ -9,8 +18,54 @@ type S3 struct {
 	s3iface.S3API
 	GetObjectOutput *s3.GetObjectOutput
 	Err             error
+	Delay           time.Duration // before GetObject returns
+	Gets            int32         // GetObject calls, updated atomically
+	Heads           int32         // HeadObject calls, updated atomically
 }
 
 func (s *S3) GetObject(*s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	atomic.AddInt32(&s.Gets, 1)
+	time.Sleep(s.Delay)
 	return s.GetObjectOutput, s.Err
 }
+
+func (s *S3) GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	return s.GetObject(input)
+}
+
+// HeadObjectWithContext reports GetObjectOutput's ETag.
+func (s *S3) HeadObjectWithContext(ctx aws.Context, input *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	atomic.AddInt32(&s.Heads, 1)
+	if s.Err != nil || s.GetObjectOutput == nil {
+		return &s3.HeadObjectOutput{}, s.Err
+	}
+	return &s3.HeadObjectOutput{ETag: s.GetObjectOutput.ETag}, nil
+}
+
+// GCS is an in-memory game.GCSClient keyed by "bucket/object".
+type GCS struct {
+	Objects     map[string][]byte
+	Generations map[string]int64
+	Reads       int
+	Err         error
+	BeforeRead  func() // if set, called as each read starts, e.g. to change an object
+}
+
+func (g *GCS) Generation(ctx context.Context, bucket, object string) (int64, error) {
+	if g.Err != nil {
+		return 0, g.Err
+	}
+	return g.Generations[bucket+"/"+object], nil
+}
+
+func (g *GCS) NewReader(ctx context.Context, bucket, object string) (io.ReadCloser, int64, error) {
+	if g.Err != nil {
+		return nil, 0, g.Err
+	}
+	if g.BeforeRead != nil {
+		g.BeforeRead()
+	}
+	g.Reads++
+	key := bucket + "/" + object
+	return ioutil.NopCloser(bytes.NewReader(g.Objects[key])), g.Generations[key], nil
+}